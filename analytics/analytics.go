@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+const (
+	// bucketSize is the resolution of the rolling windows
+	bucketSize = 5 * time.Minute
+	// maxDestinations bounds the destinations tracked per user and bucket, the rest are counted under OtherDestinations
+	maxDestinations = 1000
+	// subscriberBuffer is how many records aggregation can fall behind during bursts
+	subscriberBuffer = 4096
+
+	OtherDestinations = "(other)"
+	DefaultLimit      = 10
+)
+
+type bucket struct {
+	start     time.Time
+	users     map[string]map[string]uint64
+	outbounds map[string]uint64
+}
+
+// Aggregator counts connections per user destination and per outbound in time buckets,
+// buckets older than the retention are dropped.
+type Aggregator struct {
+	retention time.Duration
+	salt      []byte
+	buckets   []*bucket
+	now       func() time.Time
+	mu        sync.RWMutex
+}
+
+// New creates an aggregator keeping retention of history, destinations are hashed with salt when it is not empty.
+func New(retention time.Duration, salt []byte) *Aggregator {
+	return &Aggregator{
+		retention: retention,
+		salt:      salt,
+		now:       time.Now,
+	}
+}
+
+// Hashed reports whether destinations are stored hashed.
+func (a *Aggregator) Hashed() bool {
+	return len(a.salt) > 0
+}
+
+// HashDestination returns the form a destination is stored in, the panel can hash a reported host the same way to look it up.
+func (a *Aggregator) HashDestination(destination string) string {
+	if !a.Hashed() {
+		return destination
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(destination))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// Add counts a connection record.
+func (a *Aggregator) Add(record *common.AccessLog) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b := a.currentLocked()
+	if outbound := record.GetOutbound(); outbound != "" {
+		b.outbounds[outbound]++
+	}
+
+	email := record.GetEmail()
+	if email == "" || record.GetDestinationHost() == "" {
+		return
+	}
+	destinations, ok := b.users[email]
+	if !ok {
+		destinations = make(map[string]uint64)
+		b.users[email] = destinations
+	}
+
+	destination := a.HashDestination(record.GetDestinationHost())
+	if _, tracked := destinations[destination]; !tracked && len(destinations) >= maxDestinations {
+		destination = OtherDestinations
+	}
+	destinations[destination]++
+}
+
+// currentLocked returns the bucket of now, creating it and dropping expired buckets as needed.
+func (a *Aggregator) currentLocked() *bucket {
+	now := a.now()
+	start := now.Truncate(bucketSize)
+	if n := len(a.buckets); n > 0 && a.buckets[n-1].start.Equal(start) {
+		return a.buckets[n-1]
+	}
+
+	expired := 0
+	for expired < len(a.buckets) && now.Sub(a.buckets[expired].start) >= a.retention+bucketSize {
+		expired++
+	}
+	a.buckets = append(a.buckets[expired:], &bucket{
+		start:     start,
+		users:     make(map[string]map[string]uint64),
+		outbounds: make(map[string]uint64),
+	})
+	return a.buckets[len(a.buckets)-1]
+}
+
+// Stats returns the top limit destinations of each user, or only of email when it is set,
+// and the outbound hits over the last window, a zero window covers the whole retention.
+func (a *Aggregator) Stats(email string, window time.Duration, limit int) *common.DestinationStatsResponse {
+	if window <= 0 || window > a.retention {
+		window = a.retention
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	now := a.now()
+	users := make(map[string]map[string]uint64)
+	outbounds := make(map[string]uint64)
+	since := now
+	for _, b := range a.buckets {
+		// A bucket counts when any part of it is inside the window
+		if now.Sub(b.start) >= window+bucketSize {
+			continue
+		}
+		if b.start.Before(since) {
+			since = b.start
+		}
+
+		for user, destinations := range b.users {
+			if email != "" && user != email {
+				continue
+			}
+			merged, ok := users[user]
+			if !ok {
+				merged = make(map[string]uint64)
+				users[user] = merged
+			}
+			for destination, hits := range destinations {
+				merged[destination] += hits
+			}
+		}
+		for outbound, hits := range b.outbounds {
+			outbounds[outbound] += hits
+		}
+	}
+
+	response := &common.DestinationStatsResponse{Since: since.Unix(), Hashed: a.Hashed()}
+	for user, destinations := range users {
+		stats := &common.UserDestinationStats{Email: user}
+		for destination, hits := range destinations {
+			stats.Hits += hits
+			stats.Destinations = append(stats.Destinations, &common.DestinationStat{Destination: destination, Hits: hits})
+		}
+		sortDestinations(stats.Destinations)
+		if len(stats.Destinations) > limit {
+			stats.Destinations = stats.Destinations[:limit]
+		}
+		response.Users = append(response.Users, stats)
+	}
+	sort.Slice(response.Users, func(i, j int) bool {
+		return response.Users[i].GetEmail() < response.Users[j].GetEmail()
+	})
+
+	for outbound, hits := range outbounds {
+		response.Outbounds = append(response.Outbounds, &common.OutboundStat{Outbound: outbound, Hits: hits})
+	}
+	sort.Slice(response.Outbounds, func(i, j int) bool {
+		if response.Outbounds[i].GetHits() != response.Outbounds[j].GetHits() {
+			return response.Outbounds[i].GetHits() > response.Outbounds[j].GetHits()
+		}
+		return response.Outbounds[i].GetOutbound() < response.Outbounds[j].GetOutbound()
+	})
+	return response
+}
+
+// sortDestinations orders by hits, most first, ties by name so results are stable.
+func sortDestinations(destinations []*common.DestinationStat) {
+	sort.Slice(destinations, func(i, j int) bool {
+		if destinations[i].GetHits() != destinations[j].GetHits() {
+			return destinations[i].GetHits() > destinations[j].GetHits()
+		}
+		return destinations[i].GetDestination() < destinations[j].GetDestination()
+	})
+}
+
+// Consume counts the connection records of the hub until ctx is done.
+func (a *Aggregator) Consume(ctx context.Context, hub *nodeLogger.Hub) {
+	_, sub := hub.Subscribe(subscriberBuffer, nodeLogger.Replay{}, &nodeLogger.Filter{Records: true})
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			a.Add(line.Record)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func record(email, host, outbound string) *common.AccessLog {
+	return &common.AccessLog{Email: email, DestinationHost: host, Outbound: outbound}
+}
+
+func newTestAggregator(retention time.Duration, salt []byte) (*Aggregator, *time.Time) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := New(retention, salt)
+	a.now = func() time.Time { return now }
+	return a, &now
+}
+
+func TestStatsTopDestinations(t *testing.T) {
+	a, _ := newTestAggregator(time.Hour, nil)
+	for i := 0; i < 3; i++ {
+		a.Add(record("alice", "google.com", "direct"))
+	}
+	a.Add(record("alice", "example.com", "direct"))
+	a.Add(record("alice", "example.com", "proxy"))
+	a.Add(record("alice", "abc.com", "proxy"))
+	a.Add(record("bob", "google.com", "block"))
+	a.Add(record("", "dns.google", "direct"))
+
+	stats := a.Stats("", 0, 2)
+	if len(stats.GetUsers()) != 2 {
+		t.Fatalf("users = %v, want alice and bob", stats.GetUsers())
+	}
+
+	alice := stats.GetUsers()[0]
+	if alice.GetEmail() != "alice" || alice.GetHits() != 6 {
+		t.Fatalf("alice = %v, want 6 hits", alice)
+	}
+	got := fmt.Sprint(alice.GetDestinations())
+	want := fmt.Sprint([]*common.DestinationStat{
+		{Destination: "google.com", Hits: 3},
+		{Destination: "example.com", Hits: 2},
+	})
+	if got != want {
+		t.Errorf("alice destinations = %s, want %s", got, want)
+	}
+
+	outbounds := fmt.Sprint(stats.GetOutbounds())
+	wantOutbounds := fmt.Sprint([]*common.OutboundStat{
+		{Outbound: "direct", Hits: 5},
+		{Outbound: "proxy", Hits: 2},
+		{Outbound: "block", Hits: 1},
+	})
+	if outbounds != wantOutbounds {
+		t.Errorf("outbounds = %s, want %s", outbounds, wantOutbounds)
+	}
+
+	if only := a.Stats("bob", 0, 0); len(only.GetUsers()) != 1 || only.GetUsers()[0].GetEmail() != "bob" {
+		t.Errorf("stats of bob = %v", only.GetUsers())
+	}
+}
+
+func TestStatsWindowAndRetention(t *testing.T) {
+	a, now := newTestAggregator(time.Hour, nil)
+	a.Add(record("alice", "old.com", "direct"))
+
+	*now = now.Add(30 * time.Minute)
+	a.Add(record("alice", "new.com", "direct"))
+
+	if got := a.Stats("alice", 10*time.Minute, 0).GetUsers()[0].GetDestinations(); len(got) != 1 || got[0].GetDestination() != "new.com" {
+		t.Errorf("10 minute window = %v, want only new.com", got)
+	}
+	if got := a.Stats("alice", 0, 0).GetUsers()[0].GetHits(); got != 2 {
+		t.Errorf("hits over the retention = %d, want 2", got)
+	}
+
+	// Adding past the retention drops the first bucket
+	*now = now.Add(45 * time.Minute)
+	a.Add(record("alice", "newer.com", "direct"))
+	if got := a.Stats("alice", 0, 0).GetUsers()[0].GetHits(); got != 2 {
+		t.Errorf("hits after expiry = %d, want 2", got)
+	}
+	if len(a.buckets) != 2 {
+		t.Errorf("buckets = %d, want 2", len(a.buckets))
+	}
+}
+
+func TestHashedDestinations(t *testing.T) {
+	a, _ := newTestAggregator(time.Hour, []byte("salt"))
+	a.Add(record("alice", "google.com", "direct"))
+
+	stats := a.Stats("alice", 0, 0)
+	if !stats.GetHashed() {
+		t.Error("response is not marked hashed")
+	}
+	destination := stats.GetUsers()[0].GetDestinations()[0].GetDestination()
+	if destination == "google.com" || len(destination) != 32 {
+		t.Errorf("destination = %q, want a 32 digit hash", destination)
+	}
+	if destination != a.HashDestination("google.com") {
+		t.Errorf("destination = %q, want HashDestination of the host", destination)
+	}
+}
+
+func TestDestinationLimit(t *testing.T) {
+	a, _ := newTestAggregator(time.Hour, nil)
+	for i := 0; i < maxDestinations+5; i++ {
+		a.Add(record("alice", fmt.Sprintf("host%d.com", i), "direct"))
+	}
+
+	stats := a.Stats("alice", 0, maxDestinations+10).GetUsers()[0]
+	if len(stats.GetDestinations()) != maxDestinations+1 {
+		t.Fatalf("destinations = %d, want %d", len(stats.GetDestinations()), maxDestinations+1)
+	}
+	if top := stats.GetDestinations()[0]; top.GetDestination() != OtherDestinations || top.GetHits() != 5 {
+		t.Errorf("top destination = %v, want %s with 5 hits", top, OtherDestinations)
+	}
+}
+
+func TestConsume(t *testing.T) {
+	a := New(time.Hour, nil)
+	hub := nodeLogger.NewHub(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Consume(ctx, hub)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(a.Stats("", 0, 0).GetUsers()) == 0 && time.Now().Before(deadline) {
+		hub.Publish(nodeLogger.Line{Text: "not a record"})
+		hub.Publish(nodeLogger.Line{Access: true, Record: record("alice", "google.com", "direct")})
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if users := a.Stats("", 0, 0).GetUsers(); len(users) != 1 || users[0].GetEmail() != "alice" {
+		t.Errorf("users = %v, want alice", users)
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 
 	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
 )
 
 type Backend interface {
 	Started() bool
 	Version() string
-	Logs() chan string
+	Logs() *nodeLogger.Hub
 	Restart() error
+	// Restarts returns the automatic restarts of the core and whether they were stopped
+	Restarts() (uint32, common.BreakerState)
 	Shutdown()
 	SyncUser(context.Context, *common.User) error
 	SyncUsers(context.Context, []*common.User) error
@@ -20,6 +23,48 @@ type Backend interface {
 	GetUserOnlineIpListStats(context.Context, string) (*common.StatsOnlineIpListResponse, error)
 }
 
+// UserPatcher is implemented by backends that sync single users without restarting the core,
+// a few changed users are then patched in instead of syncing all of them.
+type UserPatcher interface {
+	PatchUsers(context.Context, []*common.User) error
+}
+
+// InboundManager is implemented by backends that add and remove inbounds without restarting the core.
+type InboundManager interface {
+	ListInbounds(context.Context) (*common.InboundsResponse, error)
+	// AddInbound takes the JSON of an inbound like in the core config
+	AddInbound(context.Context, string) error
+	RemoveInbound(context.Context, string) error
+}
+
+// OutboundManager is implemented by backends that change outbounds without restarting the core.
+type OutboundManager interface {
+	ListOutbounds(context.Context) (*common.OutboundsResponse, error)
+	// AddOutbound and ReplaceOutbound take the JSON of an outbound like in the core config
+	AddOutbound(context.Context, string) error
+	ReplaceOutbound(context.Context, string) error
+	RemoveOutbound(context.Context, string) error
+}
+
+// BlocklistManager is implemented by backends that apply new blocklists to the running core.
+type BlocklistManager interface {
+	SetBlocklists(context.Context, []*common.Blocklist) error
+}
+
+// RoutingManager is implemented by backends that change routing rules and balancers without restarting the core.
+type RoutingManager interface {
+	ListRouting(context.Context) (*common.RoutingResponse, error)
+	// AddRoutingRule and AddBalancer take the JSON of a rule or balancer like in the core config,
+	// a rule added first is matched before the other rules
+	AddRoutingRule(ctx context.Context, config string, first bool) error
+	RemoveRoutingRule(context.Context, string) error
+	AddBalancer(context.Context, string) error
+	RemoveBalancer(context.Context, string) error
+}
+
 type ConfigKey struct{}
 
 type UsersKey struct{}
+
+// BlocklistsKey holds the blocklists a new backend starts with.
+type BlocklistsKey struct{}
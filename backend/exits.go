@@ -0,0 +1,79 @@
+package backend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+const (
+	// maxCoreExits bounds the exit history, the oldest exits are forgotten first
+	maxCoreExits = 50
+	// exitLogLines is how many of the last lines of a process are kept with its exit
+	exitLogLines = 50
+)
+
+var logger = nodeLogger.For(nodeLogger.ComponentController)
+
+var exits struct {
+	history []*common.CoreExit
+	mu      sync.Mutex
+}
+
+// ConfigHash identifies the config a core runs with.
+func ConfigHash(config []byte) string {
+	sum := sha256.Sum256(config)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewCoreExit describes how a process that was started at started ended,
+// with the last lines it printed to logs.
+func NewCoreExit(backendName, version string, started time.Time, state *os.ProcessState, logs *nodeLogger.Hub) *common.CoreExit {
+	exit := &common.CoreExit{
+		Backend:   backendName,
+		Version:   version,
+		Pid:       uint32(state.Pid()),
+		StartedAt: started.UnixMilli(),
+		ExitedAt:  time.Now().UnixMilli(),
+		ExitCode:  int32(state.ExitCode()),
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		exit.Signal = status.Signal().String()
+	}
+
+	lines, sub := logs.Subscribe(0, nodeLogger.Replay{Tail: exitLogLines, Since: started}, nil)
+	sub.Close()
+	for _, line := range lines {
+		exit.LastLines = append(exit.LastLines, line.Text)
+	}
+	return exit
+}
+
+// RecordExit adds an exit to the history, unexpected exits are logged as crashes.
+func RecordExit(exit *common.CoreExit) {
+	if !exit.GetExpected() {
+		logger.Warn("core exited unexpectedly", "backend", exit.GetBackend(), "pid", exit.GetPid(),
+			"exit_code", exit.GetExitCode(), "signal", exit.GetSignal(),
+			"runtime", time.Duration(exit.GetExitedAt()-exit.GetStartedAt())*time.Millisecond)
+	}
+
+	exits.mu.Lock()
+	defer exits.mu.Unlock()
+	if len(exits.history) >= maxCoreExits {
+		exits.history = append(exits.history[:0], exits.history[1:]...)
+	}
+	exits.history = append(exits.history, exit)
+}
+
+// CoreExits returns the recorded exits, oldest first.
+func CoreExits() []*common.CoreExit {
+	exits.mu.Lock()
+	defer exits.mu.Unlock()
+	return append([]*common.CoreExit(nil), exits.history...)
+}
@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"bufio"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func runProcess(t *testing.T, script string, kill bool) (*exec.Cmd, *nodeLogger.Hub, time.Time) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	hub := nodeLogger.NewHub(10)
+	cmd := exec.Command("sh", "-c", script)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now()
+	if err = cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	if kill {
+		// Wait for the first line so the process is running
+		scanner.Scan()
+		hub.Publish(nodeLogger.Line{Text: scanner.Text()})
+		_ = cmd.Process.Kill()
+	}
+	for scanner.Scan() {
+		hub.Publish(nodeLogger.Line{Text: scanner.Text()})
+	}
+	_ = cmd.Wait()
+	return cmd, hub, started
+}
+
+func TestNewCoreExit(t *testing.T) {
+	cmd, hub, started := runProcess(t, "echo one; echo two; echo three; exit 3", false)
+
+	exit := NewCoreExit("xray", "1.8.0", started, cmd.ProcessState, hub)
+	if exit.GetExitCode() != 3 || exit.GetSignal() != "" {
+		t.Errorf("exit code %d signal %q, want 3 and no signal", exit.GetExitCode(), exit.GetSignal())
+	}
+	if exit.GetPid() != uint32(cmd.Process.Pid) || exit.GetBackend() != "xray" || exit.GetVersion() != "1.8.0" {
+		t.Errorf("unexpected exit %v", exit)
+	}
+	if exit.GetExitedAt() < exit.GetStartedAt() {
+		t.Errorf("exited at %d before start %d", exit.GetExitedAt(), exit.GetStartedAt())
+	}
+	if len(exit.GetLastLines()) != 3 || exit.GetLastLines()[2] != "three" {
+		t.Errorf("last lines = %v", exit.GetLastLines())
+	}
+}
+
+func TestNewCoreExit_Signal(t *testing.T) {
+	cmd, hub, started := runProcess(t, "echo ready; exec sleep 10", true)
+
+	exit := NewCoreExit("sing-box", "1.11.0", started, cmd.ProcessState, hub)
+	if exit.GetExitCode() != -1 || exit.GetSignal() != "killed" {
+		t.Errorf("exit code %d signal %q, want -1 and killed", exit.GetExitCode(), exit.GetSignal())
+	}
+}
+
+func TestRecordExit(t *testing.T) {
+	for i := 0; i < maxCoreExits+5; i++ {
+		RecordExit(&common.CoreExit{Backend: "xray", Pid: uint32(i), Expected: true})
+	}
+
+	history := CoreExits()
+	if len(history) != maxCoreExits {
+		t.Fatalf("history has %d exits, want %d", len(history), maxCoreExits)
+	}
+	if history[0].GetPid() != 5 || history[len(history)-1].GetPid() != maxCoreExits+4 {
+		t.Errorf("history spans pids %d to %d", history[0].GetPid(), history[len(history)-1].GetPid())
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	if ConfigHash([]byte("a")) == ConfigHash([]byte("b")) || len(ConfigHash(nil)) != 64 {
+		t.Error("config hashes must be distinct sha256 hex digests")
+	}
+}
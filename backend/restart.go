@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	restartMinBackoff = time.Second
+	restartMaxBackoff = time.Minute
+)
+
+// Breaker paces the automatic restarts of a core. Failed health checks back off exponentially with jitter,
+// and once too many fail within the window the breaker opens and restarts stop.
+// With a cooldown a single restart is tried again after it, the half open state, otherwise the breaker
+// stays open until the backend is started again.
+type Breaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	failures []time.Time
+	backoff  *tools.Backoff
+	restarts uint32
+	state    common.BreakerState
+	openedAt time.Time
+	mu       sync.Mutex
+}
+
+func NewBreaker(threshold int, window, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		backoff:   tools.NewBackoff(restartMinBackoff, restartMaxBackoff),
+	}
+}
+
+// NewBreakerFromConfig creates a breaker with the restart settings of the config.
+func NewBreakerFromConfig(cfg *config.Config) *Breaker {
+	return NewBreaker(cfg.RestartMaxFailures,
+		time.Duration(cfg.RestartWindow)*time.Second,
+		time.Duration(cfg.RestartCooldown)*time.Second)
+}
+
+// Failed records a failed health check at now. It returns how long to wait before restarting,
+// ok is false while the breaker is open and opened reports whether this failure opened it.
+func (b *Breaker) Failed(now time.Time) (delay time.Duration, ok, opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case common.BreakerState_BREAKER_OPEN:
+		if b.cooldown <= 0 || now.Sub(b.openedAt) < b.cooldown {
+			return 0, false, false
+		}
+		b.state = common.BreakerState_BREAKER_HALF_OPEN
+		return 0, true, false
+	case common.BreakerState_BREAKER_HALF_OPEN:
+		// The trial restart did not bring the core back
+		b.state = common.BreakerState_BREAKER_OPEN
+		b.openedAt = now
+		return 0, false, true
+	}
+
+	recent := b.failures[:0]
+	for _, failure := range b.failures {
+		if now.Sub(failure) < b.window {
+			recent = append(recent, failure)
+		}
+	}
+	b.failures = append(recent, now)
+
+	if b.threshold > 0 && len(b.failures) >= b.threshold {
+		b.state = common.BreakerState_BREAKER_OPEN
+		b.openedAt = now
+		b.failures = b.failures[:0]
+		return 0, false, true
+	}
+
+	// The jitter keeps nodes sharing a fault from restarting in lockstep
+	return b.backoff.Next(), true, false
+}
+
+// Restarted counts a restart.
+func (b *Breaker) Restarted() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.restarts++
+}
+
+// Healthy records a passed health check, resetting the backoff and closing a half open breaker.
+func (b *Breaker) Healthy() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.backoff.Reset()
+	if b.state == common.BreakerState_BREAKER_HALF_OPEN {
+		b.state = common.BreakerState_BREAKER_CLOSED
+	}
+}
+
+// State returns how many restarts were made and the breaker state.
+func (b *Breaker) State() (uint32, common.BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.restarts, b.state
+}
@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestBreaker_Window(t *testing.T) {
+	start := time.Now()
+	b := NewBreaker(3, 5*time.Minute, 0)
+
+	// Failures spread wider than the window never open the breaker
+	for i := 0; i < 6; i++ {
+		if _, ok, opened := b.Failed(start.Add(time.Duration(i) * 5 * time.Minute)); !ok || opened {
+			t.Fatalf("failure %d stopped restarts", i)
+		}
+	}
+
+	b = NewBreaker(3, 5*time.Minute, 0)
+	var ok, opened bool
+	for i := 0; i < 3; i++ {
+		_, ok, opened = b.Failed(start.Add(time.Duration(i) * time.Second))
+	}
+	if ok || !opened {
+		t.Fatal("expected the third failure within the window to open the breaker")
+	}
+	if _, state := b.State(); state != common.BreakerState_BREAKER_OPEN {
+		t.Fatalf("state = %s, want open", state)
+	}
+
+	// Without a cooldown the breaker stays open
+	if _, ok, opened = b.Failed(start.Add(time.Hour)); ok || opened {
+		t.Fatal("an open breaker must not allow restarts")
+	}
+}
+
+func TestBreaker_Cooldown(t *testing.T) {
+	start := time.Now()
+	b := NewBreaker(1, time.Minute, 10*time.Minute)
+
+	if _, ok, _ := b.Failed(start); ok {
+		t.Fatal("expected the breaker to open")
+	}
+	if _, ok, _ := b.Failed(start.Add(time.Minute)); ok {
+		t.Fatal("restart allowed before the cooldown")
+	}
+
+	// A failed trial opens the breaker again
+	if delay, ok, _ := b.Failed(start.Add(10 * time.Minute)); !ok || delay != 0 {
+		t.Fatalf("expected an immediate trial restart, got %s %v", delay, ok)
+	}
+	if _, state := b.State(); state != common.BreakerState_BREAKER_HALF_OPEN {
+		t.Fatalf("state = %s, want half open", state)
+	}
+	if _, ok, opened := b.Failed(start.Add(11 * time.Minute)); ok || !opened {
+		t.Fatal("expected a failed trial to open the breaker again")
+	}
+
+	// A healthy trial closes it
+	b.Failed(start.Add(21 * time.Minute))
+	b.Healthy()
+	if _, state := b.State(); state != common.BreakerState_BREAKER_CLOSED {
+		t.Fatalf("state = %s, want closed", state)
+	}
+}
+
+func TestBreaker_Backoff(t *testing.T) {
+	start := time.Now()
+	b := NewBreaker(0, time.Minute, 0)
+
+	for i := 0; i < 10; i++ {
+		want := min(restartMinBackoff<<i, restartMaxBackoff)
+		delay, ok, _ := b.Failed(start.Add(time.Duration(i) * time.Second))
+		if !ok {
+			t.Fatal("a breaker without threshold must never open")
+		}
+		if delay < want/2 || delay >= want {
+			t.Fatalf("failure %d: delay %s outside [%s, %s)", i, delay, want/2, want)
+		}
+	}
+
+	b.Healthy()
+	if delay, _, _ := b.Failed(start.Add(time.Hour)); delay >= restartMinBackoff {
+		t.Fatalf("delay %s after a healthy check, want the minimum backoff", delay)
+	}
+
+	b.Restarted()
+	b.Restarted()
+	if restarts, _ := b.State(); restarts != 2 {
+		t.Fatalf("restarts = %d, want 2", restarts)
+	}
+}
@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"iter"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+// UserRoutes groups the emails of the users with an outbound tag by that tag, sorted so the same users
+// always give the same rules. Users without inbounds are not on the node and are left out.
+func UserRoutes(users iter.Seq[*common.User]) map[string][]string {
+	routes := make(map[string][]string)
+	for user := range users {
+		if tag := UserRoute(user); tag != "" {
+			routes[tag] = append(routes[tag], user.GetEmail())
+		}
+	}
+	for _, emails := range routes {
+		slices.Sort(emails)
+	}
+	return routes
+}
+
+// UserRoute is the tag the backend routes a user through, empty when the user is not on the node.
+func UserRoute(user *common.User) string {
+	if len(user.GetInbounds()) == 0 {
+		return ""
+	}
+	return user.GetOutboundTag()
+}
+
+// CheckUserRoutes fails with InvalidArgument on a user routed through a tag the backend does not have,
+// the core would refuse to start or send the user out through its default outbound.
+func CheckUserRoutes(users []*common.User, exists func(tag string) bool) error {
+	for _, user := range users {
+		if tag := UserRoute(user); tag != "" && !exists(tag) {
+			return status.Errorf(codes.InvalidArgument, "user %q: outbound or balancer %q not found", user.GetEmail(), tag)
+		}
+	}
+	return nil
+}
+
+// blocklistPrefix starts the tags of the outbounds the node adds for blocklists, each list gets its own
+// so the connections it blocks can be counted from the outbound of the access logs.
+const blocklistPrefix = "blocklist:"
+
+// BlocklistTag is the outbound a list blocks through.
+func BlocklistTag(name string) string {
+	return blocklistPrefix + name
+}
+
+// BlocklistName returns the list of a blocklist outbound, ok is false for other outbounds.
+func BlocklistName(tag string) (name string, ok bool) {
+	return strings.CutPrefix(tag, blocklistPrefix)
+}
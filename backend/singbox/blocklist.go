@@ -0,0 +1,97 @@
+package singbox
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+// blocklistRules sends the destinations of the lists to their block outbounds. Geosite and geoip
+// entries are the local rule sets geosite-<name> and geoip-<name>.
+func blocklistRules(lists []*common.Blocklist) []interface{} {
+	var rules []interface{}
+	for _, list := range lists {
+		tag := backend.BlocklistTag(list.GetName())
+		if domains := list.GetDomains(); len(domains) > 0 {
+			rules = append(rules, map[string]interface{}{"domain_suffix": domains, "outbound": tag})
+		}
+		if cidrs := list.GetCidrs(); len(cidrs) > 0 {
+			rules = append(rules, map[string]interface{}{"ip_cidr": cidrs, "outbound": tag})
+		}
+		if sets := blocklistRuleSetTags(list); len(sets) > 0 {
+			rules = append(rules, map[string]interface{}{"rule_set": sets, "outbound": tag})
+		}
+	}
+	return rules
+}
+
+func blocklistRuleSetTags(list *common.Blocklist) []string {
+	var tags []string
+	for _, geosite := range list.GetGeosite() {
+		tags = append(tags, "geosite-"+geosite)
+	}
+	for _, geoip := range list.GetGeoip() {
+		tags = append(tags, "geoip-"+geoip)
+	}
+	return tags
+}
+
+// blocklistRuleSets adds the rule sets the lists use to the ones of the config, read from the assets.
+func (c *Config) blocklistRuleSets(existing interface{}) []interface{} {
+	sets, _ := existing.([]interface{})
+	sets = slices.Clone(sets)
+	defined := make(map[string]struct{}, len(sets))
+	for _, set := range sets {
+		setMap, _ := set.(map[string]interface{})
+		if tag, ok := setMap["tag"].(string); ok {
+			defined[tag] = struct{}{}
+		}
+	}
+	for _, list := range c.blocklists {
+		for _, tag := range blocklistRuleSetTags(list) {
+			if _, ok := defined[tag]; ok {
+				continue
+			}
+			defined[tag] = struct{}{}
+			sets = append(sets, map[string]interface{}{
+				"tag":    tag,
+				"type":   "local",
+				"format": "binary",
+				"path":   filepath.Join(c.assetsPath, tag+".srs"),
+			})
+		}
+	}
+	return sets
+}
+
+// blocklistOutbounds appends a block outbound per list. Without outbounds in the config the first of
+// them would be the default one, a direct outbound goes first then as sing-box would use by itself.
+func blocklistOutbounds(existing interface{}, lists []*common.Blocklist) []interface{} {
+	outbounds, _ := existing.([]interface{})
+	outbounds = slices.Clone(outbounds)
+	if len(outbounds) == 0 {
+		outbounds = append(outbounds, map[string]interface{}{"type": "direct", "tag": "direct"})
+	}
+	for _, list := range lists {
+		outbounds = append(outbounds, map[string]interface{}{"type": "block", "tag": backend.BlocklistTag(list.GetName())})
+	}
+	return outbounds
+}
+
+// SetBlocklists replaces the blocklists, sing-box only reads them from its config so the core restarts.
+// When it fails to start it runs the lists it had before again.
+func (s *SingBox) SetBlocklists(ctx context.Context, lists []*common.Blocklist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.config.blocklists
+	s.config.blocklists = lists
+	if err := s.restart(ctx); err != nil {
+		s.config.blocklists = previous
+		return s.rollback(ctx, err)
+	}
+	return nil
+}
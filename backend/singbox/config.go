@@ -3,16 +3,25 @@ package singbox
 import (
 	"encoding/json"
 	"errors"
+	"maps"
+	"net"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/common"
 )
 
 type Config struct {
 	raw      map[string]interface{}
 	inbounds []*Inbound
+	// users with an outbound tag by email, routed by rules added when the config is written
+	routed     map[string]*common.User
+	blocklists []*common.Blocklist
+	// assetsPath holds the rule set files blocklists refer to
+	assetsPath string
 }
 
 type Inbound struct {
@@ -92,16 +101,165 @@ func (c *Config) syncUsers(users []*common.User) {
 		}
 		inbound.syncUsers(users)
 	}
+
+	c.routed = make(map[string]*common.User)
+	for _, user := range users {
+		if backend.UserRoute(user) != "" {
+			c.routed[user.GetEmail()] = user
+		}
+	}
 }
 
 func (c *Config) upsertUser(user *common.User) {
 	for _, inbound := range c.inbounds {
 		inbound.upsertUser(user)
 	}
+
+	if c.routed == nil {
+		c.routed = make(map[string]*common.User)
+	}
+	if backend.UserRoute(user) != "" {
+		c.routed[user.GetEmail()] = user
+	} else {
+		delete(c.routed, user.GetEmail())
+	}
+}
+
+// clone returns a copy of the config to roll back to, changes to one do not reach the other.
+func (c *Config) clone() (*Config, error) {
+	for _, inbound := range c.inbounds {
+		inbound.mu.RLock()
+		defer inbound.mu.RUnlock()
+	}
+	data, err := json.Marshal(c.raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var exclude []string
+	for _, inbound := range c.inbounds {
+		if inbound.exclude {
+			exclude = append(exclude, inbound.tag)
+		}
+	}
+	cloned, err := NewSingBoxConfig(string(data), exclude)
+	if err != nil {
+		return nil, err
+	}
+	cloned.routed = maps.Clone(c.routed)
+	cloned.blocklists = c.blocklists
+	cloned.assetsPath = c.assetsPath
+	return cloned, nil
 }
 
 func (c *Config) ToBytes() ([]byte, error) {
-	return json.MarshalIndent(c.raw, "", "    ")
+	return json.MarshalIndent(c.generated(), "", "    ")
+}
+
+// generated returns the config with what the node adds to it: a block outbound and rules per blocklist, then
+// a rule per outbound of users matching them by auth_user. The rules go after the leading rules that only sniff
+// or resolve so those still apply. The config of the panel is left untouched.
+func (c *Config) generated() map[string]interface{} {
+	routes := backend.UserRoutes(maps.Values(c.routed))
+	if len(routes) == 0 && len(c.blocklists) == 0 {
+		return c.raw
+	}
+	route, ok := c.raw["route"].(map[string]interface{})
+	if !ok {
+		if _, exists := c.raw["route"]; exists {
+			return c.raw
+		}
+		route = map[string]interface{}{}
+	}
+
+	generated := blocklistRules(c.blocklists)
+	for _, tag := range slices.Sorted(maps.Keys(routes)) {
+		generated = append(generated, map[string]interface{}{"auth_user": routes[tag], "outbound": tag})
+	}
+	rules, _ := route["rules"].([]interface{})
+	index := 0
+	for index < len(rules) && preparesConnection(rules[index]) {
+		index++
+	}
+
+	route = maps.Clone(route)
+	route["rules"] = slices.Concat(rules[:index], generated, rules[index:])
+	raw := maps.Clone(c.raw)
+	raw["route"] = route
+	if len(c.blocklists) > 0 {
+		route["rule_set"] = c.blocklistRuleSets(route["rule_set"])
+		raw["outbounds"] = blocklistOutbounds(c.raw["outbounds"], c.blocklists)
+	}
+	return raw
+}
+
+// hasOutbound reports whether rules can route to the tag, an outbound or endpoint of the config or of a
+// blocklist. Without outbounds sing-box uses a direct one.
+func (c *Config) hasOutbound(tag string) bool {
+	outbounds, _ := c.raw["outbounds"].([]interface{})
+	if len(outbounds) == 0 && tag == "direct" {
+		return true
+	}
+	endpoints, _ := c.raw["endpoints"].([]interface{})
+	for _, outbound := range slices.Concat(outbounds, endpoints) {
+		outboundMap, _ := outbound.(map[string]interface{})
+		if outboundTag, _ := outboundMap["tag"].(string); outboundTag == tag {
+			return true
+		}
+	}
+	return slices.ContainsFunc(c.blocklists, func(list *common.Blocklist) bool { return backend.BlocklistTag(list.GetName()) == tag })
+}
+
+// preparesConnection reports whether a route rule has an action that does not end the matching.
+func preparesConnection(rule interface{}) bool {
+	ruleMap, _ := rule.(map[string]interface{})
+	switch action, _ := ruleMap["action"].(string); action {
+	case "sniff", "resolve", "route-options":
+		return true
+	}
+	return false
+}
+
+// announcesStart reports whether the log config lets the "sing-box started" info line through to the
+// output the node reads, a log written to a file leaves nothing there.
+func (c *Config) announcesStart() bool {
+	logConfig, _ := c.raw["log"].(map[string]interface{})
+	if disabled, _ := logConfig["disabled"].(bool); disabled {
+		return false
+	}
+	if output, _ := logConfig["output"].(string); output != "" {
+		return false
+	}
+	level, _ := logConfig["level"].(string)
+	switch strings.ToLower(level) {
+	case "warn", "error", "fatal", "panic":
+		return false
+	}
+	return true
+}
+
+// udpInbounds only listen on UDP, they cannot be probed with a TCP dial
+var udpInbounds = []string{"hysteria", "hysteria2", "tuic", "wireguard"}
+
+// listeners returns the TCP addresses the inbounds listen on, as dialable from the node.
+func (c *Config) listeners() []string {
+	var addresses []string
+	for _, inbound := range c.inbounds {
+		inbound.mu.RLock()
+		port, _ := inbound.raw["listen_port"].(float64)
+		listen, _ := inbound.raw["listen"].(string)
+		network, _ := inbound.raw["network"].(string)
+		inbound.mu.RUnlock()
+
+		if port <= 0 || network == "udp" || slices.Contains(udpInbounds, inbound.protocol) {
+			continue
+		}
+		if ip := net.ParseIP(listen); listen == "" || (ip != nil && ip.IsUnspecified()) {
+			listen = "127.0.0.1"
+		}
+		addresses = append(addresses, net.JoinHostPort(listen, strconv.Itoa(int(port))))
+	}
+	return addresses
 }
 
 func sanitizeInboundMap(inbound map[string]interface{}) {
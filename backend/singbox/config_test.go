@@ -0,0 +1,152 @@
+package singbox
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func TestConfig_UserRoutes(t *testing.T) {
+	config, err := NewSingBoxConfig(`{
+		"inbounds": [{"type": "vless", "tag": "in"}],
+		"route": {"rules": [{"action": "sniff"}, {"protocol": "dns", "action": "hijack-dns"}]}
+	}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	premium := &common.User{Email: "premium@example.com", Inbounds: []string{"in"}, OutboundTag: "premium"}
+	config.syncUsers([]*common.User{premium, {Email: "other@example.com", Inbounds: []string{"in"}}})
+
+	rules := config.generated()["route"].(map[string]interface{})["rules"].([]interface{})
+	if len(rules) != 3 {
+		t.Fatalf("expected the user rule between the config rules, got %v", rules)
+	}
+	rule := rules[1].(map[string]interface{})
+	if rule["outbound"] != "premium" || strings.Join(rule["auth_user"].([]string), ",") != "premium@example.com" {
+		t.Fatalf("expected the user rule after the sniff rule, got %v", rules)
+	}
+	if len(config.raw["route"].(map[string]interface{})["rules"].([]interface{})) != 2 {
+		t.Fatal("the rules of the config were changed")
+	}
+
+	// Removing the user from the node drops its rule
+	config.upsertUser(&common.User{Email: premium.GetEmail(), OutboundTag: "premium"})
+	if data, err := config.ToBytes(); err != nil || strings.Contains(string(data), "auth_user") {
+		t.Fatalf("expected no user rules, got %s (%v)", data, err)
+	}
+}
+
+func TestConfig_Blocklists(t *testing.T) {
+	config, err := NewSingBoxConfig(`{
+		"inbounds": [{"type": "vless", "tag": "in"}],
+		"route": {"rules": [{"action": "sniff"}], "rule_set": [{"tag": "geoip-private", "type": "remote", "url": "https://example.com/geoip-private.srs"}]}
+	}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.assetsPath = "/assets"
+	config.blocklists = []*common.Blocklist{{Name: "ads", Domains: []string{"ads.example.com"}, Geosite: []string{"category-ads"}, Geoip: []string{"private"}}}
+	config.syncUsers([]*common.User{{Email: "premium@example.com", Inbounds: []string{"in"}, OutboundTag: "premium"}})
+
+	generated := config.generated()
+	route := generated["route"].(map[string]interface{})
+	rules := route["rules"].([]interface{})
+	if len(rules) != 4 || rules[1].(map[string]interface{})["outbound"] != "blocklist:ads" || rules[3].(map[string]interface{})["outbound"] != "premium" {
+		t.Fatalf("expected the blocklist rules before the user rule, got %v", rules)
+	}
+	if sets := rules[2].(map[string]interface{})["rule_set"].([]string); strings.Join(sets, ",") != "geosite-category-ads,geoip-private" {
+		t.Fatalf("unexpected rule sets %v", sets)
+	}
+
+	// Rule sets of the config are kept, missing ones are read from the assets
+	ruleSets := route["rule_set"].([]interface{})
+	if len(ruleSets) != 2 || ruleSets[1].(map[string]interface{})["path"] != "/assets/geosite-category-ads.srs" {
+		t.Fatalf("unexpected rule sets %v", ruleSets)
+	}
+
+	// A config without outbounds keeps direct as the default one
+	outbounds := generated["outbounds"].([]interface{})
+	if len(outbounds) != 2 || outbounds[0].(map[string]interface{})["type"] != "direct" || outbounds[1].(map[string]interface{})["tag"] != "blocklist:ads" {
+		t.Fatalf("unexpected outbounds %v", outbounds)
+	}
+}
+
+func TestConfig_Clone(t *testing.T) {
+	config, err := NewSingBoxConfig(`{"inbounds": [{"type": "vless", "tag": "in"}, {"type": "vless", "tag": "excluded"}]}`, []string{"excluded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.syncUsers([]*common.User{{Email: "a@example.com", Inbounds: []string{"in"}, Proxies: &common.Proxy{Vless: &common.Vless{Id: "a"}}}})
+
+	previous, err := config.clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.upsertUser(&common.User{Email: "b@example.com", Inbounds: []string{"in"}, OutboundTag: "premium", Proxies: &common.Proxy{Vless: &common.Vless{Id: "b"}}})
+
+	data, err := previous.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "a@example.com") || strings.Contains(string(data), "b@example.com") || len(previous.routed) != 0 {
+		t.Fatalf("changes after the clone reached it: %s", data)
+	}
+	if !previous.inbounds[1].exclude {
+		t.Fatal("the clone lost the excluded inbounds")
+	}
+}
+
+func TestSingBox_SetBlocklistsFailed(t *testing.T) {
+	sbConfig, err := NewSingBoxConfig(`{"inbounds": [{"type": "vless", "tag": "in"}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := []*common.Blocklist{{Name: "ads", Domains: []string{"ads.example.com"}}}
+	sbConfig.blocklists = previous
+
+	// A core that can not start rejects the new lists
+	dir := t.TempDir()
+	sb := &SingBox{
+		config: sbConfig,
+		cfg:    &config.Config{},
+		core:   &Core{executablePath: filepath.Join(dir, "sing-box"), configDir: dir, logs: nodeLogger.NewHub(10)},
+	}
+	if err = sb.SetBlocklists(context.Background(), []*common.Blocklist{{Name: "abuse"}}); err == nil {
+		t.Fatal("expected the lists to fail")
+	}
+	if len(sb.config.blocklists) != 1 || sb.config.blocklists[0] != previous[0] {
+		t.Fatalf("expected the previous lists to be restored, got %v", sb.config.blocklists)
+	}
+}
+
+func TestSingBox_UnknownUserRoute(t *testing.T) {
+	sbConfig, err := NewSingBoxConfig(`{"inbounds": [{"type": "vless", "tag": "in"}], "outbounds": [{"type": "direct", "tag": "direct"}, {"type": "socks", "tag": "premium"}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := &SingBox{config: sbConfig}
+
+	if !sbConfig.hasOutbound("premium") || sbConfig.hasOutbound("missing") {
+		t.Fatal("unexpected outbounds")
+	}
+	// The core would refuse a rule to an unknown outbound, the users are rejected before it restarts
+	user := &common.User{Email: "a@example.com", Inbounds: []string{"in"}, OutboundTag: "missing"}
+	if err = sb.SyncUser(context.Background(), user); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if err = sb.SyncUsers(context.Background(), []*common.User{user}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if len(sbConfig.routed) != 0 {
+		t.Fatalf("the rejected user was routed: %v", sbConfig.routed)
+	}
+}
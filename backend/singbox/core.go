@@ -6,14 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/backend/supervisor"
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
 )
 
 type Core struct {
@@ -22,11 +29,15 @@ type Core struct {
 	configDir      string
 	process        *exec.Cmd
 	restarting     bool
-	logsChan       chan string
+	logs           *nodeLogger.Hub
 	version        string
 	cancelFunc     context.CancelFunc
 	startTime      time.Time
-	mu             sync.Mutex
+	configHash     string
+	// exited is closed once the process was waited for, stopped marks exits caused by the node
+	exited  chan struct{}
+	stopped *atomic.Bool
+	mu      sync.Mutex
 }
 
 func NewSingBoxCore(executablePath, assetsPath, configDir string, logBufferSize int) (*Core, error) {
@@ -34,7 +45,7 @@ func NewSingBoxCore(executablePath, assetsPath, configDir string, logBufferSize
 		executablePath: executablePath,
 		assetsPath:     assetsPath,
 		configDir:      configDir,
-		logsChan:       make(chan string, logBufferSize),
+		logs:           nodeLogger.NewHub(logBufferSize),
 	}
 
 	version, err := core.refreshVersion()
@@ -79,12 +90,17 @@ func (c *Core) Started() bool {
 	return c.process.ProcessState == nil
 }
 
-func (c *Core) Logs() chan string {
-	return c.logsChan
+func (c *Core) Logs() *nodeLogger.Hub {
+	return c.logs
 }
 
-func (c *Core) Start(cfg *Config, _ bool) error {
+func (c *Core) Start(ctx context.Context, cfg *Config, _ bool) (err error) {
+	ctx, span := tracing.Start(ctx, "sing-box.core.Start")
+	defer func() { tracing.End(span, err) }()
+
+	_, encodeSpan := tracing.Start(ctx, "sing-box.config.ToBytes")
 	bytesConfig, err := cfg.ToBytes()
+	tracing.End(encodeSpan, err)
 	if err != nil {
 		return err
 	}
@@ -100,9 +116,14 @@ func (c *Core) Start(cfg *Config, _ bool) error {
 		return fmt.Errorf("sing-box is already running")
 	}
 
+	// A sing-box left by an earlier run keeps the inbound ports and would make this one fail
+	if err := c.cleanupOrphanedProcesses(); err != nil {
+		logger.Warn("failed to cleanup orphaned processes", "error", err)
+	}
+
 	cmd := exec.Command(c.executablePath, "run", "-c", filepath.Join(c.configDir, "sing-box.json"))
 	cmd.Env = append(os.Environ(), "SING_BOX_LOCATION_ASSET="+c.assetsPath)
-	setProcAttributes(cmd)
+	supervisor.SetProcAttributes(cmd)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -117,22 +138,47 @@ func (c *Core) Start(cfg *Config, _ bool) error {
 	if err := cmd.Start(); err != nil {
 		return err
 	}
+	pid := cmd.Process.Pid
+	supervisor.Track(pid)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	logCtx, cancel := context.WithCancel(context.Background())
 	c.cancelFunc = cancel
 	c.process = cmd
 	c.startTime = time.Now()
+	c.configHash = backend.ConfigHash(bytesConfig)
 
-	go c.captureProcessLogs(ctx, stdout)
-	go c.captureProcessLogs(ctx, stderr)
+	var captured sync.WaitGroup
+	captured.Add(2)
+	go func() {
+		defer captured.Done()
+		c.captureProcessLogs(logCtx, stdout)
+	}()
 	go func() {
-		_ = cmd.Wait()
+		defer captured.Done()
+		c.captureProcessLogs(logCtx, stderr)
+	}()
+
+	// Wait for the process to exit and record how it ended, the pipes are read to the end first since Wait closes them
+	exited, stopped := make(chan struct{}), new(atomic.Bool)
+	c.exited, c.stopped = exited, stopped
+	version, started, configHash := c.version, c.startTime, c.configHash
+	go func() {
+		defer close(exited)
+		defer supervisor.Untrack(pid)
+		captured.Wait()
+		if err := cmd.Wait(); err != nil && cmd.ProcessState == nil {
+			return
+		}
+		exit := backend.NewCoreExit("sing-box", version, started, cmd.ProcessState, c.logs)
+		exit.ConfigHash = configHash
+		exit.Expected = stopped.Load()
+		backend.RecordExit(exit)
 	}()
 
 	return nil
 }
 
-func (c *Core) Restart(cfg *Config, debug bool) error {
+func (c *Core) Restart(ctx context.Context, cfg *Config, debug bool) error {
 	c.mu.Lock()
 	if c.restarting {
 		c.mu.Unlock()
@@ -147,11 +193,14 @@ func (c *Core) Restart(cfg *Config, debug bool) error {
 		c.mu.Unlock()
 	}()
 
-	c.Stop()
-	return c.Start(cfg, debug)
+	c.Stop(ctx)
+	return c.Start(ctx, cfg, debug)
 }
 
-func (c *Core) Stop() {
+func (c *Core) Stop(ctx context.Context) {
+	_, span := tracing.Start(ctx, "sing-box.core.Stop")
+	defer span.End()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -164,23 +213,36 @@ func (c *Core) Stop() {
 		return
 	}
 
-	done := make(chan struct{})
-	go func() {
-		_ = c.process.Wait()
-		close(done)
-	}()
-
+	pid := c.process.Process.Pid
+	c.stopped.Store(true)
 	_ = c.process.Process.Kill()
 
 	select {
-	case <-done:
+	case <-c.exited:
 	case <-time.After(5 * time.Second):
-		log.Printf("sing-box process %d did not stop within timeout", c.process.Process.Pid)
+		logger.Warn("sing-box process did not stop within timeout, force killing", "pid", pid)
+		_ = supervisor.KillProcessTree(pid)
+	}
+
+	if err := supervisor.VerifyDead(pid); err != nil {
+		logger.Warn("sing-box process may still be running", "pid", pid, "error", err)
+		_ = supervisor.KillProcessTree(pid)
 	}
 
 	c.process = nil
 }
 
+// cleanupOrphanedProcesses kills sing-box processes left behind by earlier runs or by this core.
+func (c *Core) cleanupOrphanedProcesses() error {
+	killedCount, err := supervisor.CleanupOrphans("sing-box", c.executablePath, 0, logger)
+	if killedCount > 0 {
+		publishEvent(common.EventType_ORPHAN_CLEANUP, common.EventSeverity_WARNING,
+			fmt.Sprintf("cleaned up %d orphaned sing-box process(es)", killedCount),
+			map[string]string{"count": strconv.Itoa(killedCount)})
+	}
+	return err
+}
+
 func (c *Core) PID() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -190,6 +252,13 @@ func (c *Core) PID() int {
 	return c.process.Process.Pid
 }
 
+// Exited is closed once the running process exited and its output was captured.
+func (c *Core) Exited() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.exited
+}
+
 func (c *Core) StartTime() time.Time {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -212,5 +281,5 @@ func (c *Core) writeConfigFile(config []byte) error {
 }
 
 func (c *Core) captureProcessLogs(ctx context.Context, reader io.Reader) {
-	captureLogs(ctx, reader, c.logsChan)
+	captureLogs(ctx, reader, c.logs)
 }
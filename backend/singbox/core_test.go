@@ -0,0 +1,61 @@
+package singbox
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func TestCore_StartSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	sbConfig, err := NewSingBoxConfig(`{"inbounds": []}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	core := &Core{executablePath: filepath.Join(dir, "sing-box"), configDir: dir, logs: nodeLogger.NewHub(10)}
+	if err = core.Start(context.Background(), sbConfig, false); err == nil {
+		t.Fatal("expected a missing executable to fail")
+	}
+
+	// The encoding of the config is part of the start trace
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	start, encode := spans["sing-box.core.Start"], spans["sing-box.config.ToBytes"]
+	if start == nil || encode == nil {
+		t.Fatalf("expected the start and encode spans, got %v", spans)
+	}
+	if encode.Parent().SpanID() != start.SpanContext().SpanID() {
+		t.Fatal("the encode span is not a child of the start span")
+	}
+}
+
+func TestSingBox_LogsAfterShutdown(t *testing.T) {
+	hub := nodeLogger.NewHub(10)
+	sb := &SingBox{core: &Core{logs: hub}, logs: hub, cancelFunc: func() {}}
+	sb.Shutdown()
+
+	// Consumers of a backend that was just shut down still subscribe
+	logs := sb.Logs()
+	if logs != hub {
+		t.Fatal("the hub of the core was dropped on shutdown")
+	}
+	_, sub := logs.Subscribe(1, nodeLogger.Replay{}, nil)
+	sub.Close()
+}
@@ -1,15 +0,0 @@
-//go:build !windows
-
-package singbox
-
-import (
-	"os/exec"
-	"syscall"
-)
-
-func setProcAttributes(cmd *exec.Cmd) {
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-		Pgid:    0,
-	}
-}
@@ -1,15 +0,0 @@
-//go:build windows
-
-package singbox
-
-import (
-	"os/exec"
-	"syscall"
-)
-
-func setProcAttributes(cmd *exec.Cmd) {
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		CreationFlags:    syscall.CREATE_NEW_PROCESS_GROUP,
-		NoInheritHandles: false,
-	}
-}
@@ -0,0 +1,195 @@
+package singbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
+)
+
+const (
+	// healthCheckInterval is the pause between health checks of the core
+	healthCheckInterval = 5 * time.Second
+	// startupTimeout bounds the wait for sing-box to start and open its inbounds
+	startupTimeout = 10 * time.Second
+)
+
+func publishEvent(eventType common.EventType, severity common.EventSeverity, message string, details map[string]string) {
+	events.Publish(&common.Event{
+		Type:     eventType,
+		Severity: severity,
+		Backend:  "sing-box",
+		Message:  message,
+		Details:  details,
+	})
+}
+
+// fatalLogPattern matches the error sing-box exits with, with or without the timestamp:
+// FATAL[0000] start service: start inbound/vless[vless-in]: listen tcp 0.0.0.0:443: bind: address already in use
+var fatalLogPattern = regexp.MustCompile(`FATAL(?:\[\d+\])?\s+(.+)$`)
+
+// checkStartupLine reports whether the line announces sing-box started, or the error it failed to start with.
+func checkStartupLine(line string) (bool, error) {
+	if strings.Contains(line, "sing-box started") {
+		return true, nil
+	}
+	if matches := fatalLogPattern.FindStringSubmatch(line); matches != nil {
+		return false, fmt.Errorf("failed to start sing-box: %s", matches[1])
+	}
+	return false, nil
+}
+
+// checkStatus waits for sing-box to announce its start, then for its TCP inbounds to accept connections.
+// A process exiting meanwhile fails with the error it printed.
+func (s *SingBox) checkStatus(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "sing-box.core.WaitReady")
+	defer func() { tracing.End(span, err) }()
+
+	core := s.core
+	// The wait is bounded by its own timeout, not by the request that started the core
+	waitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), startupTimeout)
+	defer cancel()
+
+	// Lines printed before subscribing are replayed from the hub
+	replay, logs := core.Logs().Subscribe(nodeLogger.SubscriberBuffer, nodeLogger.Replay{Since: core.StartTime()}, nil)
+	defer logs.Close()
+
+	// Without the startup line only a fatal error or the process exit tell a failed start
+	started := !s.config.announcesStart()
+	for _, line := range replay {
+		if announced, lineErr := checkStartupLine(line.Text); lineErr != nil {
+			return lineErr
+		} else if announced {
+			started = true
+		}
+	}
+
+	for !started {
+		select {
+		case line := <-logs.C:
+			if started, err = checkStartupLine(line.Text); err != nil {
+				return err
+			}
+		case <-core.Exited():
+			return exitError(logs)
+		case <-waitCtx.Done():
+			return errors.New("failed to start sing-box: context timeout")
+		}
+	}
+
+	probeCtx, cancelProbe := context.WithCancel(waitCtx)
+	defer cancelProbe()
+	go func() {
+		select {
+		case <-core.Exited():
+			cancelProbe()
+		case <-probeCtx.Done():
+		}
+	}()
+	if err = probeListeners(probeCtx, s.listeners, true); err != nil {
+		select {
+		case <-core.Exited():
+			return exitError(logs)
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// exitError returns the fatal error among the lines left in logs, every line was captured before the exit.
+func exitError(logs *nodeLogger.Subscription) error {
+	for len(logs.C) > 0 {
+		if _, err := checkStartupLine((<-logs.C).Text); err != nil {
+			return err
+		}
+	}
+	return errors.New("failed to start sing-box: process exited")
+}
+
+// probeListeners dials every address, with retry until ctx is done it waits for them to accept connections.
+func probeListeners(ctx context.Context, addresses []string, retry bool) error {
+	var dialer net.Dialer
+	for _, address := range addresses {
+		for {
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err == nil {
+				_ = conn.Close()
+				break
+			}
+			if !retry || ctx.Err() != nil {
+				return fmt.Errorf("inbound %s is not accepting connections: %w", address, err)
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}
+	return nil
+}
+
+// live reports why sing-box is not serving. Only its process is checked, the inbounds are probed once on start:
+// sing-box logs a failed handshake for every bare connection to a TLS, REALITY or Shadowsocks inbound.
+func (s *SingBox) live() error {
+	if !s.Started() {
+		return errors.New("sing-box process exited")
+	}
+	return nil
+}
+
+// checkHealth restarts sing-box when it stops serving, paced by the restart breaker.
+func (s *SingBox) checkHealth(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(healthCheckInterval):
+		}
+
+		err := s.live()
+		if err == nil {
+			s.restarts.Healthy()
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		delay, ok, opened := s.restarts.Failed(time.Now())
+		if opened {
+			logger.Error("sing-box is crash looping, automatic restarts stopped", "error", err)
+			publishEvent(common.EventType_CORE_CRASH_LOOP, common.EventSeverity_CRITICAL,
+				"sing-box kept failing health checks, automatic restarts stopped", map[string]string{"error": err.Error()})
+		}
+		if !ok {
+			continue
+		}
+
+		logger.Warn("sing-box health check failed, restarting", "error", err, "delay", delay)
+		publishEvent(common.EventType_CORE_CRASHED, common.EventSeverity_WARNING, "sing-box health check failed", map[string]string{"error": err.Error()})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		s.restarts.Restarted()
+		if err = s.Restart(); err != nil {
+			logger.Error("failed to restart sing-box", "error", err)
+			publishEvent(common.EventType_CORE_RESTART_FAILED, common.EventSeverity_CRITICAL, err.Error(), nil)
+		} else {
+			logger.Info("sing-box restarted")
+			publishEvent(common.EventType_CORE_RESTARTED, common.EventSeverity_INFO, "sing-box restarted after failed health check", nil)
+		}
+	}
+}
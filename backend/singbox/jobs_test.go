@@ -0,0 +1,152 @@
+package singbox
+
+import (
+	"context"
+	"net"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckStartupLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		started bool
+		err     string
+	}{
+		{line: "+0000 2025-01-01 00:00:00 INFO sing-box started (0.012s)", started: true},
+		{line: "INFO[0000] sing-box started (0.012s)", started: true},
+		{line: "+0000 2025-01-01 00:00:00 INFO inbound/vless[vless-in]: tcp server started at [::]:443"},
+		{
+			line: "FATAL[0000] start service: start inbound/vless[vless-in]: listen tcp 0.0.0.0:443: bind: address already in use",
+			err:  "failed to start sing-box: start service: start inbound/vless[vless-in]: listen tcp 0.0.0.0:443: bind: address already in use",
+		},
+		{
+			line: "+0000 2025-01-01 00:00:00 FATAL decode config at sing-box.json: unknown field",
+			err:  "failed to start sing-box: decode config at sing-box.json: unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		started, err := checkStartupLine(tt.line)
+		if started != tt.started {
+			t.Errorf("checkStartupLine(%q) started = %v, want %v", tt.line, started, tt.started)
+		}
+		if (err == nil) != (tt.err == "") || (err != nil && err.Error() != tt.err) {
+			t.Errorf("checkStartupLine(%q) error = %v, want %q", tt.line, err, tt.err)
+		}
+	}
+}
+
+func TestConfigListeners(t *testing.T) {
+	cfg, err := NewSingBoxConfig(`{
+		"log": {"level": "warn"},
+		"inbounds": [
+			{"type": "vless", "tag": "vless-in", "listen": "::", "listen_port": 443},
+			{"type": "shadowsocks", "tag": "ss-in", "listen": "10.0.0.1", "listen_port": 8388},
+			{"type": "shadowsocks", "tag": "ss-udp", "listen_port": 8389, "network": "udp"},
+			{"type": "hysteria2", "tag": "hy2-in", "listen_port": 8443},
+			{"type": "tun", "tag": "tun-in"}
+		]
+	}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"127.0.0.1:443", "10.0.0.1:8388"}
+	if got := cfg.listeners(); !slices.Equal(got, want) {
+		t.Errorf("listeners = %v, want %v", got, want)
+	}
+	if cfg.announcesStart() {
+		t.Error("a warn log level hides the startup line")
+	}
+
+	cfg, _ = NewSingBoxConfig(`{"inbounds": []}`, nil)
+	if !cfg.announcesStart() {
+		t.Error("the default log level shows the startup line")
+	}
+
+	cfg, _ = NewSingBoxConfig(`{"log": {"level": "info", "output": "/var/log/sing-box.log"}, "inbounds": []}`, nil)
+	if cfg.announcesStart() {
+		t.Error("a log written to a file hides the startup line")
+	}
+}
+
+func TestProbeListeners(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := ln.Addr().String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err = probeListeners(ctx, []string{open}, false); err != nil {
+		t.Fatalf("probe of an open listener failed: %v", err)
+	}
+
+	_ = ln.Close()
+	err = probeListeners(ctx, []string{open}, false)
+	if err == nil || !strings.Contains(err.Error(), open) {
+		t.Fatalf("expected the closed listener %s to fail, got %v", open, err)
+	}
+
+	// With retry the probe waits for the listener to open
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if ln, err := net.Listen("tcp", open); err == nil {
+			defer ln.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	if err = probeListeners(ctx, []string{open}, true); err != nil {
+		t.Fatalf("probe with retry failed: %v", err)
+	}
+}
+
+func TestSingBox_LiveChecksProcessOnly(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			_ = conn.Close()
+		}
+	}()
+
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not available")
+	}
+	cmd := exec.Command(sleepPath, "30")
+	if err = cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	s := &SingBox{core: &Core{process: cmd}, listeners: []string{ln.Addr().String()}}
+
+	if err = s.live(); err != nil {
+		t.Fatalf("running process reported as not live: %v", err)
+	}
+	// The inbounds are not dialed, sing-box would log every probe
+	time.Sleep(50 * time.Millisecond)
+	if n := accepted.Load(); n != 0 {
+		t.Fatalf("liveness check opened %d connections to the inbounds", n)
+	}
+
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	if err = s.live(); err == nil {
+		t.Fatal("exited process reported as live")
+	}
+}
@@ -4,10 +4,93 @@ import (
 	"bufio"
 	"context"
 	"io"
-	"log"
+	"regexp"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tools"
 )
 
-func captureLogs(ctx context.Context, reader io.Reader, sink chan<- string) {
+var logger = nodeLogger.For(nodeLogger.ComponentSingBox)
+
+// sing-box prints a connection over lines sharing its id, the user is only there for authenticated inbounds:
+// +0000 2025-01-01 00:00:00 INFO [3017395428 0ms] inbound/vless[vless-in]: [alice] inbound connection from 1.2.3.4:51520
+// +0000 2025-01-01 00:00:00 INFO [3017395428 0ms] inbound/vless[vless-in]: [alice] inbound connection to www.google.com:443
+// +0000 2025-01-01 00:00:00 INFO [3017395428 1ms] outbound/direct[direct]: outbound connection to www.google.com:443
+// A block outbound ends the connection with "blocked connection to" instead.
+var connectionLogPattern = regexp.MustCompile(`^(?:([+-]\d{4} \d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) )?.*?\[(\d+) [^\]]*\] (?:inbound|outbound)/[\w-]+\[([^\]]+)\]: (?:\[([^\]]+)\] )?(inbound|outbound|blocked) (packet )?connection (from|to) (\S+)`)
+
+const accessTimeLayout = "-0700 2006-01-02 15:04:05"
+
+// maxPendingConnections bounds connections waiting for their outbound line, ones that never get it are forgotten
+const maxPendingConnections = 4096
+
+// accessParser assembles connection records from the lines of each connection.
+type accessParser struct {
+	pending map[string]*common.AccessLog
+}
+
+func newAccessParser() *accessParser {
+	return &accessParser{pending: make(map[string]*common.AccessLog)}
+}
+
+// parse classifies a line for log stream filters, the outbound line of a connection carries its record.
+func (p *accessParser) parse(text string) nodeLogger.Line {
+	line := nodeLogger.Line{Text: text, Level: nodeLogger.CoreLevel(text)}
+
+	matches := connectionLogPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return line
+	}
+	stamp, id, tag, user, direction, packet, preposition, addr := matches[1], matches[2], matches[3], matches[4], matches[5], matches[6], matches[7], matches[8]
+	host, port := tools.SplitHostPort(addr)
+
+	record, ok := p.pending[id]
+	if !ok && direction != "inbound" {
+		// Connections made by sing-box itself, such as DNS queries, have no inbound side
+		return line
+	}
+	if !ok {
+		if len(p.pending) >= maxPendingConnections {
+			clear(p.pending)
+		}
+		record = &common.AccessLog{Timestamp: time.Now().UnixMilli(), Network: "tcp"}
+		if at, err := time.Parse(accessTimeLayout, stamp); err == nil {
+			record.Timestamp = at.UnixMilli()
+		}
+		p.pending[id] = record
+	}
+
+	switch {
+	case direction == "inbound":
+		record.Inbound = tag
+		record.Email = user
+		if packet != "" {
+			record.Network = "udp"
+		}
+		if preposition == "from" {
+			record.SourceIp, record.SourcePort = host, port
+		} else {
+			record.DestinationHost, record.DestinationPort = host, port
+		}
+	case preposition == "to":
+		record.Outbound = tag
+		if record.DestinationHost == "" {
+			record.DestinationHost, record.DestinationPort = host, port
+		}
+		delete(p.pending, id)
+		line.Record = record
+	}
+
+	line.Access = true
+	line.Email = record.Email
+	line.Inbound = record.Inbound
+	return line
+}
+
+func captureLogs(ctx context.Context, reader io.Reader, hub *nodeLogger.Hub) {
+	parser := newAccessParser()
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -17,12 +100,7 @@ func captureLogs(ctx context.Context, reader io.Reader, sink chan<- string) {
 		default:
 		}
 
-		if sink != nil {
-			select {
-			case sink <- line:
-			default:
-			}
-		}
-		log.Println(line)
+		hub.Publish(parser.parse(line))
+		logger.Log(ctx, nodeLogger.CoreLevel(line), line)
 	}
 }
@@ -0,0 +1,87 @@
+package singbox
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestAccessParser(t *testing.T) {
+	parser := newAccessParser()
+	lines := []string{
+		"+0000 2025-01-01 10:00:00 INFO [3017395428 0ms] inbound/vless[vless-in]: [alice] inbound connection from 1.2.3.4:51520",
+		"+0000 2025-01-01 10:00:00 INFO [1111111111 0ms] inbound/shadowsocks[ss-in]: [bob] inbound packet connection from [2001:db8::1]:4000",
+		"+0000 2025-01-01 10:00:00 INFO [3017395428 0ms] inbound/vless[vless-in]: [alice] inbound connection to www.google.com:443",
+		"+0000 2025-01-01 10:00:00 INFO [2222222222 0ms] outbound/dns[dns-out]: outbound packet connection to 8.8.8.8:53",
+		"+0000 2025-01-01 10:00:01 INFO [3017395428 1ms] outbound/direct[direct]: outbound connection to www.google.com:443",
+		"+0000 2025-01-01 10:00:01 INFO [1111111111 1ms] outbound/direct[direct]: outbound packet connection to 1.1.1.1:53",
+		"+0000 2025-01-01 10:00:02 WARN router: geoip database is outdated",
+	}
+
+	var records []*common.AccessLog
+	var access int
+	for _, text := range lines {
+		line := parser.parse(text)
+		if line.Access {
+			access++
+		}
+		if line.Record != nil {
+			records = append(records, line.Record)
+		}
+	}
+
+	if access != 5 {
+		t.Errorf("expected 5 access lines, got %d", access)
+	}
+	want := []*common.AccessLog{
+		{
+			Timestamp:       time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).UnixMilli(),
+			SourceIp:        "1.2.3.4",
+			SourcePort:      51520,
+			Network:         "tcp",
+			DestinationHost: "www.google.com",
+			DestinationPort: 443,
+			Inbound:         "vless-in",
+			Outbound:        "direct",
+			Email:           "alice",
+		},
+		{
+			Timestamp:       time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).UnixMilli(),
+			SourceIp:        "2001:db8::1",
+			SourcePort:      4000,
+			Network:         "udp",
+			DestinationHost: "1.1.1.1",
+			DestinationPort: 53,
+			Inbound:         "ss-in",
+			Outbound:        "direct",
+			Email:           "bob",
+		},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %v", len(want), len(records), records)
+	}
+	for i := range want {
+		if !proto.Equal(records[i], want[i]) {
+			t.Errorf("record %d:\n got: %v\nwant: %v", i, records[i], want[i])
+		}
+	}
+	if len(parser.pending) != 0 {
+		t.Errorf("completed connections must not stay pending, got %d", len(parser.pending))
+	}
+}
+
+func TestAccessParser_Blocked(t *testing.T) {
+	parser := newAccessParser()
+	parser.parse("+0000 2025-01-01 10:00:00 INFO [3017395428 0ms] inbound/vless[vless-in]: [alice] inbound connection to ads.example.com:443")
+	line := parser.parse("+0000 2025-01-01 10:00:00 INFO [3017395428 0ms] outbound/block[blocklist:ads]: blocked connection to ads.example.com:443")
+
+	if line.Record.GetOutbound() != "blocklist:ads" || line.Record.GetEmail() != "alice" {
+		t.Fatalf("expected a record of the blocked connection, got %v", line.Record)
+	}
+	if len(parser.pending) != 0 {
+		t.Errorf("blocked connections must not stay pending, got %d", len(parser.pending))
+	}
+}
@@ -3,7 +3,7 @@ package singbox
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"path/filepath"
 	"sync"
 	"time"
@@ -11,13 +11,21 @@ import (
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
 	"github.com/shirou/gopsutil/v4/process"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SingBox struct {
 	config *Config
 	cfg    *config.Config
 	core   *Core
+	// logs is the hub of the core, kept after Shutdown drops the core so consumers subscribing late don't fail
+	logs       *nodeLogger.Hub
+	listeners  []string
+	restarts   *backend.Breaker
+	cancelFunc context.CancelFunc
 
 	mu sync.RWMutex
 }
@@ -44,6 +52,8 @@ func NewSingBox(ctx context.Context, _ int, cfg *config.Config) (*SingBox, error
 	if err != nil {
 		return nil, err
 	}
+	sbConfig.blocklists, _ = ctx.Value(backend.BlocklistsKey{}).([]*common.Blocklist)
+	sbConfig.assetsPath = assetsAbsolutePath
 
 	configAbsolutePath, err := filepath.Abs(cfg.GeneratedConfigPath)
 	if err != nil {
@@ -55,24 +65,37 @@ func NewSingBox(ctx context.Context, _ int, cfg *config.Config) (*SingBox, error
 		return nil, err
 	}
 
-	if err := core.Start(sbConfig, cfg.Debug); err != nil {
+	if err := core.Start(ctx, sbConfig, cfg.Debug); err != nil {
+		publishEvent(common.EventType_CORE_START_FAILED, common.EventSeverity_CRITICAL, err.Error(), nil)
 		return nil, err
 	}
 
+	healthCtx, cancel := context.WithCancel(context.Background())
 	sb := &SingBox{
-		config: sbConfig,
-		cfg:    cfg,
-		core:   core,
+		config:     sbConfig,
+		cfg:        cfg,
+		core:       core,
+		logs:       core.Logs(),
+		listeners:  sbConfig.listeners(),
+		restarts:   backend.NewBreakerFromConfig(cfg),
+		cancelFunc: cancel,
 	}
 
-	log.Println("sing-box backend started, version:", sb.Version())
+	if err = sb.checkStatus(ctx); err != nil {
+		publishEvent(common.EventType_CORE_START_FAILED, common.EventSeverity_CRITICAL, err.Error(), nil)
+		sb.Shutdown()
+		return nil, err
+	}
+	go sb.checkHealth(healthCtx)
+
+	logger.Info("sing-box started", "backend", "sing-box", "version", sb.Version(), "pid", core.PID())
+	publishEvent(common.EventType_CORE_STARTED, common.EventSeverity_INFO, "sing-box started", map[string]string{"version": sb.Version()})
 	return sb, nil
 }
 
-func (s *SingBox) Logs() chan string {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.core.Logs()
+// Logs returns the hub of the core, once the backend is shut down it gets no more lines.
+func (s *SingBox) Logs() *nodeLogger.Hub {
+	return s.logs
 }
 
 func (s *SingBox) Version() string {
@@ -93,6 +116,10 @@ func (s *SingBox) Started() bool {
 	return s.core.Started()
 }
 
+func (s *SingBox) Restarts() (uint32, common.BreakerState) {
+	return s.restarts.State()
+}
+
 func (s *SingBox) Restart() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -101,20 +128,43 @@ func (s *SingBox) Restart() error {
 		return errors.New("sing-box core is not initialized")
 	}
 
-	return s.core.Restart(s.config, s.cfg.Debug)
+	return s.restart(context.Background())
+}
+
+// restart restarts the core with the config and waits for it to serve, s.mu must be held.
+func (s *SingBox) restart(ctx context.Context) error {
+	if err := s.core.Restart(ctx, s.config, s.cfg.Debug); err != nil {
+		return err
+	}
+	return s.checkStatus(ctx)
+}
+
+// rollback starts the core again after a changed config failed to start, the caller restored the config
+// it ran before so the node keeps serving. cause is returned, s.mu must be held.
+func (s *SingBox) rollback(ctx context.Context, cause error) error {
+	logger.Warn("sing-box failed to restart, rolling back to the last config that ran", "error", cause)
+	if err := s.restart(ctx); err != nil {
+		return fmt.Errorf("%w, rollback failed: %v", cause, err)
+	}
+
+	publishEvent(common.EventType_CONFIG_ROLLBACK, common.EventSeverity_CRITICAL,
+		"sing-box failed to restart, rolled back to the last config that ran", map[string]string{"error": cause.Error()})
+	return cause
 }
 
 func (s *SingBox) Shutdown() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.cancelFunc()
+
 	if s.core != nil {
-		s.core.Stop()
+		s.core.Stop(context.Background())
 		s.core = nil
 	}
 }
 
-func (s *SingBox) SyncUser(_ context.Context, user *common.User) error {
+func (s *SingBox) SyncUser(ctx context.Context, user *common.User) error {
 	if user == nil {
 		return errors.New("user payload is empty")
 	}
@@ -122,16 +172,43 @@ func (s *SingBox) SyncUser(_ context.Context, user *common.User) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := backend.CheckUserRoutes([]*common.User{user}, s.config.hasOutbound); err != nil {
+		return err
+	}
+	previous, err := s.config.clone()
+	if err != nil {
+		return err
+	}
 	s.config.upsertUser(user)
-	return s.core.Restart(s.config, s.cfg.Debug)
+	if err = s.restart(ctx); err != nil {
+		s.config = previous
+		return s.rollback(ctx, err)
+	}
+	return nil
 }
 
-func (s *SingBox) SyncUsers(_ context.Context, users []*common.User) error {
+// SyncUsers restarts the core with the users, when it fails to start it runs the users it had before again.
+func (s *SingBox) SyncUsers(ctx context.Context, users []*common.User) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := backend.CheckUserRoutes(users, s.config.hasOutbound); err != nil {
+		return err
+	}
+	previous, err := s.config.clone()
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.Start(ctx, "sing-box.config.syncUsers", attribute.Int("users", len(users)))
 	s.config.syncUsers(users)
-	return s.core.Restart(s.config, s.cfg.Debug)
+	span.End()
+
+	if err = s.restart(ctx); err != nil {
+		s.config = previous
+		return s.rollback(ctx, err)
+	}
+	return nil
 }
 
 func (s *SingBox) GetSysStats(ctx context.Context) (*common.BackendStatsResponse, error) {
@@ -0,0 +1,73 @@
+package singbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+)
+
+// Validate checks the config in the context with `sing-box check` without starting it. The users are applied
+// like on start and the config is written to a temporary file, the live config file is left alone.
+func Validate(ctx context.Context, cfg *config.Config) (*common.ValidateConfigResponse, error) {
+	sbConfig, ok := ctx.Value(backend.ConfigKey{}).(*Config)
+	if !ok || sbConfig == nil {
+		return nil, errors.New("sing-box config has not been initialized")
+	}
+
+	executableAbsolutePath, err := filepath.Abs(cfg.SingBoxExecutablePath)
+	if err != nil {
+		return nil, err
+	}
+	assetsAbsolutePath, err := filepath.Abs(cfg.SingBoxAssetsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	core := &Core{executablePath: executableAbsolutePath, assetsPath: assetsAbsolutePath}
+	version, err := core.refreshVersion()
+	if err != nil {
+		return nil, err
+	}
+	response := &common.ValidateConfigResponse{CoreVersion: version}
+
+	users, _ := ctx.Value(backend.UsersKey{}).([]*common.User)
+	sbConfig.syncUsers(users)
+	sbConfig.blocklists, _ = ctx.Value(backend.BlocklistsKey{}).([]*common.Blocklist)
+	sbConfig.assetsPath = assetsAbsolutePath
+
+	bytesConfig, err := sbConfig.ToBytes()
+	if err != nil {
+		response.Errors = append(response.Errors, &common.ConfigError{Source: common.ConfigErrorSource_CONFIG_PARSE, Message: err.Error()})
+		return response, nil
+	}
+
+	file, err := os.CreateTemp("", "sing-box-check-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(bytesConfig)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response.Errors, err = backend.CheckConfig(ctx, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, core.executablePath, "check", "--disable-color", "-c", file.Name())
+		cmd.Env = append(os.Environ(), "SING_BOX_LOCATION_ASSET="+core.assetsPath)
+		return cmd
+	}, fatalLogPattern)
+	if err != nil {
+		return nil, err
+	}
+	response.Valid = len(response.Errors) == 0
+	return response, nil
+}
@@ -1,6 +1,6 @@
 //go:build !windows
 
-package xray
+package supervisor
 
 import (
 	"fmt"
@@ -14,9 +14,17 @@ import (
 	"time"
 )
 
-// findXrayProcesses finds all running xray processes by executable path
+// SetProcAttributes starts the process in its own process group so its whole tree can be killed
+func SetProcAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+		Pgid:    0,
+	}
+}
+
+// FindProcesses finds all running processes of an executable
 // Returns process information including PID, PPID, and zombie state
-func findXrayProcesses(executablePath string) ([]ProcessInfo, error) {
+func FindProcesses(executablePath string) ([]ProcessInfo, error) {
 	absPath, err := filepath.Abs(executablePath)
 	if err != nil {
 		return nil, err
@@ -24,7 +32,7 @@ func findXrayProcesses(executablePath string) ([]ProcessInfo, error) {
 
 	// Prefer /proc on Linux (works on Alpine/busybox) to avoid relying on ps flags
 	if runtime.GOOS == "linux" {
-		if procs, perr := findXrayProcessesFromProc(absPath); perr == nil && len(procs) > 0 {
+		if procs, perr := findProcessesFromProc(absPath); perr == nil && len(procs) > 0 {
 			return procs, nil
 		}
 	}
@@ -95,8 +103,8 @@ func findXrayProcesses(executablePath string) ([]ProcessInfo, error) {
 	return processes, nil
 }
 
-// findXrayProcessesFromProc scans /proc directly (Linux-only)
-func findXrayProcessesFromProc(absPath string) ([]ProcessInfo, error) {
+// findProcessesFromProc scans /proc directly (Linux-only)
+func findProcessesFromProc(absPath string) ([]ProcessInfo, error) {
 	entries, err := os.ReadDir("/proc")
 	if err != nil {
 		return nil, err
@@ -174,8 +182,8 @@ func getProcessPath(pid int) (string, error) {
 	return fields[0], nil
 }
 
-// killProcessTree kills a process and all its children on Unix
-func killProcessTree(pid int) error {
+// KillProcessTree kills a process and all its children on Unix
+func KillProcessTree(pid int) error {
 	proc, err := os.FindProcess(pid)
 	if err != nil {
 		return fmt.Errorf("failed to find process %d: %w", pid, err)
@@ -238,8 +246,8 @@ func getProcessGroupID(pid int) (int, error) {
 	return pgid, nil
 }
 
-// verifyProcessDead checks if a process is actually dead
-func verifyProcessDead(pid int) error {
+// VerifyDead checks if a process is actually dead
+func VerifyDead(pid int) error {
 	if !isProcessRunning(pid) {
 		return nil
 	}
@@ -259,7 +267,7 @@ func isProcessRunning(pid int) bool {
 }
 
 // isProcessZombie checks if a process is a zombie on Unix
-// This is already handled in findXrayProcesses by checking the state field
+// This is already handled in FindProcesses by checking the state field
 // But we provide this function for consistency
 func isProcessZombie(pid int) bool {
 	// Read from /proc/PID/stat to get process state
@@ -0,0 +1,102 @@
+//go:build !windows
+
+package supervisor
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// testExecutable copies sleep to a private path, so only the processes of the test match it.
+func testExecutable(t *testing.T) string {
+	t.Helper()
+
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not available")
+	}
+	data, err := os.ReadFile(sleepPath)
+	if err != nil {
+		t.Skip("sleep is not readable")
+	}
+	executable := filepath.Join(t.TempDir(), "core")
+	if err = os.WriteFile(executable, data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return executable
+}
+
+// startChild runs the executable as a child of the test, done is closed once it exited.
+func startChild(t *testing.T, executable string) (cmd *exec.Cmd, done chan struct{}) {
+	t.Helper()
+
+	cmd = exec.Command(executable, "30")
+	SetProcAttributes(cmd)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	done = make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-done
+	})
+	return cmd, done
+}
+
+func TestCleanupOrphans(t *testing.T) {
+	executable := testExecutable(t)
+	cmd, done := startChild(t, executable)
+	Track(cmd.Process.Pid)
+	defer Untrack(cmd.Process.Pid)
+
+	processes, err := FindProcesses(executable)
+	if err != nil {
+		t.Fatalf("failed to find processes: %v", err)
+	}
+	if len(processes) != 1 || processes[0].PID != cmd.Process.Pid || processes[0].PPID != os.Getpid() {
+		t.Fatalf("processes = %+v, want pid %d with the test as parent", processes, cmd.Process.Pid)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if killed, err := CleanupOrphans("core", executable, 0, logger); err != nil || killed != 1 {
+		t.Fatalf("killed %d processes, err %v, want 1", killed, err)
+	}
+	<-done
+
+	if err = VerifyDead(cmd.Process.Pid); err != nil {
+		t.Fatal(err)
+	}
+	if killed, _ := CleanupOrphans("core", executable, 0, logger); killed != 0 {
+		t.Fatalf("killed %d processes after cleanup", killed)
+	}
+}
+
+func TestCleanupOrphans_UntrackedChild(t *testing.T) {
+	executable := testExecutable(t)
+	core, coreDone := startChild(t, executable)
+	Track(core.Process.Pid)
+	defer Untrack(core.Process.Pid)
+	// A child that is not a core, like a config check running next to it
+	check, _ := startChild(t, executable)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if killed, err := CleanupOrphans("core", executable, 0, logger); err != nil || killed != 1 {
+		t.Fatalf("killed %d processes, err %v, want 1", killed, err)
+	}
+	<-coreDone
+
+	if err := VerifyDead(core.Process.Pid); err != nil {
+		t.Fatalf("tracked core was not reaped: %v", err)
+	}
+	if VerifyDead(check.Process.Pid) == nil {
+		t.Fatal("untracked child was killed with the core")
+	}
+}
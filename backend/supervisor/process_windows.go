@@ -1,6 +1,6 @@
 //go:build windows
 
-package xray
+package supervisor
 
 import (
 	"fmt"
@@ -13,9 +13,20 @@ import (
 	"time"
 )
 
-// findXrayProcesses finds all running xray processes by executable path
+// SetProcAttributes sets Windows-specific process attributes for proper process management
+// We use CREATE_NEW_PROCESS_GROUP to allow sending signals to the process group,
+// but we ensure proper cleanup in KillProcessTree to handle child processes
+func SetProcAttributes(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+		// NoInheritHandles ensures child processes don't inherit handles unnecessarily
+		NoInheritHandles: false,
+	}
+}
+
+// FindProcesses finds all running processes of an executable
 // Returns process information including PID, PPID, and zombie state
-func findXrayProcesses(executablePath string) ([]ProcessInfo, error) {
+func FindProcesses(executablePath string) ([]ProcessInfo, error) {
 	absPath, err := filepath.Abs(executablePath)
 	if err != nil {
 		return nil, err
@@ -58,7 +69,7 @@ func findXrayProcesses(executablePath string) ([]ProcessInfo, error) {
 			continue
 		}
 
-		// Check if this is an xray process by executable path
+		// Check if this is a process of the executable by path
 		if !strings.EqualFold(procAbsPath, absPath) {
 			// Also check by name if path doesn't match (in case of symlinks)
 			if !strings.EqualFold(filepath.Base(procAbsPath), executableName) {
@@ -113,8 +124,8 @@ func getProcessPath(pid int) (string, error) {
 	return "", fmt.Errorf("executable path not found for PID %d", pid)
 }
 
-// killProcessTree kills a process and all its children on Windows
-func killProcessTree(pid int) error {
+// KillProcessTree kills a process and all its children on Windows
+func KillProcessTree(pid int) error {
 	proc, err := os.FindProcess(pid)
 	if err != nil {
 		// Process might not exist, check if it's running
@@ -162,8 +173,8 @@ func killProcessTree(pid int) error {
 	return fmt.Errorf("process %d is still running after kill attempt", pid)
 }
 
-// verifyProcessDead checks if a process is actually dead
-func verifyProcessDead(pid int) error {
+// VerifyDead checks if a process is actually dead
+func VerifyDead(pid int) error {
 	if !isProcessRunning(pid) {
 		return nil
 	}
@@ -0,0 +1,74 @@
+// Package supervisor finds and kills core processes, including the ones left behind by earlier runs of the node.
+package supervisor
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// ProcessInfo holds information about a process
+type ProcessInfo struct {
+	PID      int
+	PPID     int
+	IsZombie bool
+}
+
+// cores holds the pids of the cores the node started. The node also runs the executable for other work,
+// like checking a config, those processes are its children as well but never orphans.
+var cores sync.Map
+
+// Track records pid as a core started by the node until Untrack is called once it exited.
+func Track(pid int) {
+	cores.Store(pid, struct{}{})
+}
+
+// Untrack forgets a core recorded with Track.
+func Untrack(pid int) {
+	cores.Delete(pid)
+}
+
+// CleanupOrphans finds and kills processes of the executable, except currentPID, that are:
+// 1. Zombie processes (orphaned from their parent)
+// 2. Cores the node started and tracked that are still running (PPID matches node PID)
+// 3. Stale processes from a previous run still holding the inbound ports
+// Other children of the node are left alone. It returns how many were killed, name is the core as shown in logs.
+func CleanupOrphans(name, executablePath string, currentPID int, logger *slog.Logger) (int, error) {
+	processes, err := FindProcesses(executablePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find %s processes: %w", name, err)
+	}
+
+	// Get current node process PID
+	nodePID := os.Getpid()
+
+	killedCount := 0
+	for _, procInfo := range processes {
+		if procInfo.PID == currentPID {
+			continue
+		}
+		if _, tracked := cores.Load(procInfo.PID); procInfo.PPID == nodePID && !tracked {
+			continue
+		}
+
+		reason := fmt.Sprintf("stale %s process from previous run", name)
+		if procInfo.IsZombie {
+			reason = fmt.Sprintf("zombie %s process", name)
+		} else if procInfo.PPID == nodePID {
+			reason = fmt.Sprintf("orphaned %s process with node as parent (PPID: %d)", name, procInfo.PPID)
+		}
+
+		logger.Warn(reason+", killing it", "pid", procInfo.PID, "ppid", procInfo.PPID)
+		if err := KillProcessTree(procInfo.PID); err != nil {
+			logger.Warn("failed to kill orphaned process", "pid", procInfo.PID, "error", err)
+		} else {
+			killedCount++
+		}
+	}
+
+	if killedCount > 0 {
+		logger.Info(fmt.Sprintf("cleaned up orphaned %s processes", name), "count", killedCount)
+	}
+	return killedCount, nil
+}
@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// checkTimeout bounds how long a core may take to check a config
+const checkTimeout = 30 * time.Second
+
+// CheckConfig runs a core in its config test mode. A rejected config is reported as the output lines
+// matching errorLine, or the whole output when none does; err is only set when the core could not be run.
+func CheckConfig(ctx context.Context, command func(ctx context.Context) *exec.Cmd, errorLine *regexp.Regexp) ([]*common.ConfigError, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	cmd := command(ctx)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if err == nil {
+		return nil, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var messages []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" && errorLine.MatchString(line) {
+			messages = append(messages, line)
+		}
+	}
+	if len(messages) == 0 {
+		messages = append(messages, strings.TrimSpace(output.String()))
+		if messages[0] == "" {
+			messages[0] = exitErr.Error()
+		}
+	}
+
+	configErrors := make([]*common.ConfigError, 0, len(messages))
+	for _, message := range messages {
+		configErrors = append(configErrors, &common.ConfigError{Source: common.ConfigErrorSource_CONFIG_CORE, Message: message})
+	}
+	return configErrors, nil
+}
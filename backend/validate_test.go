@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"testing"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestCheckConfig(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	errorLine := regexp.MustCompile(`FATAL`)
+	script := func(script string) func(context.Context) *exec.Cmd {
+		return func(ctx context.Context) *exec.Cmd { return exec.CommandContext(ctx, "sh", "-c", script) }
+	}
+
+	tests := []struct {
+		name     string
+		script   string
+		messages []string
+	}{
+		{name: "accepted", script: "echo configuration OK"},
+		{name: "matching lines", script: "echo banner; echo 'FATAL bad inbound'; echo 'FATAL bad outbound' >&2; exit 1",
+			messages: []string{"FATAL bad inbound", "FATAL bad outbound"}},
+		{name: "whole output", script: "echo something went wrong; exit 1", messages: []string{"something went wrong"}},
+		{name: "no output", script: "exit 3", messages: []string{"exit status 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configErrors, err := CheckConfig(context.Background(), script(tt.script), errorLine)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(configErrors) != len(tt.messages) {
+				t.Fatalf("got %v, want %v", configErrors, tt.messages)
+			}
+			for i, configError := range configErrors {
+				if configError.GetSource() != common.ConfigErrorSource_CONFIG_CORE || configError.GetMessage() != tt.messages[i] {
+					t.Errorf("error %d = %v, want %q", i, configError, tt.messages[i])
+				}
+			}
+		})
+	}
+
+	if _, err := CheckConfig(context.Background(), func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, "/nonexistent/core")
+	}, errorLine); err == nil {
+		t.Error("expected an error for a core that cannot run")
+	}
+}
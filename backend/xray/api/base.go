@@ -3,7 +3,9 @@ package api
 import (
 	"fmt"
 	"github.com/xtls/xray-core/app/proxyman/command"
+	routingService "github.com/xtls/xray-core/app/router/command"
 	statsService "github.com/xtls/xray-core/app/stats/command"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -11,6 +13,7 @@ import (
 type XrayHandler struct {
 	HandlerServiceClient *command.HandlerServiceClient
 	StatsServiceClient   *statsService.StatsServiceClient
+	RoutingServiceClient *routingService.RoutingServiceClient
 	GrpcClient           *grpc.ClientConn
 }
 
@@ -18,7 +21,10 @@ func NewXrayAPI(apiPort int) (*XrayHandler, error) {
 	x := &XrayHandler{}
 
 	var err error
-	x.GrpcClient, err = grpc.NewClient(fmt.Sprintf("127.0.0.1:%v", apiPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	x.GrpcClient, err = grpc.NewClient(fmt.Sprintf("127.0.0.1:%v", apiPort),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 
 	if err != nil {
 		return nil, err
@@ -28,6 +34,8 @@ func NewXrayAPI(apiPort int) (*XrayHandler, error) {
 	ssClient := statsService.NewStatsServiceClient(x.GrpcClient)
 	x.HandlerServiceClient = &hsClient
 	x.StatsServiceClient = &ssClient
+	rsClient := routingService.NewRoutingServiceClient(x.GrpcClient)
+	x.RoutingServiceClient = &rsClient
 
 	return x, nil
 }
@@ -37,5 +45,6 @@ func (x *XrayHandler) Close() {
 		_ = x.GrpcClient.Close()
 	}
 	x.StatsServiceClient = nil
+	x.RoutingServiceClient = nil
 	x.HandlerServiceClient = nil
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/xtls/xray-core/app/proxyman/command"
 	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
 )
 
 func (x *XrayHandler) AlertInbound(ctx context.Context, tag string, operation *serial.TypedMessage) error {
@@ -16,6 +17,18 @@ func (x *XrayHandler) AlertInbound(ctx context.Context, tag string, operation *s
 	return nil
 }
 
+func (x *XrayHandler) AddInbound(ctx context.Context, inbound *core.InboundHandlerConfig) error {
+	client := *x.HandlerServiceClient
+	_, err := client.AddInbound(ctx, &command.AddInboundRequest{Inbound: inbound})
+	return err
+}
+
+func (x *XrayHandler) RemoveInbound(ctx context.Context, tag string) error {
+	client := *x.HandlerServiceClient
+	_, err := client.RemoveInbound(ctx, &command.RemoveInboundRequest{Tag: tag})
+	return err
+}
+
 func (x *XrayHandler) AlertOutbound(ctx context.Context, tag string, operation *serial.TypedMessage) error {
 	client := *x.HandlerServiceClient
 	_, err := client.AlterOutbound(ctx, &command.AlterOutboundRequest{Tag: tag, Operation: operation})
@@ -25,6 +38,18 @@ func (x *XrayHandler) AlertOutbound(ctx context.Context, tag string, operation *
 	return nil
 }
 
+func (x *XrayHandler) AddOutbound(ctx context.Context, outbound *core.OutboundHandlerConfig) error {
+	client := *x.HandlerServiceClient
+	_, err := client.AddOutbound(ctx, &command.AddOutboundRequest{Outbound: outbound})
+	return err
+}
+
+func (x *XrayHandler) RemoveOutbound(ctx context.Context, tag string) error {
+	client := *x.HandlerServiceClient
+	_, err := client.RemoveOutbound(ctx, &command.RemoveOutboundRequest{Tag: tag})
+	return err
+}
+
 func (x *XrayHandler) AddInboundUser(ctx context.Context, tag string, user Account) error {
 	// Create the AddUserOperation message
 	account, err := user.Message()
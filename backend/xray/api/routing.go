@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/app/router/command"
+)
+
+// ReloadRouting replaces the rules and balancers of the core router with the ones of config.
+func (x *XrayHandler) ReloadRouting(ctx context.Context, config *router.Config) error {
+	message, err := ToTypedMessage(config)
+	if err != nil {
+		return err
+	}
+	client := *x.RoutingServiceClient
+	_, err = client.AddRule(ctx, &command.AddRuleRequest{Config: message, ShouldAppend: false})
+	return err
+}
+
+// AppendRouting adds the rules and balancers of config after the ones of the core router.
+func (x *XrayHandler) AppendRouting(ctx context.Context, config *router.Config) error {
+	message, err := ToTypedMessage(config)
+	if err != nil {
+		return err
+	}
+	client := *x.RoutingServiceClient
+	_, err = client.AddRule(ctx, &command.AddRuleRequest{Config: message, ShouldAppend: true})
+	return err
+}
+
+func (x *XrayHandler) RemoveRule(ctx context.Context, ruleTag string) error {
+	client := *x.RoutingServiceClient
+	_, err := client.RemoveRule(ctx, &command.RemoveRuleRequest{RuleTag: ruleTag})
+	return err
+}
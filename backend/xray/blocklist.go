@@ -0,0 +1,139 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+func isBlocklistRule(raw json.RawMessage) bool {
+	rule, err := parseRoutingRule(raw)
+	if err != nil {
+		return false
+	}
+	_, ok := backend.BlocklistName(rule.RuleTag)
+	return ok
+}
+
+func isBlocklistOutbound(outbound *Outbound) bool {
+	_, ok := backend.BlocklistName(outbound.Tag)
+	return ok
+}
+
+// blocklistRules builds the rules sending the destinations of the lists to their outbounds. Domains and IPs
+// get rules of their own, the core only matches a rule when all of its fields match.
+func blocklistRules(lists []*common.Blocklist) []json.RawMessage {
+	var rules []json.RawMessage
+	for _, list := range lists {
+		tag := backend.BlocklistTag(list.GetName())
+
+		var domains, ips []string
+		for _, domain := range list.GetDomains() {
+			domains = append(domains, "domain:"+domain)
+		}
+		for _, geosite := range list.GetGeosite() {
+			domains = append(domains, "geosite:"+geosite)
+		}
+		ips = append(ips, list.GetCidrs()...)
+		for _, geoip := range list.GetGeoip() {
+			ips = append(ips, "geoip:"+geoip)
+		}
+
+		if len(domains) > 0 {
+			rules = appendRule(rules, map[string]interface{}{"ruleTag": tag + ":domain", "domain": domains, "outboundTag": tag, "type": "field"})
+		}
+		if len(ips) > 0 {
+			rules = appendRule(rules, map[string]interface{}{"ruleTag": tag + ":ip", "ip": ips, "outboundTag": tag, "type": "field"})
+		}
+	}
+	return rules
+}
+
+func appendRule(rules []json.RawMessage, rule map[string]interface{}) []json.RawMessage {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return rules
+	}
+	return append(rules, data)
+}
+
+// blocklistedRules returns the rules with the ones of the lists right after the api rule, in place of earlier lists.
+func (c *Config) blocklistedRules(lists []*common.Blocklist) []json.RawMessage {
+	rules := slices.DeleteFunc(slices.Clone(c.RouterConfig.RuleList), isBlocklistRule)
+	return slices.Insert(rules, slices.IndexFunc(rules, isAPIRule)+1, blocklistRules(lists)...)
+}
+
+// blocklistedOutbounds returns the outbounds with a blackhole per list in place of the ones of earlier lists,
+// they go last so the default outbound stays the first one of the config.
+func (c *Config) blocklistedOutbounds(lists []*common.Blocklist) []*Outbound {
+	outbounds := slices.DeleteFunc(slices.Clone(c.OutboundConfigs), isBlocklistOutbound)
+	for _, list := range lists {
+		data, err := json.Marshal(map[string]string{"tag": backend.BlocklistTag(list.GetName()), "protocol": "blackhole"})
+		if err != nil {
+			continue
+		}
+		outbound := &Outbound{}
+		if err = json.Unmarshal(data, outbound); err != nil {
+			continue
+		}
+		outbounds = append(outbounds, outbound)
+	}
+	return outbounds
+}
+
+func (c *Config) applyBlocklists(lists []*common.Blocklist) {
+	if c.RouterConfig == nil {
+		return
+	}
+	c.OutboundConfigs = c.blocklistedOutbounds(lists)
+	c.RouterConfig.RuleList = c.blocklistedRules(lists)
+}
+
+// SetBlocklists replaces the blocklists of the running core. Outbounds of new lists are added before
+// the routing that sends traffic to them, the ones of dropped lists are removed after it.
+func (x *Xray) SetBlocklists(ctx context.Context, lists []*common.Blocklist) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	outbounds := x.config.blocklistedOutbounds(lists)
+	var added []string
+	undo := func() {
+		for _, tag := range added {
+			_ = x.handler.RemoveOutbound(ctx, tag)
+		}
+	}
+	for _, outbound := range outbounds {
+		if !isBlocklistOutbound(outbound) || x.outboundIndex(outbound.Tag) >= 0 {
+			continue
+		}
+		_, handlerConfig, err := parseOutbound(string(outbound.raw))
+		if err == nil {
+			err = x.handler.AddOutbound(ctx, handlerConfig)
+		}
+		if err != nil {
+			undo()
+			return err
+		}
+		added = append(added, outbound.Tag)
+	}
+
+	if err := x.reloadRouting(ctx, x.config.blocklistedRules(lists), x.config.RouterConfig.Balancers); err != nil {
+		undo()
+		return err
+	}
+
+	var errs []error
+	for _, outbound := range x.config.OutboundConfigs {
+		if isBlocklistOutbound(outbound) && !slices.ContainsFunc(outbounds, func(o *Outbound) bool { return o.Tag == outbound.Tag }) {
+			errs = append(errs, x.handler.RemoveOutbound(ctx, outbound.Tag))
+		}
+	}
+	x.config.OutboundConfigs = outbounds
+
+	logger.Info("blocklists applied", "lists", len(lists))
+	return errors.Join(errs...)
+}
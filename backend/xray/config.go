@@ -3,7 +3,6 @@ package xray
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"slices"
 	"strings"
 	"sync"
@@ -28,7 +27,7 @@ type Config struct {
 	RouterConfig     *conf.RouterConfig     `json:"routing"`
 	DNSConfig        map[string]interface{} `json:"dns"`
 	InboundConfigs   []*Inbound             `json:"inbounds"`
-	OutboundConfigs  interface{}            `json:"outbounds"`
+	OutboundConfigs  []*Outbound            `json:"outbounds"`
 	Policy           *conf.PolicyConfig     `json:"policy"`
 	API              *conf.APIConfig        `json:"api"`
 	Metrics          map[string]interface{} `json:"metrics,omitempty"`
@@ -52,6 +51,29 @@ type Inbound struct {
 	exclude        bool
 }
 
+// Outbound keeps an outbound as it was sent, only the fields the node needs are decoded.
+type Outbound struct {
+	Tag      string
+	Protocol string
+	raw      json.RawMessage
+}
+
+func (o *Outbound) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Tag      string `json:"tag"`
+		Protocol string `json:"protocol"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	o.Tag, o.Protocol, o.raw = fields.Tag, fields.Protocol, append(json.RawMessage(nil), data...)
+	return nil
+}
+
+func (o *Outbound) MarshalJSON() ([]byte, error) {
+	return o.raw, nil
+}
+
 func (c *Config) syncUsers(users []*common.User) {
 	for _, i := range c.InboundConfigs {
 		if i.exclude {
@@ -59,6 +81,7 @@ func (c *Config) syncUsers(users []*common.User) {
 		}
 		i.syncUsers(users)
 	}
+	c.routeUsers(slices.Values(users))
 }
 
 func (i *Inbound) syncUsers(users []*common.User) {
@@ -75,7 +98,7 @@ func (i *Inbound) syncUsers(users []*common.User) {
 			}
 			account, err := api.NewVmessAccount(user)
 			if err != nil {
-				log.Println("error for user", user.GetEmail(), ":", err)
+				logger.Error("invalid user account", "inbound", i.Tag, "email", user.GetEmail(), "error", err)
 			}
 			if slices.Contains(user.Inbounds, i.Tag) {
 				clients = append(clients, account)
@@ -91,7 +114,7 @@ func (i *Inbound) syncUsers(users []*common.User) {
 			}
 			account, err := api.NewVlessAccount(user)
 			if err != nil {
-				log.Println("error for user", user.GetEmail(), ":", err)
+				logger.Error("invalid user account", "inbound", i.Tag, "email", user.GetEmail(), "error", err)
 			}
 			if slices.Contains(user.Inbounds, i.Tag) {
 				newAccount := checkVless(i, *account)
@@ -328,6 +351,22 @@ func (c *Config) ToBytes() ([]byte, error) {
 	return b, nil
 }
 
+// clone returns a copy of the config to roll back to, changes to one do not reach the other.
+func (c *Config) clone() (*Config, error) {
+	data, err := c.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var exclude []string
+	for _, i := range c.InboundConfigs {
+		if i.exclude {
+			exclude = append(exclude, i.Tag)
+		}
+	}
+	return NewXRayConfig(string(data), exclude)
+}
+
 func filterRules(rules []json.RawMessage, apiTag string) ([]json.RawMessage, error) {
 	if rules == nil {
 		rules = []json.RawMessage{}
@@ -354,15 +393,13 @@ func filterRules(rules []json.RawMessage, apiTag string) ([]json.RawMessage, err
 func (c *Config) ApplyAPI(apiPort int) (err error) {
 	// Remove the existing inbound with the API_INBOUND tag
 	for i, inbound := range c.InboundConfigs {
-		if inbound.Tag == "API_INBOUND" {
+		if inbound.Tag == apiInboundTag {
 			c.InboundConfigs = append(c.InboundConfigs[:i], c.InboundConfigs[i+1:]...)
 		}
 	}
 
-	apiTag := "API"
-
 	c.API = &conf.APIConfig{
-		Services: []string{"HandlerService", "LoggerService", "StatsService"},
+		Services: []string{"HandlerService", "LoggerService", "StatsService", "RoutingService"},
 		Tag:      apiTag,
 	}
 
@@ -380,15 +417,15 @@ func (c *Config) ApplyAPI(apiPort int) (err error) {
 		Port:     apiPort,
 		Protocol: "dokodemo-door",
 		Settings: map[string]interface{}{"address": "127.0.0.1"},
-		Tag:      "API_INBOUND",
+		Tag:      apiInboundTag,
 	}
 
 	c.InboundConfigs = append([]*Inbound{inbound}, c.InboundConfigs...)
 
 	rule := map[string]interface{}{
-		"inboundTag":  []string{"API_INBOUND"},
+		"inboundTag":  []string{apiInboundTag},
 		"source":      []string{"127.0.0.1"},
-		"outboundTag": "API",
+		"outboundTag": apiTag,
 		"type":        "field",
 	}
 
@@ -6,15 +6,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/backend/supervisor"
+	"github.com/pasarguard/node/common"
 	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
 )
 
 type Core struct {
@@ -25,10 +32,15 @@ type Core struct {
 	process        *exec.Cmd
 	processPID     int
 	restarting     bool
-	logsChan       chan string
+	logs           *nodeLogger.Hub
 	logger         *nodeLogger.Logger
 	cancelFunc     context.CancelFunc
-	mu             sync.Mutex
+	startTime      time.Time
+	configHash     string
+	// exited is closed once the process was waited for, stopped marks exits caused by the node
+	exited  chan struct{}
+	stopped *atomic.Bool
+	mu      sync.Mutex
 }
 
 func NewXRayCore(executablePath, assetsPath, configPath string, logBufferSize int) (*Core, error) {
@@ -36,7 +48,7 @@ func NewXRayCore(executablePath, assetsPath, configPath string, logBufferSize in
 		executablePath: executablePath,
 		assetsPath:     assetsPath,
 		configPath:     configPath,
-		logsChan:       make(chan string, logBufferSize),
+		logs:           nodeLogger.NewHub(logBufferSize),
 	}
 
 	version, err := core.refreshVersion()
@@ -104,7 +116,10 @@ func (c *Core) Started() bool {
 	return false
 }
 
-func (c *Core) Start(xConfig *Config, debugMode bool) error {
+func (c *Core) Start(ctx context.Context, xConfig *Config, debugMode bool) (err error) {
+	ctx, span := tracing.Start(ctx, "xray.core.Start")
+	defer func() { tracing.End(span, err) }()
+
 	logConfig := xConfig.LogConfig
 	if logConfig == nil {
 		return errors.New("log config is empty")
@@ -117,7 +132,10 @@ func (c *Core) Start(xConfig *Config, debugMode bool) error {
 
 	accessFile, errorFile := xConfig.RemoveLogFiles()
 
+	_, encodeSpan := tracing.Start(ctx, "xray.config.ToBytes")
 	bytesConfig, err := xConfig.ToBytes()
+	encodeSpan.SetAttributes(attribute.Int("config.bytes", len(bytesConfig)))
+	tracing.End(encodeSpan, err)
 	if debugMode {
 		if err = c.GenerateConfigFile(bytesConfig); err != nil {
 			return err
@@ -134,14 +152,15 @@ func (c *Core) Start(xConfig *Config, debugMode bool) error {
 
 	// Clean up any orphaned xray processes before starting new one
 	if err := c.cleanupOrphanedProcesses(); err != nil {
-		log.Printf("warning: failed to cleanup orphaned processes: %v", err)
+		logger.Warn("failed to cleanup orphaned processes", "error", err)
 	}
 
 	// Force kill any orphaned process in this Core instance before starting new one
 	if c.process != nil && c.process.Process != nil {
 		pid := c.process.Process.Pid
+		c.stopped.Store(true)
 		_ = c.process.Process.Kill()
-		_ = killProcessTree(pid)
+		_ = supervisor.KillProcessTree(pid)
 		c.process = nil
 		c.processPID = 0
 	}
@@ -149,7 +168,7 @@ func (c *Core) Start(xConfig *Config, debugMode bool) error {
 	cmd := exec.Command(c.executablePath, "-c", "stdin:")
 	cmd.Env = append(os.Environ(), "XRAY_LOCATION_ASSET="+c.assetsPath)
 	// Set process attributes for proper process management
-	setProcAttributes(cmd)
+	supervisor.SetProcAttributes(cmd)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -162,34 +181,62 @@ func (c *Core) Start(xConfig *Config, debugMode bool) error {
 	}
 
 	// Create a new logger for this core instance
-	c.logger = nodeLogger.New(debugMode)
+	c.logger = nodeLogger.New(nodeLogger.ComponentXray, debugMode)
 	if err = c.logger.SetLogFile(accessFile, errorFile); err != nil {
 		return err
 	}
 
 	cmd.Stdin = bytes.NewBuffer(bytesConfig)
+	c.startTime = time.Now()
 	if err = cmd.Start(); err != nil {
 		return err
 	}
 	c.process = cmd
 	c.processPID = cmd.Process.Pid
-
-	// Wait for the process to exit to prevent zombie processes
-	go func() {
-		_ = cmd.Wait()
-	}()
+	supervisor.Track(c.processPID)
+	c.configHash = backend.ConfigHash(bytesConfig)
+	logger.Debug("xray process started", "pid", c.processPID)
 
 	ctxCore, cancel := context.WithCancel(context.Background())
 	c.cancelFunc = cancel
 
 	// Start capturing process logs
-	go c.captureProcessLogs(ctxCore, stdout)
-	go c.captureProcessLogs(ctxCore, stderr)
+	var captured sync.WaitGroup
+	captured.Add(2)
+	go func() {
+		defer captured.Done()
+		c.captureProcessLogs(ctxCore, stdout)
+	}()
+	go func() {
+		defer captured.Done()
+		c.captureProcessLogs(ctxCore, stderr)
+	}()
+
+	// Wait for the process to exit to prevent zombie processes and record how it ended,
+	// the pipes are read to the end first since Wait closes them
+	exited, stopped := make(chan struct{}), new(atomic.Bool)
+	c.exited, c.stopped = exited, stopped
+	version, started, configHash, pid := c.version, c.startTime, c.configHash, c.processPID
+	go func() {
+		defer close(exited)
+		defer supervisor.Untrack(pid)
+		captured.Wait()
+		if err := cmd.Wait(); err != nil && cmd.ProcessState == nil {
+			return
+		}
+		exit := backend.NewCoreExit("xray", version, started, cmd.ProcessState, c.logs)
+		exit.ConfigHash = configHash
+		exit.Expected = stopped.Load()
+		backend.RecordExit(exit)
+	}()
 
 	return nil
 }
 
-func (c *Core) Stop() {
+func (c *Core) Stop(ctx context.Context) {
+	_, span := tracing.Start(ctx, "xray.core.Stop")
+	defer span.End()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -202,28 +249,24 @@ func (c *Core) Stop() {
 		c.processPID = pid
 
 		// Kill the process
+		c.stopped.Store(true)
 		_ = c.process.Process.Kill()
 
 		// Wait for process to terminate with timeout
-		done := make(chan error, 1)
-		go func() {
-			done <- c.process.Wait()
-		}()
-
 		select {
-		case <-done:
+		case <-c.exited:
 			// Process terminated
 		case <-time.After(5 * time.Second):
 			// Timeout - try force kill
-			log.Printf("xray process %d did not terminate within timeout, force killing", pid)
-			_ = killProcessTree(pid)
+			logger.Warn("xray process did not terminate within timeout, force killing", "pid", pid)
+			_ = supervisor.KillProcessTree(pid)
 		}
 
 		// Verify process is actually dead
-		if err := verifyProcessDead(pid); err != nil {
-			log.Printf("warning: xray process %d may still be running: %v", pid, err)
+		if err := supervisor.VerifyDead(pid); err != nil {
+			logger.Warn("xray process may still be running", "pid", pid, "error", err)
 			// Try one more time to kill it
-			_ = killProcessTree(pid)
+			_ = supervisor.KillProcessTree(pid)
 		}
 	}
 	c.process = nil
@@ -238,10 +281,10 @@ func (c *Core) Stop() {
 		c.logger = nil
 	}
 
-	log.Println("xray core stopped")
+	logger.Info("xray core stopped")
 }
 
-func (c *Core) Restart(config *Config, debugMode bool) error {
+func (c *Core) Restart(ctx context.Context, config *Config, debugMode bool) error {
 	c.mu.Lock()
 	if c.restarting {
 		c.mu.Unlock()
@@ -256,68 +299,36 @@ func (c *Core) Restart(config *Config, debugMode bool) error {
 		c.mu.Unlock()
 	}()
 
-	log.Println("restarting Xray core...")
-	c.Stop()
-	if err := c.Start(config, debugMode); err != nil {
+	logger.Info("restarting xray core")
+	c.Stop(ctx)
+	if err := c.Start(ctx, config, debugMode); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *Core) Logs() chan string {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.logsChan
+func (c *Core) Logs() *nodeLogger.Hub {
+	return c.logs
 }
 
-// ProcessInfo holds information about a process
-type ProcessInfo struct {
-	PID      int
-	PPID     int
-	IsZombie bool
+func (c *Core) StartTime() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.startTime
 }
 
-// cleanupOrphanedProcesses finds and kills xray processes that are:
-// 1. Zombie processes (orphaned from their parent)
-// 2. Processes where the node itself is the parent (PPID matches node PID)
+// cleanupOrphanedProcesses kills xray processes left behind by earlier runs or by this core.
 func (c *Core) cleanupOrphanedProcesses() error {
-	processes, err := findXrayProcesses(c.executablePath)
-	if err != nil {
-		return fmt.Errorf("failed to find xray processes: %w", err)
-	}
-
 	currentPID := 0
 	if c.process != nil && c.process.Process != nil {
 		currentPID = c.process.Process.Pid
 	}
 
-	// Get current node process PID
-	nodePID := os.Getpid()
-
-	killedCount := 0
-	for _, procInfo := range processes {
-		if procInfo.PID == currentPID {
-			continue
-		}
-
-		reason := "stale xray process from previous run"
-		if procInfo.IsZombie {
-			reason = "zombie xray process"
-		} else if procInfo.PPID == nodePID {
-			reason = fmt.Sprintf("orphaned xray process with node as parent (PPID: %d)", procInfo.PPID)
-		}
-
-		log.Printf("%s %d (PPID: %d), killing it", reason, procInfo.PID, procInfo.PPID)
-		if err := killProcessTree(procInfo.PID); err != nil {
-			log.Printf("warning: failed to kill orphaned process %d: %v", procInfo.PID, err)
-		} else {
-			killedCount++
-		}
-	}
-
+	killedCount, err := supervisor.CleanupOrphans("xray", c.executablePath, currentPID, logger)
 	if killedCount > 0 {
-		log.Printf("cleaned up %d orphaned xray process(es)", killedCount)
+		publishEvent(common.EventType_ORPHAN_CLEANUP, common.EventSeverity_WARNING,
+			fmt.Sprintf("cleaned up %d orphaned xray process(es)", killedCount),
+			map[string]string{"count": strconv.Itoa(killedCount)})
 	}
-
-	return nil
+	return err
 }
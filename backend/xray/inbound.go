@@ -0,0 +1,113 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+// apiInboundTag is the inbound ApplyAPI adds for the node, it can not be managed through the api
+const apiInboundTag = "API_INBOUND"
+
+func (x *Xray) ListInbounds(_ context.Context) (*common.InboundsResponse, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	response := &common.InboundsResponse{}
+	for _, inbound := range x.config.InboundConfigs {
+		if inbound.Tag == apiInboundTag {
+			continue
+		}
+		info := &common.InboundInfo{
+			Tag:      inbound.Tag,
+			Protocol: inbound.Protocol,
+			Listen:   inbound.Listen,
+			Excluded: inbound.exclude,
+		}
+		if inbound.Port != nil {
+			info.Port = fmt.Sprint(inbound.Port)
+		}
+		for _, user := range x.users {
+			if slices.Contains(user.GetInbounds(), inbound.Tag) {
+				info.Users++
+			}
+		}
+		response.Inbounds = append(response.Inbounds, info)
+	}
+	return response, nil
+}
+
+// AddInbound adds an inbound to the running core with the users assigned to its tag.
+// The stored config keeps it, so a restart of the core brings it back.
+func (x *Xray) AddInbound(ctx context.Context, config string) error {
+	inbound := &Inbound{}
+	if err := json.Unmarshal([]byte(config), inbound); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid inbound: %v", err)
+	}
+	if inbound.Tag == "" || inbound.Tag == apiInboundTag {
+		return status.Error(codes.InvalidArgument, "inbound needs a tag")
+	}
+	if inbound.Settings == nil {
+		inbound.Settings = make(map[string]interface{})
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if slices.ContainsFunc(x.config.InboundConfigs, func(i *Inbound) bool { return i.Tag == inbound.Tag }) {
+		return status.Errorf(codes.AlreadyExists, "inbound %q already exists", inbound.Tag)
+	}
+
+	users := make([]*common.User, 0, len(x.users))
+	for _, user := range x.users {
+		users = append(users, user)
+	}
+	inbound.syncUsers(users)
+
+	// The core takes the inbound built like from a config file
+	data, err := json.Marshal(inbound)
+	if err != nil {
+		return err
+	}
+	var detour conf.InboundDetourConfig
+	if err = json.Unmarshal(data, &detour); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid inbound: %v", err)
+	}
+	handlerConfig, err := detour.Build()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid inbound: %v", err)
+	}
+	if err = x.handler.AddInbound(ctx, handlerConfig); err != nil {
+		return err
+	}
+
+	x.config.InboundConfigs = append(slices.Clip(x.config.InboundConfigs), inbound)
+	logger.Info("inbound added", "tag", inbound.Tag, "protocol", inbound.Protocol)
+	return nil
+}
+
+// RemoveInbound removes an inbound from the running core and the stored config.
+func (x *Xray) RemoveInbound(ctx context.Context, tag string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := slices.IndexFunc(x.config.InboundConfigs, func(i *Inbound) bool { return i.Tag == tag })
+	if index < 0 || tag == apiInboundTag {
+		return status.Errorf(codes.NotFound, "inbound %q not found", tag)
+	}
+	if err := x.handler.RemoveInbound(ctx, tag); err != nil {
+		return err
+	}
+
+	// A new slice, SyncUser may still be ranging over the old one
+	x.config.InboundConfigs = slices.Delete(slices.Clone(x.config.InboundConfigs), index, index+1)
+	logger.Info("inbound removed", "tag", tag)
+	return nil
+}
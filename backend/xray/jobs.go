@@ -4,77 +4,133 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
 )
 
-func (x *Xray) checkXrayStatus() error {
-	x.mu.Lock()
-	defer x.mu.Unlock()
+func publishEvent(eventType common.EventType, severity common.EventSeverity, message string, details map[string]string) {
+	events.Publish(&common.Event{
+		Type:     eventType,
+		Severity: severity,
+		Backend:  "xray",
+		Message:  message,
+		Details:  details,
+	})
+}
+
+// checkXrayStatus waits for the core to announce its start, x.mu must be held once the backend is shared.
+func (x *Xray) checkXrayStatus(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "xray.core.WaitReady")
+	defer func() { tracing.End(span, err) }()
 
 	core := x.core
-	logChan := core.Logs()
 	version := core.Version()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	// Lines printed before subscribing are replayed from the hub
+	replay, logs := core.Logs().Subscribe(nodeLogger.SubscriberBuffer, nodeLogger.Replay{Since: core.StartTime()}, nil)
+	defer logs.Close()
+
+	// The wait is bounded by its own timeout, not by the request that started the core
+	waitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*10)
 	defer cancel()
 
-	// Precompile regex for better performance
-	logRegex := regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[([^]]+)] (.+)$`)
+	for _, line := range replay {
+		if started, err := checkStartupLine(line.Text, version); started || err != nil {
+			return err
+		}
+	}
 
 	for {
 		select {
-		case lastLog := <-logChan:
-			if strings.Contains(lastLog, "Xray "+version+" started") {
-				return nil
-			}
-
-			// Check for failure patterns
-			matches := logRegex.FindStringSubmatch(lastLog)
-			if len(matches) > 3 {
-				// Check both error level and message content
-				if matches[2] == "Error" || strings.Contains(matches[3], "Failed to start") {
-					return fmt.Errorf("failed to start xray: %s", matches[3])
-				}
-			} else {
-				// Fallback check if log format doesn't match
-				if strings.Contains(lastLog, "Failed to start") {
-					return fmt.Errorf("failed to start xray: %s", lastLog)
-				}
+		case line := <-logs.C:
+			if started, err := checkStartupLine(line.Text, version); started || err != nil {
+				return err
 			}
 
-		case <-ctx.Done():
+		case <-waitCtx.Done():
 			return errors.New("failed to start xray: context timeout")
 		}
 	}
 }
 
+var startupLogRegex = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[([^]]+)] (.+)$`)
+
+// checkStartupLine reports whether the line announces xray started, or the error it failed to start with.
+func checkStartupLine(line, version string) (bool, error) {
+	if strings.Contains(line, "Xray "+version+" started") {
+		return true, nil
+	}
+
+	// Check for failure patterns
+	matches := startupLogRegex.FindStringSubmatch(line)
+	if len(matches) > 3 {
+		// Check both error level and message content
+		if matches[2] == "Error" || strings.Contains(matches[3], "Failed to start") {
+			return false, fmt.Errorf("failed to start xray: %s", matches[3])
+		}
+	} else if strings.Contains(line, "Failed to start") {
+		// Fallback check if log format doesn't match
+		return false, fmt.Errorf("failed to start xray: %s", line)
+	}
+	return false, nil
+}
+
+// healthCheckInterval is the pause between health checks of the core
+const healthCheckInterval = 5 * time.Second
+
+// checkXrayHealth restarts xray when its API stops answering, paced by the restart breaker.
 func (x *Xray) checkXrayHealth(baseCtx context.Context) {
 	for {
 		select {
 		case <-baseCtx.Done():
 			return
-		default:
-			ctx, cancel := context.WithTimeout(baseCtx, time.Second*3)
-			_, err := x.GetSysStats(ctx)
-			cancel() // Always call cancel to avoid context leak
-
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					// Context was canceled due to x.ctx cancellation
-					return // Exit gracefully
-				}
-
-				// Handle other errors by attempting restart
-				if err = x.Restart(); err != nil {
-					log.Println(err.Error())
-				} else {
-					log.Println("xray restarted")
-				}
-			}
+		case <-time.After(healthCheckInterval):
+		}
+
+		ctx, cancel := context.WithTimeout(baseCtx, time.Second*3)
+		_, err := x.GetSysStats(ctx)
+		cancel() // Always call cancel to avoid context leak
+
+		if err == nil {
+			x.restarts.Healthy()
+			continue
+		}
+		if errors.Is(err, context.Canceled) {
+			// Context was canceled due to x.ctx cancellation
+			return // Exit gracefully
+		}
+
+		delay, ok, opened := x.restarts.Failed(time.Now())
+		if opened {
+			logger.Error("xray is crash looping, automatic restarts stopped", "error", err)
+			publishEvent(common.EventType_CORE_CRASH_LOOP, common.EventSeverity_CRITICAL,
+				"xray kept failing health checks, automatic restarts stopped", map[string]string{"error": err.Error()})
+		}
+		if !ok {
+			continue
+		}
+
+		logger.Warn("xray health check failed, restarting", "error", err, "delay", delay)
+		publishEvent(common.EventType_CORE_CRASHED, common.EventSeverity_WARNING, "xray health check failed", map[string]string{"error": err.Error()})
+		select {
+		case <-baseCtx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		x.restarts.Restarted()
+		if err = x.Restart(); err != nil {
+			logger.Error("failed to restart xray", "error", err)
+			publishEvent(common.EventType_CORE_RESTART_FAILED, common.EventSeverity_CRITICAL, err.Error(), nil)
+		} else {
+			logger.Info("xray restarted")
+			publishEvent(common.EventType_CORE_RESTARTED, common.EventSeverity_INFO, "xray restarted after failed health check", nil)
 		}
-		time.Sleep(time.Second * 5)
 	}
 }
@@ -4,16 +4,62 @@ import (
 	"bufio"
 	"context"
 	"io"
+	"log/slog"
 	"regexp"
+	"time"
 
+	"github.com/pasarguard/node/common"
 	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tools"
 )
 
+var logger = nodeLogger.For(nodeLogger.ComponentXray)
+
 var (
 	// Pattern for access logs: contains "accepted" (tcp/udp) and "email:"
 	accessLogPattern = regexp.MustCompile(`from .+:\d+ accepted (tcp|udp):.+:\d+ \[.+\] email: .+`)
+	// Fields of an access log, the route is "[inbound -> outbound]", "[inbound >> outbound]" or just "[inbound]":
+	// 2025/10/06 11:28:38.624743 from 2.187.120.79:48394 accepted tcp:www.gstatic.com:443 [REALITY -> DIRECT] email: 7.Family
+	accessRecordPattern = regexp.MustCompile(`^(?:(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) )?from (?:(?:tcp|udp):)?(\S+) accepted (tcp|udp):(\S+) \[([^\]]+?)(?: (?:->|>>) ([^\]]*))?\] email: (\S+)`)
 )
 
+const accessTimeLayout = "2006/01/02 15:04:05.999999"
+
+// parseLogLine classifies a line for log stream filters and parses access logs into records.
+func parseLogLine(text string) nodeLogger.Line {
+	line := nodeLogger.Line{Text: text, Level: nodeLogger.CoreLevel(text)}
+	if !accessLogPattern.MatchString(text) {
+		return line
+	}
+
+	line.Access = true
+	line.Level = slog.LevelInfo
+
+	matches := accessRecordPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return line
+	}
+
+	record := &common.AccessLog{
+		Timestamp: time.Now().UnixMilli(),
+		Network:   matches[3],
+		Inbound:   matches[5],
+		Outbound:  matches[6],
+		Email:     matches[7],
+	}
+	// Xray prints the local time
+	if at, err := time.ParseInLocation(accessTimeLayout, matches[1], time.Local); err == nil {
+		record.Timestamp = at.UnixMilli()
+	}
+	record.SourceIp, record.SourcePort = tools.SplitHostPort(matches[2])
+	record.DestinationHost, record.DestinationPort = tools.SplitHostPort(matches[4])
+
+	line.Record = record
+	line.Email = record.Email
+	line.Inbound = record.Inbound
+	return line
+}
+
 func (c *Core) detectLogType(log string) {
 	// Check if it's an access log (contains accepted + email pattern)
 	if accessLogPattern.MatchString(log) {
@@ -33,13 +79,8 @@ func (c *Core) captureProcessLogs(ctx context.Context, pipe io.Reader) {
 			return // Exit gracefully if stop signal received
 		default:
 			output := scanner.Text()
-			// Non-blocking send: skip if channel is full to prevent deadlock
-			select {
-			case c.logsChan <- output:
-				// Log sent successfully
-			default:
-				// Channel full, skip this log (prevents blocking xray process)
-			}
+			// Publishing never blocks, slow viewers miss lines instead of stalling xray
+			c.logs.Publish(parseLogLine(output))
 			c.detectLogType(output)
 		}
 	}
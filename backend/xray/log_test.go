@@ -1,11 +1,16 @@
 package xray
 
 import (
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/common"
 	nodeLogger "github.com/pasarguard/node/logger"
 )
 
@@ -74,7 +79,7 @@ func TestDetectLogType(t *testing.T) {
 			accessLog := filepath.Join(tmpDir, "access.log")
 			errorLog := filepath.Join(tmpDir, "error.log")
 
-			logger := nodeLogger.New(false)
+			logger := nodeLogger.New(nodeLogger.ComponentXray, false)
 			if err := logger.SetLogFile(accessLog, errorLog); err != nil {
 				t.Fatalf("Failed to set log files: %v", err)
 			}
@@ -106,3 +111,106 @@ func TestDetectLogType(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		level   slog.Level
+		access  bool
+		email   string
+		inbound string
+	}{
+		{
+			name:    "Access log - default route",
+			text:    "2025/10/06 11:28:38.624743 from 2.187.120.79:48394 accepted tcp:www.gstatic.com:443 [REALITY_GRPC_1 -> DIRECT] email: 7.Family",
+			level:   slog.LevelInfo,
+			access:  true,
+			email:   "7.Family",
+			inbound: "REALITY_GRPC_1",
+		},
+		{
+			name:    "Access log - routed",
+			text:    "2025/10/06 11:28:38.624743 from 5.117.22.146:16425 accepted udp:dns.google.com:53 [VLESS TCP >> BLOCK] email: 1.Myself",
+			level:   slog.LevelInfo,
+			access:  true,
+			email:   "1.Myself",
+			inbound: "VLESS TCP",
+		},
+		{
+			name:  "Warning",
+			text:  "2024/01/15 10:30:45.654321 [Warning] connection timeout",
+			level: slog.LevelWarn,
+		},
+		{
+			name:  "Error",
+			text:  "2024/01/15 10:30:45.123456 [Error] failed to connect to server",
+			level: slog.LevelError,
+		},
+		{
+			name:  "No level",
+			text:  "some random log without level",
+			level: slog.LevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := parseLogLine(tt.text)
+			if line.Level != tt.level || line.Access != tt.access || line.Email != tt.email || line.Inbound != tt.inbound {
+				t.Errorf("unexpected line: %+v", line)
+			}
+			if (line.Record != nil) != tt.access {
+				t.Errorf("access logs and only them must carry a record, got %v", line.Record)
+			}
+		})
+	}
+}
+
+func TestParseLogLine_Record(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want *common.AccessLog
+	}{
+		{
+			name: "IPv4",
+			text: "2025/10/06 11:28:38.624743 from 2.187.120.79:48394 accepted tcp:www.gstatic.com:443 [REALITY_GRPC_1 -> DIRECT] email: 7.Family",
+			want: &common.AccessLog{
+				Timestamp:       time.Date(2025, 10, 6, 11, 28, 38, 624743000, time.Local).UnixMilli(),
+				SourceIp:        "2.187.120.79",
+				SourcePort:      48394,
+				Network:         "tcp",
+				DestinationHost: "www.gstatic.com",
+				DestinationPort: 443,
+				Inbound:         "REALITY_GRPC_1",
+				Outbound:        "DIRECT",
+				Email:           "7.Family",
+			},
+		},
+		{
+			name: "IPv6 with network prefixed source",
+			text: "2025/10/06 11:28:38 from tcp:[2001:db8::1]:5000 accepted udp:[2001:4860:4860::8888]:53 [VLESS TCP >> BLOCK] email: 1.Myself",
+			want: &common.AccessLog{
+				Timestamp:       time.Date(2025, 10, 6, 11, 28, 38, 0, time.Local).UnixMilli(),
+				SourceIp:        "2001:db8::1",
+				SourcePort:      5000,
+				Network:         "udp",
+				DestinationHost: "2001:4860:4860::8888",
+				DestinationPort: 53,
+				Inbound:         "VLESS TCP",
+				Outbound:        "BLOCK",
+				Email:           "1.Myself",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := parseLogLine(tt.text)
+			if !proto.Equal(line.Record, tt.want) {
+				t.Errorf("unexpected record:\n got: %v\nwant: %v", line.Record, tt.want)
+			}
+		})
+	}
+}
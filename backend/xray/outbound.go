@@ -0,0 +1,142 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+// parseOutbound decodes an outbound for the api and builds it like the core does from a config file.
+func parseOutbound(config string) (*Outbound, *core.OutboundHandlerConfig, error) {
+	outbound := &Outbound{}
+	if err := json.Unmarshal([]byte(config), outbound); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid outbound: %v", err)
+	}
+	if outbound.Tag == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "outbound needs a tag")
+	}
+
+	var detour conf.OutboundDetourConfig
+	if err := json.Unmarshal(outbound.raw, &detour); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid outbound: %v", err)
+	}
+	handlerConfig, err := detour.Build()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid outbound: %v", err)
+	}
+	return outbound, handlerConfig, nil
+}
+
+// blocklistOutbound keeps the outbounds of blocklists out of the hands of the api, SetBlocklists manages them.
+func blocklistOutbound(tag string, code codes.Code) error {
+	if _, ok := backend.BlocklistName(tag); ok {
+		return status.Errorf(code, "outbound %q belongs to a blocklist", tag)
+	}
+	return nil
+}
+
+func (x *Xray) outboundIndex(tag string) int {
+	return slices.IndexFunc(x.config.OutboundConfigs, func(o *Outbound) bool { return o.Tag == tag })
+}
+
+func (x *Xray) ListOutbounds(_ context.Context) (*common.OutboundsResponse, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	response := &common.OutboundsResponse{}
+	for _, outbound := range x.config.OutboundConfigs {
+		response.Outbounds = append(response.Outbounds, &common.OutboundInfo{Tag: outbound.Tag, Protocol: outbound.Protocol})
+	}
+	return response, nil
+}
+
+// AddOutbound adds an outbound to the running core and the stored config.
+func (x *Xray) AddOutbound(ctx context.Context, config string) error {
+	outbound, handlerConfig, err := parseOutbound(config)
+	if err != nil {
+		return err
+	}
+	if err = blocklistOutbound(outbound.Tag, codes.InvalidArgument); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.outboundIndex(outbound.Tag) >= 0 {
+		return status.Errorf(codes.AlreadyExists, "outbound %q already exists", outbound.Tag)
+	}
+	if err = x.handler.AddOutbound(ctx, handlerConfig); err != nil {
+		return err
+	}
+
+	x.config.OutboundConfigs = append(x.config.OutboundConfigs, outbound)
+	logger.Info("outbound added", "tag", outbound.Tag, "protocol", outbound.Protocol)
+	return nil
+}
+
+// ReplaceOutbound swaps the outbound with the same tag, it keeps its place in the stored config
+// so replacing the first outbound keeps it the default one after a restart.
+func (x *Xray) ReplaceOutbound(ctx context.Context, config string) error {
+	outbound, handlerConfig, err := parseOutbound(config)
+	if err != nil {
+		return err
+	}
+	if err = blocklistOutbound(outbound.Tag, codes.InvalidArgument); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.outboundIndex(outbound.Tag)
+	if index < 0 {
+		return status.Errorf(codes.NotFound, "outbound %q not found", outbound.Tag)
+	}
+	if err = x.handler.RemoveOutbound(ctx, outbound.Tag); err != nil {
+		return err
+	}
+	if err = x.handler.AddOutbound(ctx, handlerConfig); err != nil {
+		// Put the old outbound back so the core keeps routing through the tag
+		if _, previous, parseErr := parseOutbound(string(x.config.OutboundConfigs[index].raw)); parseErr == nil {
+			if restoreErr := x.handler.AddOutbound(ctx, previous); restoreErr != nil {
+				logger.Error("failed to restore replaced outbound", "tag", outbound.Tag, "error", restoreErr)
+			}
+		}
+		return err
+	}
+
+	x.config.OutboundConfigs[index] = outbound
+	logger.Info("outbound replaced", "tag", outbound.Tag, "protocol", outbound.Protocol)
+	return nil
+}
+
+// RemoveOutbound removes an outbound from the running core and the stored config.
+func (x *Xray) RemoveOutbound(ctx context.Context, tag string) error {
+	if err := blocklistOutbound(tag, codes.FailedPrecondition); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.outboundIndex(tag)
+	if index < 0 {
+		return status.Errorf(codes.NotFound, "outbound %q not found", tag)
+	}
+	if err := x.handler.RemoveOutbound(ctx, tag); err != nil {
+		return err
+	}
+
+	x.config.OutboundConfigs = slices.Delete(x.config.OutboundConfigs, index, index+1)
+	logger.Info("outbound removed", "tag", tag)
+	return nil
+}
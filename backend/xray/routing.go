@@ -0,0 +1,319 @@
+package xray
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+// apiTag is the outbound of the core api, ApplyAPI keeps the rule routing the api inbound to it first
+const apiTag = "API"
+
+// userRulePrefix starts the ruleTag of the rules the node generates from the outbound tags of users
+const userRulePrefix = "user-route:"
+
+// routingRule holds the fields of a rule the node reads, the rest stays in the raw JSON.
+type routingRule struct {
+	RuleTag     string `json:"ruleTag"`
+	OutboundTag string `json:"outboundTag"`
+	BalancerTag string `json:"balancerTag"`
+}
+
+func parseRoutingRule(raw []byte) (*routingRule, error) {
+	rule := &routingRule{}
+	if err := json.Unmarshal(raw, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func isAPIRule(raw json.RawMessage) bool {
+	rule, err := parseRoutingRule(raw)
+	return err == nil && rule.OutboundTag == apiTag
+}
+
+func isUserRule(raw json.RawMessage) bool {
+	rule, err := parseRoutingRule(raw)
+	return err == nil && strings.HasPrefix(rule.RuleTag, userRulePrefix)
+}
+
+// generatedRule reports whether the node owns the rule, those follow users and blocklists.
+func generatedRule(ruleTag string) bool {
+	_, blocklist := backend.BlocklistName(ruleTag)
+	return blocklist || strings.HasPrefix(ruleTag, userRulePrefix)
+}
+
+// routedRules returns the rules with a rule per outbound or balancer of users right after the api rule,
+// matching the users by email. Rules generated before are replaced.
+func (c *Config) routedRules(users iter.Seq[*common.User]) []json.RawMessage {
+	routes := backend.UserRoutes(users)
+	rules := slices.DeleteFunc(slices.Clone(c.RouterConfig.RuleList), isUserRule)
+	generated := make([]json.RawMessage, 0, len(routes))
+	for _, tag := range slices.Sorted(maps.Keys(routes)) {
+		rule := map[string]interface{}{
+			"ruleTag": userRulePrefix + tag,
+			"user":    routes[tag],
+			"type":    "field",
+		}
+		if slices.ContainsFunc(c.RouterConfig.Balancers, func(b *conf.BalancingRule) bool { return b.Tag == tag }) {
+			rule["balancerTag"] = tag
+		} else {
+			rule["outboundTag"] = tag
+		}
+		generated = appendRule(generated, rule)
+	}
+
+	// After the api rule and the blocklists, a blocked destination stays blocked for routed users
+	index := slices.IndexFunc(rules, isAPIRule) + 1
+	for index < len(rules) && isBlocklistRule(rules[index]) {
+		index++
+	}
+	return slices.Insert(rules, index, generated...)
+}
+
+func (c *Config) routeUsers(users iter.Seq[*common.User]) {
+	if c.RouterConfig == nil {
+		c.RouterConfig = &conf.RouterConfig{}
+	}
+	c.RouterConfig.RuleList = c.routedRules(users)
+}
+
+// routeUsers reloads the routing of the running core after the outbound tag of a user changed,
+// when the rules generated from the users stay the same the core is left alone.
+func (x *Xray) routeUsers(ctx context.Context) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	rules := x.config.routedRules(maps.Values(x.users))
+	if slices.EqualFunc(rules, x.config.RouterConfig.RuleList, func(a, b json.RawMessage) bool { return bytes.Equal(a, b) }) {
+		return nil
+	}
+	if err := x.reloadRouting(ctx, rules, x.config.RouterConfig.Balancers); err != nil {
+		return err
+	}
+	logger.Debug("user routes updated")
+	return nil
+}
+
+// routeExists reports whether users can be routed through the tag, an outbound or a balancer.
+func (x *Xray) routeExists(tag string) bool {
+	return x.outboundIndex(tag) >= 0 || x.balancerIndex(tag) >= 0
+}
+
+func (x *Xray) ruleIndex(ruleTag string) int {
+	return slices.IndexFunc(x.config.RouterConfig.RuleList, func(raw json.RawMessage) bool {
+		rule, err := parseRoutingRule(raw)
+		return err == nil && rule.RuleTag == ruleTag
+	})
+}
+
+func (x *Xray) balancerIndex(tag string) int {
+	return slices.IndexFunc(x.config.RouterConfig.Balancers, func(b *conf.BalancingRule) bool { return b.Tag == tag })
+}
+
+// buildRouting builds rules and balancers as the core loads them, every balancer a rule uses must be
+// among balancers.
+func (x *Xray) buildRouting(rules []json.RawMessage, balancers []*conf.BalancingRule) (*conf.RouterConfig, *router.Config, error) {
+	routerConf := &conf.RouterConfig{
+		RuleList:       rules,
+		DomainStrategy: x.config.RouterConfig.DomainStrategy,
+		Balancers:      balancers,
+	}
+	routerConfig, err := routerConf.Build()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid routing: %v", err)
+	}
+	for _, rule := range routerConfig.GetRule() {
+		tag := rule.GetBalancingTag()
+		if tag != "" && !slices.ContainsFunc(balancers, func(b *conf.BalancingRule) bool { return b.Tag == tag }) {
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "balancer %q not found", tag)
+		}
+	}
+	return routerConf, routerConfig, nil
+}
+
+// reloadRouting replaces the rules and balancers of the running core and the stored config.
+// The core drops its rules before loading new ones, so everything is built and checked here first.
+func (x *Xray) reloadRouting(ctx context.Context, rules []json.RawMessage, balancers []*conf.BalancingRule) error {
+	routerConf, routerConfig, err := x.buildRouting(rules, balancers)
+	if err != nil {
+		return err
+	}
+	if err = x.handler.ReloadRouting(ctx, routerConfig); err != nil {
+		return err
+	}
+	x.config.RouterConfig = routerConf
+	return nil
+}
+
+// appendRouting adds rules and balancers after the ones of the running core and the stored config,
+// only the added ones are built.
+func (x *Xray) appendRouting(ctx context.Context, rules []json.RawMessage, balancers []*conf.BalancingRule) error {
+	_, routerConfig, err := x.buildRouting(rules, slices.Concat(x.config.RouterConfig.Balancers, balancers))
+	if err != nil {
+		return err
+	}
+	// Only the added balancers are sent, the core refuses the ones it has
+	routerConfig.BalancingRule = routerConfig.BalancingRule[len(x.config.RouterConfig.Balancers):]
+	if err = x.handler.AppendRouting(ctx, routerConfig); err != nil {
+		return err
+	}
+
+	routerConf := *x.config.RouterConfig
+	routerConf.RuleList = slices.Concat(routerConf.RuleList, rules)
+	routerConf.Balancers = slices.Concat(routerConf.Balancers, balancers)
+	x.config.RouterConfig = &routerConf
+	return nil
+}
+
+func (x *Xray) ListRouting(_ context.Context) (*common.RoutingResponse, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	response := &common.RoutingResponse{}
+	for _, raw := range x.config.RouterConfig.RuleList {
+		rule, err := parseRoutingRule(raw)
+		if err != nil || rule.OutboundTag == apiTag {
+			continue
+		}
+		response.Rules = append(response.Rules, &common.RoutingRule{
+			RuleTag:     rule.RuleTag,
+			OutboundTag: rule.OutboundTag,
+			BalancerTag: rule.BalancerTag,
+			Config:      string(raw),
+		})
+	}
+	for _, balancer := range x.config.RouterConfig.Balancers {
+		data, err := json.Marshal(balancer)
+		if err != nil {
+			return nil, err
+		}
+		response.Balancers = append(response.Balancers, &common.BalancerInfo{
+			Tag:      balancer.Tag,
+			Selector: balancer.Selectors,
+			Config:   string(data),
+		})
+	}
+	return response, nil
+}
+
+// AddRoutingRule adds a rule to the running core and the stored config. A rule added first goes
+// right after the api rule, so it can block or redirect traffic other rules would match.
+func (x *Xray) AddRoutingRule(ctx context.Context, config string, first bool) error {
+	rule, err := parseRoutingRule([]byte(config))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid routing rule: %v", err)
+	}
+	if rule.RuleTag == "" {
+		return status.Error(codes.InvalidArgument, "routing rule needs a ruleTag")
+	}
+	if rule.OutboundTag == apiTag {
+		return status.Error(codes.InvalidArgument, "routing rule can not use the api outbound")
+	}
+	if generatedRule(rule.RuleTag) {
+		return status.Errorf(codes.InvalidArgument, "ruleTag %q is kept for the rules of the node", rule.RuleTag)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.ruleIndex(rule.RuleTag) >= 0 {
+		return status.Errorf(codes.AlreadyExists, "routing rule %q already exists", rule.RuleTag)
+	}
+
+	// The core only appends rules, one that goes first needs the routing reloaded
+	if first {
+		rules := slices.Insert(slices.Clone(x.config.RouterConfig.RuleList), slices.IndexFunc(x.config.RouterConfig.RuleList, isAPIRule)+1, json.RawMessage(config))
+		err = x.reloadRouting(ctx, rules, x.config.RouterConfig.Balancers)
+	} else {
+		err = x.appendRouting(ctx, []json.RawMessage{json.RawMessage(config)}, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("routing rule added", "rule_tag", rule.RuleTag, "outbound", rule.OutboundTag, "balancer", rule.BalancerTag)
+	return nil
+}
+
+// RemoveRoutingRule removes a rule from the running core and the stored config.
+func (x *Xray) RemoveRoutingRule(ctx context.Context, ruleTag string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.ruleIndex(ruleTag)
+	if index < 0 || ruleTag == "" {
+		return status.Errorf(codes.NotFound, "routing rule %q not found", ruleTag)
+	}
+	if generatedRule(ruleTag) {
+		return status.Errorf(codes.FailedPrecondition, "routing rule %q is generated from users or blocklists", ruleTag)
+	}
+	if err := x.handler.RemoveRule(ctx, ruleTag); err != nil {
+		return err
+	}
+
+	x.config.RouterConfig.RuleList = slices.Delete(slices.Clone(x.config.RouterConfig.RuleList), index, index+1)
+	logger.Info("routing rule removed", "rule_tag", ruleTag)
+	return nil
+}
+
+// AddBalancer adds a balancer to the running core and the stored config.
+func (x *Xray) AddBalancer(ctx context.Context, config string) error {
+	balancer := &conf.BalancingRule{}
+	if err := json.Unmarshal([]byte(config), balancer); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid balancer: %v", err)
+	}
+	if balancer.Tag == "" {
+		return status.Error(codes.InvalidArgument, "balancer needs a tag")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.balancerIndex(balancer.Tag) >= 0 {
+		return status.Errorf(codes.AlreadyExists, "balancer %q already exists", balancer.Tag)
+	}
+
+	if err := x.appendRouting(ctx, nil, []*conf.BalancingRule{balancer}); err != nil {
+		return err
+	}
+
+	logger.Info("balancer added", "tag", balancer.Tag, "selector", balancer.Selectors)
+	return nil
+}
+
+// RemoveBalancer removes a balancer no rule uses from the running core and the stored config.
+func (x *Xray) RemoveBalancer(ctx context.Context, tag string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	index := x.balancerIndex(tag)
+	if index < 0 {
+		return status.Errorf(codes.NotFound, "balancer %q not found", tag)
+	}
+	for _, raw := range x.config.RouterConfig.RuleList {
+		if rule, err := parseRoutingRule(raw); err == nil && rule.BalancerTag == tag {
+			return status.Errorf(codes.FailedPrecondition, "balancer %q is used by routing rule %q", tag, rule.RuleTag)
+		}
+	}
+
+	balancers := slices.Delete(slices.Clone(x.config.RouterConfig.Balancers), index, index+1)
+	if err := x.reloadRouting(ctx, x.config.RouterConfig.RuleList, balancers); err != nil {
+		return err
+	}
+
+	logger.Info("balancer removed", "tag", tag)
+	return nil
+}
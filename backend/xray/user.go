@@ -3,12 +3,15 @@ package xray
 import (
 	"context"
 	"errors"
-	"log"
 	"slices"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/backend/xray/api"
 	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/tracing"
 )
 
 func setupUserAccount(user *common.User) (api.ProxySettings, error) {
@@ -126,13 +129,34 @@ func isActiveInbound(inbound *Inbound, inbounds []string, settings api.ProxySett
 }
 
 func (x *Xray) SyncUser(ctx context.Context, user *common.User) error {
+	rerouted, err := x.syncUser(ctx, user)
+	if rerouted {
+		err = errors.Join(err, x.routeUsers(ctx))
+	}
+	return err
+}
+
+// syncUser syncs a user to the inbounds of the core, rerouted reports whether its outbound tag changed.
+func (x *Xray) syncUser(ctx context.Context, user *common.User) (rerouted bool, err error) {
 	proxySetting, err := setupUserAccount(user)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	x.mu.Lock()
+	if err = backend.CheckUserRoutes([]*common.User{user}, x.routeExists); err != nil {
+		x.mu.Unlock()
+		return false, err
+	}
 	handler := x.handler
 	inbounds := x.config.InboundConfigs
+	rerouted = backend.UserRoute(x.users[user.GetEmail()]) != backend.UserRoute(user)
+	if len(user.GetInbounds()) == 0 {
+		delete(x.users, user.GetEmail())
+	} else {
+		x.users[user.GetEmail()] = user
+	}
+	x.mu.Unlock()
 
 	var errMessage string
 
@@ -149,7 +173,7 @@ func (x *Xray) SyncUser(ctx context.Context, user *common.User) error {
 			inbound.updateUser(account)
 			err = handler.AddInboundUser(ctx, inbound.Tag, account)
 			if err != nil {
-				log.Println(err)
+				logger.Error("failed to add inbound user", "inbound", inbound.Tag, "email", user.GetEmail(), "error", err)
 				errMessage += "\n" + err.Error()
 			}
 		} else {
@@ -158,15 +182,69 @@ func (x *Xray) SyncUser(ctx context.Context, user *common.User) error {
 	}
 
 	if errMessage != "" {
-		return errors.New("failed to add user:" + errMessage)
+		return rerouted, errors.New("failed to add user:" + errMessage)
 	}
-	return nil
+	return rerouted, nil
 }
 
-func (x *Xray) SyncUsers(_ context.Context, users []*common.User) error {
-	x.config.syncUsers(users)
-	if err := x.Restart(); err != nil {
+func (x *Xray) setUsers(users []*common.User) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.users = usersByEmail(users)
+}
+
+func usersByEmail(users []*common.User) map[string]*common.User {
+	byEmail := make(map[string]*common.User, len(users))
+	for _, user := range users {
+		byEmail[user.GetEmail()] = user
+	}
+	return byEmail
+}
+
+// PatchUsers syncs the users one by one through the api, the core keeps running.
+func (x *Xray) PatchUsers(ctx context.Context, users []*common.User) error {
+	x.mu.RLock()
+	err := backend.CheckUserRoutes(users, x.routeExists)
+	x.mu.RUnlock()
+	if err != nil {
 		return err
 	}
+
+	var errs []error
+	var rerouted bool
+	for _, user := range users {
+		changed, err := x.syncUser(ctx, user)
+		rerouted = rerouted || changed
+		errs = append(errs, err)
+	}
+	if rerouted {
+		errs = append(errs, x.routeUsers(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// SyncUsers restarts the core with the users, when it fails to start it runs the users it had before again.
+func (x *Xray) SyncUsers(ctx context.Context, users []*common.User) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if err := backend.CheckUserRoutes(users, x.routeExists); err != nil {
+		return err
+	}
+	previous, err := x.config.clone()
+	if err != nil {
+		return err
+	}
+	previousUsers := x.users
+
+	_, span := tracing.Start(ctx, "xray.config.syncUsers", attribute.Int("users", len(users)))
+	x.config.syncUsers(users)
+	x.users = usersByEmail(users)
+	span.End()
+
+	if err = x.restart(ctx); err != nil {
+		x.config, x.users = previous, previousUsers
+		return x.rollback(ctx, err)
+	}
 	return nil
 }
@@ -0,0 +1,75 @@
+package xray
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/tools"
+)
+
+// Failed to start: main: failed to load config files: [stdin:] > infra/conf: invalid port: abc
+var checkErrorPattern = regexp.MustCompile(`(?i)fail|error|invalid`)
+
+// Validate checks the config in the context with `xray run -test` without starting it. The api and users are
+// applied like on start, so the config checked is the one that would run. The api gets a free port of its own,
+// the one of the running core stays with it.
+func Validate(ctx context.Context, cfg *config.Config) (*common.ValidateConfigResponse, error) {
+	xrayConfig, ok := ctx.Value(backend.ConfigKey{}).(*Config)
+	if !ok {
+		return nil, errors.New("xray config has not been initialized")
+	}
+
+	executableAbsolutePath, err := filepath.Abs(cfg.XrayExecutablePath)
+	if err != nil {
+		return nil, err
+	}
+	assetsAbsolutePath, err := filepath.Abs(cfg.XrayAssetsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	core := &Core{executablePath: executableAbsolutePath, assetsPath: assetsAbsolutePath}
+	version, err := core.refreshVersion()
+	if err != nil {
+		return nil, err
+	}
+	response := &common.ValidateConfigResponse{CoreVersion: version}
+
+	if err = xrayConfig.ApplyAPI(tools.FindFreePort()); err != nil {
+		response.Errors = append(response.Errors, &common.ConfigError{Source: common.ConfigErrorSource_CONFIG_PARSE, Message: err.Error()})
+		return response, nil
+	}
+	users, _ := ctx.Value(backend.UsersKey{}).([]*common.User)
+	xrayConfig.syncUsers(users)
+	blocklists, _ := ctx.Value(backend.BlocklistsKey{}).([]*common.Blocklist)
+	xrayConfig.applyBlocklists(blocklists)
+	if xrayConfig.LogConfig != nil {
+		xrayConfig.RemoveLogFiles()
+	}
+
+	bytesConfig, err := xrayConfig.ToBytes()
+	if err != nil {
+		response.Errors = append(response.Errors, &common.ConfigError{Source: common.ConfigErrorSource_CONFIG_PARSE, Message: err.Error()})
+		return response, nil
+	}
+
+	response.Errors, err = backend.CheckConfig(ctx, func(ctx context.Context) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, core.executablePath, "run", "-test", "-c", "stdin:")
+		cmd.Env = append(os.Environ(), "XRAY_LOCATION_ASSET="+core.assetsPath)
+		cmd.Stdin = bytes.NewReader(bytesConfig)
+		return cmd
+	}, checkErrorPattern)
+	if err != nil {
+		return nil, err
+	}
+	response.Valid = len(response.Errors) == 0
+	return response, nil
+}
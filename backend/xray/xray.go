@@ -3,26 +3,44 @@ package xray
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/backend/xray/api"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tracing"
 )
 
 type Xray struct {
-	config     *Config
-	cfg        *config.Config
-	core       *Core
-	handler    *api.XrayHandler
+	config   *Config
+	cfg      *config.Config
+	core     *Core
+	handler  *api.XrayHandler
+	restarts *backend.Breaker
+	// users by email, for inbounds added at runtime
+	users      map[string]*common.User
 	cancelFunc context.CancelFunc
 	mu         sync.RWMutex
 }
 
+// SetAssetLocation points the xray packages of the node at the assets of the core. Routing rules changed
+// at runtime are built in the node, their geosite and geoip entries are read from there.
+func SetAssetLocation(assetsPath string) error {
+	absolutePath, err := filepath.Abs(assetsPath)
+	if err != nil {
+		return err
+	}
+	return os.Setenv("XRAY_LOCATION_ASSET", absolutePath)
+}
+
 func NewXray(ctx context.Context, port int, cfg *config.Config) (*Xray, error) {
 	executableAbsolutePath, err := filepath.Abs(cfg.XrayExecutablePath)
 	if err != nil {
@@ -44,6 +62,7 @@ func NewXray(ctx context.Context, port int, cfg *config.Config) (*Xray, error) {
 	xray := &Xray{
 		cancelFunc: xCancel,
 		cfg:        cfg,
+		restarts:   backend.NewBreakerFromConfig(cfg),
 	}
 
 	start := time.Now()
@@ -53,29 +72,37 @@ func NewXray(ctx context.Context, port int, cfg *config.Config) (*Xray, error) {
 		return nil, errors.New("xray config has not been initialized")
 	}
 
+	_, span := tracing.Start(ctx, "xray.config.generate")
 	if err = xrayConfig.ApplyAPI(port); err != nil {
+		tracing.End(span, err)
 		return nil, err
 	}
 
 	users := ctx.Value(backend.UsersKey{}).([]*common.User)
 	xrayConfig.syncUsers(users)
+	xray.setUsers(users)
+	blocklists, _ := ctx.Value(backend.BlocklistsKey{}).([]*common.Blocklist)
+	xrayConfig.applyBlocklists(blocklists)
+	span.SetAttributes(attribute.Int("users", len(users)))
+	span.End()
 
 	xray.config = xrayConfig
 
-	log.Println("config generated in", time.Since(start).Seconds(), "second.")
+	logger.Info("config generated", "duration", time.Since(start))
 
 	core, err := NewXRayCore(executableAbsolutePath, assetsAbsolutePath, configAbsolutePath, cfg.LogBufferSize)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = core.Start(xrayConfig, cfg.Debug); err != nil {
+	if err = core.Start(ctx, xrayConfig, cfg.Debug); err != nil {
 		return nil, err
 	}
 
 	xray.core = core
 
-	if err = xray.checkXrayStatus(); err != nil {
+	if err = xray.checkXrayStatus(ctx); err != nil {
+		publishEvent(common.EventType_CORE_START_FAILED, common.EventSeverity_CRITICAL, err.Error(), nil)
 		xray.Shutdown()
 		return nil, err
 	}
@@ -88,12 +115,13 @@ func NewXray(ctx context.Context, port int, cfg *config.Config) (*Xray, error) {
 	xray.handler = handler
 	go xray.checkXrayHealth(xCtx)
 
-	log.Println("xray started, Version:", xray.Version())
+	logger.Info("xray started", "backend", "xray", "version", xray.Version())
+	publishEvent(common.EventType_CORE_STARTED, common.EventSeverity_INFO, "xray started", map[string]string{"version": xray.Version()})
 
 	return xray, nil
 }
 
-func (x *Xray) Logs() chan string {
+func (x *Xray) Logs() *nodeLogger.Hub {
 	x.mu.RLock()
 	defer x.mu.RUnlock()
 	return x.core.Logs()
@@ -111,13 +139,35 @@ func (x *Xray) Started() bool {
 	return x.core.Started()
 }
 
+func (x *Xray) Restarts() (uint32, common.BreakerState) {
+	return x.restarts.State()
+}
+
 func (x *Xray) Restart() error {
 	x.mu.Lock()
 	defer x.mu.Unlock()
-	if err := x.core.Restart(x.config, x.cfg.Debug); err != nil {
+	return x.restart(context.Background())
+}
+
+// restart restarts the core with the config and waits for it to start, x.mu must be held.
+func (x *Xray) restart(ctx context.Context) error {
+	if err := x.core.Restart(ctx, x.config, x.cfg.Debug); err != nil {
 		return err
 	}
-	return nil
+	return x.checkXrayStatus(ctx)
+}
+
+// rollback starts the core again after a changed config failed to start, the caller restored the config
+// it ran before so the node keeps serving. cause is returned, x.mu must be held.
+func (x *Xray) rollback(ctx context.Context, cause error) error {
+	logger.Warn("xray failed to restart, rolling back to the last config that ran", "error", cause)
+	if err := x.restart(ctx); err != nil {
+		return fmt.Errorf("%w, rollback failed: %v", cause, err)
+	}
+
+	publishEvent(common.EventType_CONFIG_ROLLBACK, common.EventSeverity_CRITICAL,
+		"xray failed to restart, rolled back to the last config that ran", map[string]string{"error": cause.Error()})
+	return cause
 }
 
 func (x *Xray) Shutdown() {
@@ -129,7 +179,7 @@ func (x *Xray) Shutdown() {
 
 	// Stop core (this now waits for process termination)
 	if x.core != nil {
-		x.core.Stop()
+		x.core.Stop(context.Background())
 	}
 
 	// Close API handler
@@ -4,14 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+	nodeLogger "github.com/pasarguard/node/logger"
 	"github.com/pasarguard/node/tools"
 )
 
@@ -133,16 +138,17 @@ func TestXrayBackend(t *testing.T) {
 	ctx1, cancel = context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	logs := back.Logs()
+	_, logs := back.Logs().Subscribe(nodeLogger.SubscriberBuffer, nodeLogger.Replay{}, nil)
+	defer logs.Close()
 loop:
 	for {
 		select {
-		case newLog, ok := <-logs:
+		case newLog, ok := <-logs.C:
 			if !ok {
 				log.Println("channel closed")
 				break loop
 			}
-			fmt.Println(newLog)
+			fmt.Println(newLog.Text)
 		case <-ctx1.Done():
 			break loop
 		}
@@ -150,3 +156,158 @@ loop:
 
 	back.Shutdown()
 }
+
+func TestOutbound_JSON(t *testing.T) {
+	config, err := NewXRayConfig(`{"outbounds": [{"protocol": "freedom", "tag": "direct", "settings": {"domainStrategy": "UseIPv4"}}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.OutboundConfigs) != 1 || config.OutboundConfigs[0].Tag != "direct" || config.OutboundConfigs[0].Protocol != "freedom" {
+		t.Fatalf("unexpected outbounds: %+v", config.OutboundConfigs)
+	}
+
+	// Fields the node does not decode are kept
+	data, err := config.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"domainStrategy":"UseIPv4"`) {
+		t.Fatalf("outbound settings were lost: %s", data)
+	}
+
+	if _, _, err = parseOutbound(`{"protocol": "freedom"}`); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an outbound without a tag to be rejected, got %v", err)
+	}
+	if _, _, err = parseOutbound(`{"protocol": "no-such-protocol", "tag": "x"}`); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an unknown protocol to be rejected, got %v", err)
+	}
+}
+
+func TestConfig_UserRoutes(t *testing.T) {
+	config, err := NewXRayConfig(`{"inbounds": [{"tag": "in", "protocol": "vmess", "settings": {"clients": []}}], "routing": {"rules": [{"ruleTag": "config", "network": "tcp", "outboundTag": "direct"}]}}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.ApplyAPI(8080); err != nil {
+		t.Fatal(err)
+	}
+
+	user := func(email, outboundTag string) *common.User {
+		return &common.User{Email: email, Inbounds: []string{"in"}, OutboundTag: outboundTag}
+	}
+	config.syncUsers([]*common.User{user("b@example.com", "premium"), user("a@example.com", "premium"), user("c@example.com", "")})
+
+	rules := config.RouterConfig.RuleList
+	if len(rules) != 3 || !isAPIRule(rules[0]) {
+		t.Fatalf("expected the api rule, a user rule and the config rule, got %s", rules)
+	}
+	if got := string(rules[1]); got != `{"outboundTag":"premium","ruleTag":"user-route:premium","type":"field","user":["a@example.com","b@example.com"]}` {
+		t.Fatalf("unexpected user rule %s", got)
+	}
+
+	// Syncing again replaces the generated rules, users without a tag or inbounds are not routed
+	config.syncUsers([]*common.User{user("a@example.com", ""), {Email: "b@example.com", OutboundTag: "premium"}})
+	if rules = config.RouterConfig.RuleList; len(rules) != 2 || slices.ContainsFunc(rules, isUserRule) {
+		t.Fatalf("expected the user rule to be gone, got %s", rules)
+	}
+}
+
+func TestConfig_Blocklists(t *testing.T) {
+	config, err := NewXRayConfig(`{"inbounds": [{"tag": "in", "protocol": "vmess", "settings": {"clients": []}}], "outbounds": [{"tag": "direct", "protocol": "freedom"}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.ApplyAPI(8080); err != nil {
+		t.Fatal(err)
+	}
+	config.syncUsers([]*common.User{{Email: "a@example.com", Inbounds: []string{"in"}, OutboundTag: "direct"}})
+	config.applyBlocklists([]*common.Blocklist{{Name: "ads", Domains: []string{"ads.example.com"}, Geosite: []string{"category-ads"}, Cidrs: []string{"192.0.2.0/24"}}})
+
+	rules := config.RouterConfig.RuleList
+	if len(rules) != 4 || !isAPIRule(rules[0]) || !isBlocklistRule(rules[1]) || !isBlocklistRule(rules[2]) || !isUserRule(rules[3]) {
+		t.Fatalf("expected the api rule, the blocklist rules and the user rule in that order, got %s", rules)
+	}
+	if got := string(rules[1]); got != `{"domain":["domain:ads.example.com","geosite:category-ads"],"outboundTag":"blocklist:ads","ruleTag":"blocklist:ads:domain","type":"field"}` {
+		t.Fatalf("unexpected domain rule %s", got)
+	}
+	outbounds := config.OutboundConfigs
+	if len(outbounds) != 2 || outbounds[0].Tag != "direct" || outbounds[1].Tag != "blocklist:ads" || outbounds[1].Protocol != "blackhole" {
+		t.Fatalf("expected the blocklist outbound after the default one, got %+v", outbounds)
+	}
+
+	// Users synced later keep their rules after the blocklists, new lists replace the old ones
+	config.syncUsers([]*common.User{{Email: "b@example.com", Inbounds: []string{"in"}, OutboundTag: "direct"}})
+	config.applyBlocklists(nil)
+	if rules = config.RouterConfig.RuleList; len(rules) != 2 || !isUserRule(rules[1]) || len(config.OutboundConfigs) != 1 {
+		t.Fatalf("expected the blocklists to be gone, got %s and %+v", rules, config.OutboundConfigs)
+	}
+}
+
+func TestXray_SyncUsersRollback(t *testing.T) {
+	xrayFile, err := tools.ReadFileAsString(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newConfig, err := NewXRayConfig(xrayFile, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := func(email string) *common.User {
+		return &common.User{
+			Email:    email,
+			Inbounds: []string{"VLESS TCP NOTLS"},
+			Proxies:  &common.Proxy{Vless: &common.Vless{Id: uuid.New().String()}},
+		}
+	}
+	cfg := &config.Config{
+		XrayExecutablePath:  executablePath,
+		XrayAssetsPath:      assetsPath,
+		GeneratedConfigPath: configPath,
+		LogBufferSize:       1000,
+	}
+	ctx := context.WithValue(context.Background(), backend.ConfigKey{}, newConfig)
+	ctx = context.WithValue(ctx, backend.UsersKey{}, []*common.User{user("a@example.com")})
+
+	back, err := NewXray(ctx, tools.FindFreePort(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer back.Shutdown()
+
+	// The core refuses two users with the same email, the users it ran before keep being served
+	if err = back.SyncUsers(context.Background(), []*common.User{user("b@example.com"), user("b@example.com")}); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	if !back.Started() {
+		t.Fatal("xray was not started again with the previous users")
+	}
+	if _, ok := back.users["a@example.com"]; !ok || len(back.users) != 1 {
+		t.Fatalf("expected the previous users to be restored, got %v", back.users)
+	}
+	data, err := back.config.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "a@example.com") || strings.Contains(string(data), "b@example.com") {
+		t.Fatal("expected the config to hold the previous users")
+	}
+}
+
+func TestXray_RouteUsersUnchanged(t *testing.T) {
+	config, err := NewXRayConfig(`{"inbounds": [{"tag": "in", "protocol": "vmess", "settings": {"clients": []}}], "outbounds": [{"tag": "premium", "protocol": "freedom"}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.ApplyAPI(8080); err != nil {
+		t.Fatal(err)
+	}
+	users := []*common.User{{Email: "a@example.com", Inbounds: []string{"in"}, OutboundTag: "premium"}}
+	config.syncUsers(users)
+
+	// Without a handler reloading the routing would panic, the same rules must leave the core alone
+	x := &Xray{config: config, users: usersByEmail(users)}
+	if err = x.routeUsers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -22,6 +22,57 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// automatic restarts of a crashed core stop while the breaker is open, the backend is degraded
+type BreakerState int32
+
+const (
+	BreakerState_BREAKER_CLOSED BreakerState = 0
+	BreakerState_BREAKER_OPEN   BreakerState = 1
+	// a single restart is tried after the cooldown
+	BreakerState_BREAKER_HALF_OPEN BreakerState = 2
+)
+
+// Enum value maps for BreakerState.
+var (
+	BreakerState_name = map[int32]string{
+		0: "BREAKER_CLOSED",
+		1: "BREAKER_OPEN",
+		2: "BREAKER_HALF_OPEN",
+	}
+	BreakerState_value = map[string]int32{
+		"BREAKER_CLOSED":    0,
+		"BREAKER_OPEN":      1,
+		"BREAKER_HALF_OPEN": 2,
+	}
+)
+
+func (x BreakerState) Enum() *BreakerState {
+	p := new(BreakerState)
+	*p = x
+	return p
+}
+
+func (x BreakerState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BreakerState) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[0].Descriptor()
+}
+
+func (BreakerState) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[0]
+}
+
+func (x BreakerState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BreakerState.Descriptor instead.
+func (BreakerState) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{0}
+}
+
 type BackendType int32
 
 const (
@@ -52,11 +103,11 @@ func (x BackendType) String() string {
 }
 
 func (BackendType) Descriptor() protoreflect.EnumDescriptor {
-	return file_common_service_proto_enumTypes[0].Descriptor()
+	return file_common_service_proto_enumTypes[1].Descriptor()
 }
 
 func (BackendType) Type() protoreflect.EnumType {
-	return &file_common_service_proto_enumTypes[0]
+	return &file_common_service_proto_enumTypes[1]
 }
 
 func (x BackendType) Number() protoreflect.EnumNumber {
@@ -65,7 +116,156 @@ func (x BackendType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use BackendType.Descriptor instead.
 func (BackendType) EnumDescriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{0}
+	return file_common_service_proto_rawDescGZIP(), []int{1}
+}
+
+type LogLevel int32
+
+const (
+	LogLevel_LOG_DEBUG   LogLevel = 0
+	LogLevel_LOG_INFO    LogLevel = 1
+	LogLevel_LOG_WARNING LogLevel = 2
+	LogLevel_LOG_ERROR   LogLevel = 3
+)
+
+// Enum value maps for LogLevel.
+var (
+	LogLevel_name = map[int32]string{
+		0: "LOG_DEBUG",
+		1: "LOG_INFO",
+		2: "LOG_WARNING",
+		3: "LOG_ERROR",
+	}
+	LogLevel_value = map[string]int32{
+		"LOG_DEBUG":   0,
+		"LOG_INFO":    1,
+		"LOG_WARNING": 2,
+		"LOG_ERROR":   3,
+	}
+)
+
+func (x LogLevel) Enum() *LogLevel {
+	p := new(LogLevel)
+	*p = x
+	return p
+}
+
+func (x LogLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[2].Descriptor()
+}
+
+func (LogLevel) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[2]
+}
+
+func (x LogLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogLevel.Descriptor instead.
+func (LogLevel) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{2}
+}
+
+type LogType int32
+
+const (
+	LogType_ALL_LOGS    LogType = 0
+	LogType_ACCESS_LOGS LogType = 1
+	LogType_ERROR_LOGS  LogType = 2
+)
+
+// Enum value maps for LogType.
+var (
+	LogType_name = map[int32]string{
+		0: "ALL_LOGS",
+		1: "ACCESS_LOGS",
+		2: "ERROR_LOGS",
+	}
+	LogType_value = map[string]int32{
+		"ALL_LOGS":    0,
+		"ACCESS_LOGS": 1,
+		"ERROR_LOGS":  2,
+	}
+)
+
+func (x LogType) Enum() *LogType {
+	p := new(LogType)
+	*p = x
+	return p
+}
+
+func (x LogType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[3].Descriptor()
+}
+
+func (LogType) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[3]
+}
+
+func (x LogType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogType.Descriptor instead.
+func (LogType) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{3}
+}
+
+type ConfigErrorSource int32
+
+const (
+	// the node could not parse or generate the config
+	ConfigErrorSource_CONFIG_PARSE ConfigErrorSource = 0
+	// the core rejected the generated config
+	ConfigErrorSource_CONFIG_CORE ConfigErrorSource = 1
+)
+
+// Enum value maps for ConfigErrorSource.
+var (
+	ConfigErrorSource_name = map[int32]string{
+		0: "CONFIG_PARSE",
+		1: "CONFIG_CORE",
+	}
+	ConfigErrorSource_value = map[string]int32{
+		"CONFIG_PARSE": 0,
+		"CONFIG_CORE":  1,
+	}
+)
+
+func (x ConfigErrorSource) Enum() *ConfigErrorSource {
+	p := new(ConfigErrorSource)
+	*p = x
+	return p
+}
+
+func (x ConfigErrorSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConfigErrorSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[4].Descriptor()
+}
+
+func (ConfigErrorSource) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[4]
+}
+
+func (x ConfigErrorSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConfigErrorSource.Descriptor instead.
+func (ConfigErrorSource) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{4}
 }
 
 type StatType int32
@@ -110,11 +310,11 @@ func (x StatType) String() string {
 }
 
 func (StatType) Descriptor() protoreflect.EnumDescriptor {
-	return file_common_service_proto_enumTypes[1].Descriptor()
+	return file_common_service_proto_enumTypes[5].Descriptor()
 }
 
 func (StatType) Type() protoreflect.EnumType {
-	return &file_common_service_proto_enumTypes[1]
+	return &file_common_service_proto_enumTypes[5]
 }
 
 func (x StatType) Number() protoreflect.EnumNumber {
@@ -123,7 +323,133 @@ func (x StatType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use StatType.Descriptor instead.
 func (StatType) EnumDescriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{1}
+	return file_common_service_proto_rawDescGZIP(), []int{5}
+}
+
+// events
+type EventType int32
+
+const (
+	EventType_UNKNOWN_EVENT           EventType = 0
+	EventType_CORE_STARTED            EventType = 1
+	EventType_CORE_START_FAILED       EventType = 2
+	EventType_CORE_CRASHED            EventType = 3
+	EventType_CORE_RESTARTED          EventType = 4
+	EventType_CORE_RESTART_FAILED     EventType = 5
+	EventType_ORPHAN_CLEANUP          EventType = 6
+	EventType_CERT_EXPIRING           EventType = 7
+	EventType_CERT_EXPIRED            EventType = 8
+	EventType_CORE_CRASH_LOOP         EventType = 9
+	EventType_BANDWIDTH_QUOTA_REACHED EventType = 10
+	EventType_CONFIG_ROLLBACK         EventType = 11
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0:  "UNKNOWN_EVENT",
+		1:  "CORE_STARTED",
+		2:  "CORE_START_FAILED",
+		3:  "CORE_CRASHED",
+		4:  "CORE_RESTARTED",
+		5:  "CORE_RESTART_FAILED",
+		6:  "ORPHAN_CLEANUP",
+		7:  "CERT_EXPIRING",
+		8:  "CERT_EXPIRED",
+		9:  "CORE_CRASH_LOOP",
+		10: "BANDWIDTH_QUOTA_REACHED",
+		11: "CONFIG_ROLLBACK",
+	}
+	EventType_value = map[string]int32{
+		"UNKNOWN_EVENT":           0,
+		"CORE_STARTED":            1,
+		"CORE_START_FAILED":       2,
+		"CORE_CRASHED":            3,
+		"CORE_RESTARTED":          4,
+		"CORE_RESTART_FAILED":     5,
+		"ORPHAN_CLEANUP":          6,
+		"CERT_EXPIRING":           7,
+		"CERT_EXPIRED":            8,
+		"CORE_CRASH_LOOP":         9,
+		"BANDWIDTH_QUOTA_REACHED": 10,
+		"CONFIG_ROLLBACK":         11,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[6].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[6]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{6}
+}
+
+type EventSeverity int32
+
+const (
+	EventSeverity_INFO     EventSeverity = 0
+	EventSeverity_WARNING  EventSeverity = 1
+	EventSeverity_CRITICAL EventSeverity = 2
+)
+
+// Enum value maps for EventSeverity.
+var (
+	EventSeverity_name = map[int32]string{
+		0: "INFO",
+		1: "WARNING",
+		2: "CRITICAL",
+	}
+	EventSeverity_value = map[string]int32{
+		"INFO":     0,
+		"WARNING":  1,
+		"CRITICAL": 2,
+	}
+)
+
+func (x EventSeverity) Enum() *EventSeverity {
+	p := new(EventSeverity)
+	*p = x
+	return p
+}
+
+func (x EventSeverity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventSeverity) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_service_proto_enumTypes[7].Descriptor()
+}
+
+func (EventSeverity) Type() protoreflect.EnumType {
+	return &file_common_service_proto_enumTypes[7]
+}
+
+func (x EventSeverity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventSeverity.Descriptor instead.
+func (EventSeverity) EnumDescriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{7}
 }
 
 type Empty struct {
@@ -164,10 +490,15 @@ func (*Empty) Descriptor() ([]byte, []int) {
 
 // Base info response message
 type BaseInfoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Started       bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"`
-	CoreVersion   string                 `protobuf:"bytes,2,opt,name=core_version,json=coreVersion,proto3" json:"core_version,omitempty"`
-	NodeVersion   string                 `protobuf:"bytes,3,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Started     bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"`
+	CoreVersion string                 `protobuf:"bytes,2,opt,name=core_version,json=coreVersion,proto3" json:"core_version,omitempty"`
+	NodeVersion string                 `protobuf:"bytes,3,opt,name=node_version,json=nodeVersion,proto3" json:"node_version,omitempty"`
+	// automatic restarts of the running backend
+	RestartCount uint32       `protobuf:"varint,4,opt,name=restart_count,json=restartCount,proto3" json:"restart_count,omitempty"`
+	BreakerState BreakerState `protobuf:"varint,5,opt,name=breaker_state,json=breakerState,proto3,enum=service.BreakerState" json:"breaker_state,omitempty"`
+	// set when the config of the start failed and the last config that started runs instead
+	Rollback      *ConfigRollback `protobuf:"bytes,6,opt,name=rollback,proto3" json:"rollback,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -223,6 +554,27 @@ func (x *BaseInfoResponse) GetNodeVersion() string {
 	return ""
 }
 
+func (x *BaseInfoResponse) GetRestartCount() uint32 {
+	if x != nil {
+		return x.RestartCount
+	}
+	return 0
+}
+
+func (x *BaseInfoResponse) GetBreakerState() BreakerState {
+	if x != nil {
+		return x.BreakerState
+	}
+	return BreakerState_BREAKER_CLOSED
+}
+
+func (x *BaseInfoResponse) GetRollback() *ConfigRollback {
+	if x != nil {
+		return x.Rollback
+	}
+	return nil
+}
+
 type Backend struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Type            BackendType            `protobuf:"varint,1,opt,name=type,proto3,enum=service.BackendType" json:"type,omitempty"`
@@ -301,8 +653,12 @@ func (x *Backend) GetExcludeInbounds() []string {
 
 // log
 type Log struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Detail        string                 `protobuf:"bytes,1,opt,name=detail,proto3" json:"detail,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Detail string                 `protobuf:"bytes,1,opt,name=detail,proto3" json:"detail,omitempty"`
+	// unix milliseconds the node captured the line at
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// lines this stream missed so far because it was not keeping up
+	Dropped       uint64 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -344,31 +700,55 @@ func (x *Log) GetDetail() string {
 	return ""
 }
 
-// stats
-type Stat struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	Link          string                 `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
-	Value         int64                  `protobuf:"varint,4,opt,name=value,proto3" json:"value,omitempty"`
+func (x *Log) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Log) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+// tail and since select buffered lines sent before the live ones, both zero sends only new lines
+// the other fields filter both buffered and live lines, zero values disable a filter
+type LogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tail  uint32                 `protobuf:"varint,1,opt,name=tail,proto3" json:"tail,omitempty"`
+	// unix milliseconds
+	Since int64 `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+	// minimum level, access logs are info
+	Level LogLevel `protobuf:"varint,3,opt,name=level,proto3,enum=service.LogLevel" json:"level,omitempty"`
+	// error logs are every line that is not an access log
+	Type LogType `protobuf:"varint,4,opt,name=type,proto3,enum=service.LogType" json:"type,omitempty"`
+	// user email of access logs
+	Email string `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	// inbound tag of access logs
+	Inbound string `protobuf:"bytes,6,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	// RE2 regular expression matched against the raw line
+	Pattern       string `protobuf:"bytes,7,opt,name=pattern,proto3" json:"pattern,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Stat) Reset() {
-	*x = Stat{}
+func (x *LogRequest) Reset() {
+	*x = LogRequest{}
 	mi := &file_common_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Stat) String() string {
+func (x *LogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Stat) ProtoMessage() {}
+func (*LogRequest) ProtoMessage() {}
 
-func (x *Stat) ProtoReflect() protoreflect.Message {
+func (x *LogRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -380,60 +760,92 @@ func (x *Stat) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Stat.ProtoReflect.Descriptor instead.
-func (*Stat) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogRequest.ProtoReflect.Descriptor instead.
+func (*LogRequest) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Stat) GetName() string {
+func (x *LogRequest) GetTail() uint32 {
 	if x != nil {
-		return x.Name
+		return x.Tail
 	}
-	return ""
+	return 0
 }
 
-func (x *Stat) GetType() string {
+func (x *LogRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *LogRequest) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_LOG_DEBUG
+}
+
+func (x *LogRequest) GetType() LogType {
 	if x != nil {
 		return x.Type
 	}
+	return LogType_ALL_LOGS
+}
+
+func (x *LogRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
 	return ""
 }
 
-func (x *Stat) GetLink() string {
+func (x *LogRequest) GetInbound() string {
 	if x != nil {
-		return x.Link
+		return x.Inbound
 	}
 	return ""
 }
 
-func (x *Stat) GetValue() int64 {
+func (x *LogRequest) GetPattern() string {
 	if x != nil {
-		return x.Value
+		return x.Pattern
 	}
-	return 0
+	return ""
 }
 
-type StatResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         []*Stat                `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// connection parsed from a core access log
+type AccessLog struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// unix milliseconds, from the core when the line carries a time
+	Timestamp  int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	SourceIp   string `protobuf:"bytes,2,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	SourcePort uint32 `protobuf:"varint,3,opt,name=source_port,json=sourcePort,proto3" json:"source_port,omitempty"`
+	// tcp or udp
+	Network         string `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	DestinationHost string `protobuf:"bytes,5,opt,name=destination_host,json=destinationHost,proto3" json:"destination_host,omitempty"`
+	DestinationPort uint32 `protobuf:"varint,6,opt,name=destination_port,json=destinationPort,proto3" json:"destination_port,omitempty"`
+	Inbound         string `protobuf:"bytes,7,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	Outbound        string `protobuf:"bytes,8,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	Email           string `protobuf:"bytes,9,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *StatResponse) Reset() {
-	*x = StatResponse{}
+func (x *AccessLog) Reset() {
+	*x = AccessLog{}
 	mi := &file_common_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatResponse) String() string {
+func (x *AccessLog) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatResponse) ProtoMessage() {}
+func (*AccessLog) ProtoMessage() {}
 
-func (x *StatResponse) ProtoReflect() protoreflect.Message {
+func (x *AccessLog) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -445,41 +857,100 @@ func (x *StatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
-func (*StatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AccessLog.ProtoReflect.Descriptor instead.
+func (*AccessLog) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *StatResponse) GetStats() []*Stat {
+func (x *AccessLog) GetTimestamp() int64 {
 	if x != nil {
-		return x.Stats
+		return x.Timestamp
 	}
-	return nil
+	return 0
 }
 
-type StatRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Reset_        bool                   `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
-	Type          StatType               `protobuf:"varint,3,opt,name=type,proto3,enum=service.StatType" json:"type,omitempty"`
+func (x *AccessLog) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *AccessLog) GetSourcePort() uint32 {
+	if x != nil {
+		return x.SourcePort
+	}
+	return 0
+}
+
+func (x *AccessLog) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *AccessLog) GetDestinationHost() string {
+	if x != nil {
+		return x.DestinationHost
+	}
+	return ""
+}
+
+func (x *AccessLog) GetDestinationPort() uint32 {
+	if x != nil {
+		return x.DestinationPort
+	}
+	return 0
+}
+
+func (x *AccessLog) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+func (x *AccessLog) GetOutbound() string {
+	if x != nil {
+		return x.Outbound
+	}
+	return ""
+}
+
+func (x *AccessLog) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// tail and since select buffered records like in LogRequest, email and inbound filter them
+type AccessLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tail  uint32                 `protobuf:"varint,1,opt,name=tail,proto3" json:"tail,omitempty"`
+	// unix milliseconds
+	Since         int64  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+	Email         string `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Inbound       string `protobuf:"bytes,4,opt,name=inbound,proto3" json:"inbound,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatRequest) Reset() {
-	*x = StatRequest{}
+func (x *AccessLogRequest) Reset() {
+	*x = AccessLogRequest{}
 	mi := &file_common_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatRequest) String() string {
+func (x *AccessLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatRequest) ProtoMessage() {}
+func (*AccessLogRequest) ProtoMessage() {}
 
-func (x *StatRequest) ProtoReflect() protoreflect.Message {
+func (x *AccessLogRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -491,54 +962,75 @@ func (x *StatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
-func (*StatRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AccessLogRequest.ProtoReflect.Descriptor instead.
+func (*AccessLogRequest) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *StatRequest) GetName() string {
+func (x *AccessLogRequest) GetTail() uint32 {
 	if x != nil {
-		return x.Name
+		return x.Tail
 	}
-	return ""
+	return 0
 }
 
-func (x *StatRequest) GetReset_() bool {
+func (x *AccessLogRequest) GetSince() int64 {
 	if x != nil {
-		return x.Reset_
+		return x.Since
 	}
-	return false
+	return 0
 }
 
-func (x *StatRequest) GetType() StatType {
+func (x *AccessLogRequest) GetEmail() string {
 	if x != nil {
-		return x.Type
+		return x.Email
 	}
-	return StatType_Outbounds
+	return ""
 }
 
-type OnlineStatResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+func (x *AccessLogRequest) GetInbound() string {
+	if x != nil {
+		return x.Inbound
+	}
+	return ""
+}
+
+// exit of a core process as recorded by the node
+type CoreExit struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Backend string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	Version string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Pid     uint32                 `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	// unix milliseconds
+	StartedAt int64 `protobuf:"varint,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	ExitedAt  int64 `protobuf:"varint,5,opt,name=exited_at,json=exitedAt,proto3" json:"exited_at,omitempty"`
+	// -1 when the process was killed by a signal
+	ExitCode int32  `protobuf:"varint,6,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Signal   string `protobuf:"bytes,7,opt,name=signal,proto3" json:"signal,omitempty"`
+	// the node stopped the process, it did not crash
+	Expected bool `protobuf:"varint,8,opt,name=expected,proto3" json:"expected,omitempty"`
+	// sha256 of the config the process ran with
+	ConfigHash string `protobuf:"bytes,9,opt,name=config_hash,json=configHash,proto3" json:"config_hash,omitempty"`
+	// last lines the process printed
+	LastLines     []string `protobuf:"bytes,10,rep,name=last_lines,json=lastLines,proto3" json:"last_lines,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OnlineStatResponse) Reset() {
-	*x = OnlineStatResponse{}
+func (x *CoreExit) Reset() {
+	*x = CoreExit{}
 	mi := &file_common_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OnlineStatResponse) String() string {
+func (x *CoreExit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OnlineStatResponse) ProtoMessage() {}
+func (*CoreExit) ProtoMessage() {}
 
-func (x *OnlineStatResponse) ProtoReflect() protoreflect.Message {
+func (x *CoreExit) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -550,47 +1042,103 @@ func (x *OnlineStatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OnlineStatResponse.ProtoReflect.Descriptor instead.
-func (*OnlineStatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CoreExit.ProtoReflect.Descriptor instead.
+func (*CoreExit) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *OnlineStatResponse) GetName() string {
+func (x *CoreExit) GetBackend() string {
 	if x != nil {
-		return x.Name
+		return x.Backend
 	}
 	return ""
 }
 
-func (x *OnlineStatResponse) GetValue() int64 {
+func (x *CoreExit) GetVersion() string {
 	if x != nil {
-		return x.Value
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CoreExit) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
 	}
 	return 0
 }
 
-type StatsOnlineIpListResponse struct {
+func (x *CoreExit) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *CoreExit) GetExitedAt() int64 {
+	if x != nil {
+		return x.ExitedAt
+	}
+	return 0
+}
+
+func (x *CoreExit) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *CoreExit) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *CoreExit) GetExpected() bool {
+	if x != nil {
+		return x.Expected
+	}
+	return false
+}
+
+func (x *CoreExit) GetConfigHash() string {
+	if x != nil {
+		return x.ConfigHash
+	}
+	return ""
+}
+
+func (x *CoreExit) GetLastLines() []string {
+	if x != nil {
+		return x.LastLines
+	}
+	return nil
+}
+
+// oldest first
+type CoreCrashesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Ips           map[string]int64       `protobuf:"bytes,2,rep,name=ips,proto3" json:"ips,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Exits         []*CoreExit            `protobuf:"bytes,1,rep,name=exits,proto3" json:"exits,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsOnlineIpListResponse) Reset() {
-	*x = StatsOnlineIpListResponse{}
+func (x *CoreCrashesResponse) Reset() {
+	*x = CoreCrashesResponse{}
 	mi := &file_common_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsOnlineIpListResponse) String() string {
+func (x *CoreCrashesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsOnlineIpListResponse) ProtoMessage() {}
+func (*CoreCrashesResponse) ProtoMessage() {}
 
-func (x *StatsOnlineIpListResponse) ProtoReflect() protoreflect.Message {
+func (x *CoreCrashesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -602,55 +1150,40 @@ func (x *StatsOnlineIpListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatsOnlineIpListResponse.ProtoReflect.Descriptor instead.
-func (*StatsOnlineIpListResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CoreCrashesResponse.ProtoReflect.Descriptor instead.
+func (*CoreCrashesResponse) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *StatsOnlineIpListResponse) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *StatsOnlineIpListResponse) GetIps() map[string]int64 {
+func (x *CoreCrashesResponse) GetExits() []*CoreExit {
 	if x != nil {
-		return x.Ips
+		return x.Exits
 	}
 	return nil
 }
 
-type BackendStatsResponse struct {
+type ConfigError struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NumGoroutine  uint32                 `protobuf:"varint,1,opt,name=num_goroutine,json=numGoroutine,proto3" json:"num_goroutine,omitempty"`
-	NumGc         uint32                 `protobuf:"varint,2,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
-	Alloc         uint64                 `protobuf:"varint,3,opt,name=alloc,proto3" json:"alloc,omitempty"`
-	TotalAlloc    uint64                 `protobuf:"varint,4,opt,name=total_alloc,json=totalAlloc,proto3" json:"total_alloc,omitempty"`
-	Sys           uint64                 `protobuf:"varint,5,opt,name=sys,proto3" json:"sys,omitempty"`
-	Mallocs       uint64                 `protobuf:"varint,6,opt,name=mallocs,proto3" json:"mallocs,omitempty"`
-	Frees         uint64                 `protobuf:"varint,7,opt,name=frees,proto3" json:"frees,omitempty"`
-	LiveObjects   uint64                 `protobuf:"varint,8,opt,name=live_objects,json=liveObjects,proto3" json:"live_objects,omitempty"`
-	PauseTotalNs  uint64                 `protobuf:"varint,9,opt,name=pause_total_ns,json=pauseTotalNs,proto3" json:"pause_total_ns,omitempty"`
-	Uptime        uint32                 `protobuf:"varint,10,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Source        ConfigErrorSource      `protobuf:"varint,1,opt,name=source,proto3,enum=service.ConfigErrorSource" json:"source,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendStatsResponse) Reset() {
-	*x = BackendStatsResponse{}
+func (x *ConfigError) Reset() {
+	*x = ConfigError{}
 	mi := &file_common_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendStatsResponse) String() string {
+func (x *ConfigError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackendStatsResponse) ProtoMessage() {}
+func (*ConfigError) ProtoMessage() {}
 
-func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
+func (x *ConfigError) ProtoReflect() protoreflect.Message {
 	mi := &file_common_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -662,108 +1195,118 @@ func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackendStatsResponse.ProtoReflect.Descriptor instead.
-func (*BackendStatsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConfigError.ProtoReflect.Descriptor instead.
+func (*ConfigError) Descriptor() ([]byte, []int) {
 	return file_common_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *BackendStatsResponse) GetNumGoroutine() uint32 {
+func (x *ConfigError) GetSource() ConfigErrorSource {
 	if x != nil {
-		return x.NumGoroutine
+		return x.Source
 	}
-	return 0
+	return ConfigErrorSource_CONFIG_PARSE
 }
 
-func (x *BackendStatsResponse) GetNumGc() uint32 {
+func (x *ConfigError) GetMessage() string {
 	if x != nil {
-		return x.NumGc
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *BackendStatsResponse) GetAlloc() uint64 {
-	if x != nil {
-		return x.Alloc
-	}
-	return 0
+type ConfigRollback struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// why the requested config failed to start
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// the version of the config history that runs instead
+	Version       uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	ConfigHash    string `protobuf:"bytes,3,opt,name=config_hash,json=configHash,proto3" json:"config_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendStatsResponse) GetTotalAlloc() uint64 {
-	if x != nil {
-		return x.TotalAlloc
-	}
-	return 0
+func (x *ConfigRollback) Reset() {
+	*x = ConfigRollback{}
+	mi := &file_common_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendStatsResponse) GetSys() uint64 {
-	if x != nil {
-		return x.Sys
-	}
-	return 0
+func (x *ConfigRollback) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *BackendStatsResponse) GetMallocs() uint64 {
+func (*ConfigRollback) ProtoMessage() {}
+
+func (x *ConfigRollback) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[10]
 	if x != nil {
-		return x.Mallocs
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *BackendStatsResponse) GetFrees() uint64 {
-	if x != nil {
-		return x.Frees
-	}
-	return 0
+// Deprecated: Use ConfigRollback.ProtoReflect.Descriptor instead.
+func (*ConfigRollback) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *BackendStatsResponse) GetLiveObjects() uint64 {
+func (x *ConfigRollback) GetError() string {
 	if x != nil {
-		return x.LiveObjects
+		return x.Error
 	}
-	return 0
+	return ""
 }
 
-func (x *BackendStatsResponse) GetPauseTotalNs() uint64 {
+func (x *ConfigRollback) GetVersion() uint64 {
 	if x != nil {
-		return x.PauseTotalNs
+		return x.Version
 	}
 	return 0
 }
 
-func (x *BackendStatsResponse) GetUptime() uint32 {
+func (x *ConfigRollback) GetConfigHash() string {
 	if x != nil {
-		return x.Uptime
+		return x.ConfigHash
 	}
-	return 0
+	return ""
 }
 
-type SystemStatsResponse struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	MemTotal               uint64                 `protobuf:"varint,1,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
-	MemUsed                uint64                 `protobuf:"varint,2,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
-	CpuCores               uint64                 `protobuf:"varint,3,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
-	CpuUsage               float64                `protobuf:"fixed64,4,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
-	IncomingBandwidthSpeed uint64                 `protobuf:"varint,5,opt,name=incoming_bandwidth_speed,json=incomingBandwidthSpeed,proto3" json:"incoming_bandwidth_speed,omitempty"`
-	OutgoingBandwidthSpeed uint64                 `protobuf:"varint,6,opt,name=outgoing_bandwidth_speed,json=outgoingBandwidthSpeed,proto3" json:"outgoing_bandwidth_speed,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+// a config that started, kept in the config history
+type ConfigVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  BackendType            `protobuf:"varint,2,opt,name=type,proto3,enum=service.BackendType" json:"type,omitempty"`
+	// sha256 of the config as sent
+	ConfigHash      string   `protobuf:"bytes,3,opt,name=config_hash,json=configHash,proto3" json:"config_hash,omitempty"`
+	ExcludeInbounds []string `protobuf:"bytes,4,rep,name=exclude_inbounds,json=excludeInbounds,proto3" json:"exclude_inbounds,omitempty"`
+	// unix milliseconds
+	StartedAt int64 `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// left out of the history listing
+	Config        string `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SystemStatsResponse) Reset() {
-	*x = SystemStatsResponse{}
-	mi := &file_common_service_proto_msgTypes[10]
+func (x *ConfigVersion) Reset() {
+	*x = ConfigVersion{}
+	mi := &file_common_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemStatsResponse) String() string {
+func (x *ConfigVersion) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemStatsResponse) ProtoMessage() {}
+func (*ConfigVersion) ProtoMessage() {}
 
-func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[10]
+func (x *ConfigVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -774,76 +1317,76 @@ func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemStatsResponse.ProtoReflect.Descriptor instead.
-func (*SystemStatsResponse) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ConfigVersion.ProtoReflect.Descriptor instead.
+func (*ConfigVersion) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *SystemStatsResponse) GetMemTotal() uint64 {
+func (x *ConfigVersion) GetId() uint64 {
 	if x != nil {
-		return x.MemTotal
+		return x.Id
 	}
 	return 0
 }
 
-func (x *SystemStatsResponse) GetMemUsed() uint64 {
+func (x *ConfigVersion) GetType() BackendType {
 	if x != nil {
-		return x.MemUsed
+		return x.Type
 	}
-	return 0
+	return BackendType_XRAY
 }
 
-func (x *SystemStatsResponse) GetCpuCores() uint64 {
+func (x *ConfigVersion) GetConfigHash() string {
 	if x != nil {
-		return x.CpuCores
+		return x.ConfigHash
 	}
-	return 0
+	return ""
 }
 
-func (x *SystemStatsResponse) GetCpuUsage() float64 {
+func (x *ConfigVersion) GetExcludeInbounds() []string {
 	if x != nil {
-		return x.CpuUsage
+		return x.ExcludeInbounds
 	}
-	return 0
+	return nil
 }
 
-func (x *SystemStatsResponse) GetIncomingBandwidthSpeed() uint64 {
+func (x *ConfigVersion) GetStartedAt() int64 {
 	if x != nil {
-		return x.IncomingBandwidthSpeed
+		return x.StartedAt
 	}
 	return 0
 }
 
-func (x *SystemStatsResponse) GetOutgoingBandwidthSpeed() uint64 {
+func (x *ConfigVersion) GetConfig() string {
 	if x != nil {
-		return x.OutgoingBandwidthSpeed
+		return x.Config
 	}
-	return 0
+	return ""
 }
 
-// User
-type Vmess struct {
+// oldest first, the newest version is the one rolled back to
+type ConfigHistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Versions      []*ConfigVersion       `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Vmess) Reset() {
-	*x = Vmess{}
-	mi := &file_common_service_proto_msgTypes[11]
+func (x *ConfigHistoryResponse) Reset() {
+	*x = ConfigHistoryResponse{}
+	mi := &file_common_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Vmess) String() string {
+func (x *ConfigHistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Vmess) ProtoMessage() {}
+func (*ConfigHistoryResponse) ProtoMessage() {}
 
-func (x *Vmess) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[11]
+func (x *ConfigHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -854,41 +1397,2321 @@ func (x *Vmess) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Vmess.ProtoReflect.Descriptor instead.
-func (*Vmess) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ConfigHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ConfigHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *Vmess) GetId() string {
+func (x *ConfigHistoryResponse) GetVersions() []*ConfigVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// starts a version of the config history like Start, with the given users
+type RestoreConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Users         []*User                `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+	KeepAlive     uint64                 `protobuf:"varint,3,opt,name=keep_alive,json=keepAlive,proto3" json:"keep_alive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreConfigRequest) Reset() {
+	*x = RestoreConfigRequest{}
+	mi := &file_common_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreConfigRequest) ProtoMessage() {}
+
+func (x *RestoreConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreConfigRequest.ProtoReflect.Descriptor instead.
+func (*RestoreConfigRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RestoreConfigRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
+	return 0
+}
+
+func (x *RestoreConfigRequest) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *RestoreConfigRequest) GetKeepAlive() uint64 {
+	if x != nil {
+		return x.KeepAlive
+	}
+	return 0
+}
+
+// inbound of the running backend
+type InboundInfo struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Tag      string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Protocol string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Listen   string                 `protobuf:"bytes,3,opt,name=listen,proto3" json:"listen,omitempty"`
+	Port     string                 `protobuf:"bytes,4,opt,name=port,proto3" json:"port,omitempty"`
+	// users assigned to the inbound
+	Users uint32 `protobuf:"varint,5,opt,name=users,proto3" json:"users,omitempty"`
+	// excluded inbounds are left out of user syncs
+	Excluded      bool `protobuf:"varint,6,opt,name=excluded,proto3" json:"excluded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundInfo) Reset() {
+	*x = InboundInfo{}
+	mi := &file_common_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundInfo) ProtoMessage() {}
+
+func (x *InboundInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundInfo.ProtoReflect.Descriptor instead.
+func (*InboundInfo) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *InboundInfo) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
 	return ""
 }
 
-type Vless struct {
+func (x *InboundInfo) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *InboundInfo) GetListen() string {
+	if x != nil {
+		return x.Listen
+	}
+	return ""
+}
+
+func (x *InboundInfo) GetPort() string {
+	if x != nil {
+		return x.Port
+	}
+	return ""
+}
+
+func (x *InboundInfo) GetUsers() uint32 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+func (x *InboundInfo) GetExcluded() bool {
+	if x != nil {
+		return x.Excluded
+	}
+	return false
+}
+
+type InboundsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Flow          string                 `protobuf:"bytes,2,opt,name=flow,proto3" json:"flow,omitempty"`
+	Inbounds      []*InboundInfo         `protobuf:"bytes,1,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundsResponse) Reset() {
+	*x = InboundsResponse{}
+	mi := &file_common_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundsResponse) ProtoMessage() {}
+
+func (x *InboundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundsResponse.ProtoReflect.Descriptor instead.
+func (*InboundsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InboundsResponse) GetInbounds() []*InboundInfo {
+	if x != nil {
+		return x.Inbounds
+	}
+	return nil
+}
+
+// config is the JSON of an inbound like in the core config, the users assigned to its tag are added to it
+type AddInboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddInboundRequest) Reset() {
+	*x = AddInboundRequest{}
+	mi := &file_common_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddInboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInboundRequest) ProtoMessage() {}
+
+func (x *AddInboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInboundRequest.ProtoReflect.Descriptor instead.
+func (*AddInboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AddInboundRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type RemoveInboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveInboundRequest) Reset() {
+	*x = RemoveInboundRequest{}
+	mi := &file_common_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveInboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveInboundRequest) ProtoMessage() {}
+
+func (x *RemoveInboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveInboundRequest.ProtoReflect.Descriptor instead.
+func (*RemoveInboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveInboundRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+// outbound of the running backend
+type OutboundInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundInfo) Reset() {
+	*x = OutboundInfo{}
+	mi := &file_common_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundInfo) ProtoMessage() {}
+
+func (x *OutboundInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundInfo.ProtoReflect.Descriptor instead.
+func (*OutboundInfo) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OutboundInfo) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *OutboundInfo) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+type OutboundsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outbounds     []*OutboundInfo        `protobuf:"bytes,1,rep,name=outbounds,proto3" json:"outbounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundsResponse) Reset() {
+	*x = OutboundsResponse{}
+	mi := &file_common_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundsResponse) ProtoMessage() {}
+
+func (x *OutboundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundsResponse.ProtoReflect.Descriptor instead.
+func (*OutboundsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OutboundsResponse) GetOutbounds() []*OutboundInfo {
+	if x != nil {
+		return x.Outbounds
+	}
+	return nil
+}
+
+// config is the JSON of an outbound like in the core config, a replaced outbound is matched by its tag
+type OutboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundRequest) Reset() {
+	*x = OutboundRequest{}
+	mi := &file_common_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundRequest) ProtoMessage() {}
+
+func (x *OutboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundRequest.ProtoReflect.Descriptor instead.
+func (*OutboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OutboundRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type RemoveOutboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOutboundRequest) Reset() {
+	*x = RemoveOutboundRequest{}
+	mi := &file_common_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOutboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOutboundRequest) ProtoMessage() {}
+
+func (x *RemoveOutboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOutboundRequest.ProtoReflect.Descriptor instead.
+func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RemoveOutboundRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+// routing rule of the running backend, config is its JSON like in the core config
+type RoutingRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleTag       string                 `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	OutboundTag   string                 `protobuf:"bytes,2,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	BalancerTag   string                 `protobuf:"bytes,3,opt,name=balancer_tag,json=balancerTag,proto3" json:"balancer_tag,omitempty"`
+	Config        string                 `protobuf:"bytes,4,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
+	mi := &file_common_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRule) ProtoMessage() {}
+
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
+func (*RoutingRule) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RoutingRule) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetBalancerTag() string {
+	if x != nil {
+		return x.BalancerTag
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+// balancer of the running backend, config is its JSON like in the core config
+type BalancerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Selector      []string               `protobuf:"bytes,2,rep,name=selector,proto3" json:"selector,omitempty"`
+	Config        string                 `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalancerInfo) Reset() {
+	*x = BalancerInfo{}
+	mi := &file_common_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalancerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalancerInfo) ProtoMessage() {}
+
+func (x *BalancerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalancerInfo.ProtoReflect.Descriptor instead.
+func (*BalancerInfo) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BalancerInfo) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *BalancerInfo) GetSelector() []string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *BalancerInfo) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+// rules are in the order the backend matches them, the api rule of the node is left out
+type RoutingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	Balancers     []*BalancerInfo        `protobuf:"bytes,2,rep,name=balancers,proto3" json:"balancers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingResponse) Reset() {
+	*x = RoutingResponse{}
+	mi := &file_common_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingResponse) ProtoMessage() {}
+
+func (x *RoutingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingResponse.ProtoReflect.Descriptor instead.
+func (*RoutingResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RoutingResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *RoutingResponse) GetBalancers() []*BalancerInfo {
+	if x != nil {
+		return x.Balancers
+	}
+	return nil
+}
+
+// config is the JSON of a rule like in the core config and needs a ruleTag,
+// first puts it before the other rules instead of after them
+type AddRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	First         bool                   `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleRequest) Reset() {
+	*x = AddRoutingRuleRequest{}
+	mi := &file_common_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRoutingRuleRequest) ProtoMessage() {}
+
+func (x *AddRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AddRoutingRuleRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetFirst() bool {
+	if x != nil {
+		return x.First
+	}
+	return false
+}
+
+type RemoveRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleTag       string                 `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRoutingRuleRequest) Reset() {
+	*x = RemoveRoutingRuleRequest{}
+	mi := &file_common_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRoutingRuleRequest) ProtoMessage() {}
+
+func (x *RemoveRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RemoveRoutingRuleRequest) GetRuleTag() string {
+	if x != nil {
+		return x.RuleTag
+	}
+	return ""
+}
+
+// config is the JSON of a balancer like in the core config
+type AddBalancerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        string                 `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBalancerRequest) Reset() {
+	*x = AddBalancerRequest{}
+	mi := &file_common_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBalancerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBalancerRequest) ProtoMessage() {}
+
+func (x *AddBalancerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBalancerRequest.ProtoReflect.Descriptor instead.
+func (*AddBalancerRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AddBalancerRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type RemoveBalancerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveBalancerRequest) Reset() {
+	*x = RemoveBalancerRequest{}
+	mi := &file_common_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveBalancerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveBalancerRequest) ProtoMessage() {}
+
+func (x *RemoveBalancerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveBalancerRequest.ProtoReflect.Descriptor instead.
+func (*RemoveBalancerRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RemoveBalancerRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+// named list of destinations the node blocks. Domains block their subdomains too,
+// geosite and geoip take the names of entries in the files of the core like category-ads
+type Blocklist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Domains       []string               `protobuf:"bytes,2,rep,name=domains,proto3" json:"domains,omitempty"`
+	Cidrs         []string               `protobuf:"bytes,3,rep,name=cidrs,proto3" json:"cidrs,omitempty"`
+	Geosite       []string               `protobuf:"bytes,4,rep,name=geosite,proto3" json:"geosite,omitempty"`
+	Geoip         []string               `protobuf:"bytes,5,rep,name=geoip,proto3" json:"geoip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Blocklist) Reset() {
+	*x = Blocklist{}
+	mi := &file_common_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Blocklist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Blocklist) ProtoMessage() {}
+
+func (x *Blocklist) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Blocklist.ProtoReflect.Descriptor instead.
+func (*Blocklist) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Blocklist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Blocklist) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Blocklist) GetCidrs() []string {
+	if x != nil {
+		return x.Cidrs
+	}
+	return nil
+}
+
+func (x *Blocklist) GetGeosite() []string {
+	if x != nil {
+		return x.Geosite
+	}
+	return nil
+}
+
+func (x *Blocklist) GetGeoip() []string {
+	if x != nil {
+		return x.Geoip
+	}
+	return nil
+}
+
+// the lists replace the ones the node has, a request without lists removes them all
+type SetBlocklistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lists         []*Blocklist           `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBlocklistRequest) Reset() {
+	*x = SetBlocklistRequest{}
+	mi := &file_common_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBlocklistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBlocklistRequest) ProtoMessage() {}
+
+func (x *SetBlocklistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBlocklistRequest.ProtoReflect.Descriptor instead.
+func (*SetBlocklistRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetBlocklistRequest) GetLists() []*Blocklist {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+// hits are the connections blocked by a list since it was set, counted from the access logs of the core
+type BlocklistStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Hits          uint64                 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlocklistStat) Reset() {
+	*x = BlocklistStat{}
+	mi := &file_common_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlocklistStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlocklistStat) ProtoMessage() {}
+
+func (x *BlocklistStat) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlocklistStat.ProtoReflect.Descriptor instead.
+func (*BlocklistStat) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BlocklistStat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BlocklistStat) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type BlocklistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lists         []*Blocklist           `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	Stats         []*BlocklistStat       `protobuf:"bytes,2,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlocklistResponse) Reset() {
+	*x = BlocklistResponse{}
+	mi := &file_common_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlocklistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlocklistResponse) ProtoMessage() {}
+
+func (x *BlocklistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlocklistResponse.ProtoReflect.Descriptor instead.
+func (*BlocklistResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BlocklistResponse) GetLists() []*Blocklist {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+func (x *BlocklistResponse) GetStats() []*BlocklistStat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// result of checking a config without starting it, version is the core that checked it
+type ValidateConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors        []*ConfigError         `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	CoreVersion   string                 `protobuf:"bytes,3,opt,name=core_version,json=coreVersion,proto3" json:"core_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateConfigResponse) Reset() {
+	*x = ValidateConfigResponse{}
+	mi := &file_common_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateConfigResponse) ProtoMessage() {}
+
+func (x *ValidateConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateConfigResponse.ProtoReflect.Descriptor instead.
+func (*ValidateConfigResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ValidateConfigResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateConfigResponse) GetErrors() []*ConfigError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidateConfigResponse) GetCoreVersion() string {
+	if x != nil {
+		return x.CoreVersion
+	}
+	return ""
+}
+
+// stats
+type Stat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Link          string                 `protobuf:"bytes,3,opt,name=link,proto3" json:"link,omitempty"`
+	Value         int64                  `protobuf:"varint,4,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stat) Reset() {
+	*x = Stat{}
+	mi := &file_common_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stat) ProtoMessage() {}
+
+func (x *Stat) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stat.ProtoReflect.Descriptor instead.
+func (*Stat) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Stat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stat) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Stat) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *Stat) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type StatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*Stat                `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatResponse) Reset() {
+	*x = StatResponse{}
+	mi := &file_common_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatResponse) ProtoMessage() {}
+
+func (x *StatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
+func (*StatResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StatResponse) GetStats() []*Stat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type StatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Reset_        bool                   `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
+	Type          StatType               `protobuf:"varint,3,opt,name=type,proto3,enum=service.StatType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatRequest) Reset() {
+	*x = StatRequest{}
+	mi := &file_common_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatRequest) ProtoMessage() {}
+
+func (x *StatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
+func (*StatRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *StatRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StatRequest) GetReset_() bool {
+	if x != nil {
+		return x.Reset_
+	}
+	return false
+}
+
+func (x *StatRequest) GetType() StatType {
+	if x != nil {
+		return x.Type
+	}
+	return StatType_Outbounds
+}
+
+type OnlineStatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnlineStatResponse) Reset() {
+	*x = OnlineStatResponse{}
+	mi := &file_common_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnlineStatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnlineStatResponse) ProtoMessage() {}
+
+func (x *OnlineStatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnlineStatResponse.ProtoReflect.Descriptor instead.
+func (*OnlineStatResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *OnlineStatResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OnlineStatResponse) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type StatsOnlineIpListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ips           map[string]int64       `protobuf:"bytes,2,rep,name=ips,proto3" json:"ips,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsOnlineIpListResponse) Reset() {
+	*x = StatsOnlineIpListResponse{}
+	mi := &file_common_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsOnlineIpListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsOnlineIpListResponse) ProtoMessage() {}
+
+func (x *StatsOnlineIpListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsOnlineIpListResponse.ProtoReflect.Descriptor instead.
+func (*StatsOnlineIpListResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StatsOnlineIpListResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StatsOnlineIpListResponse) GetIps() map[string]int64 {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+// destination analytics, email selects one user and limit is the top destinations per user (default 10)
+type DestinationStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Email string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// seconds back from now, 0 covers the whole retention
+	Window        uint32 `protobuf:"varint,2,opt,name=window,proto3" json:"window,omitempty"`
+	Limit         uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestinationStatsRequest) Reset() {
+	*x = DestinationStatsRequest{}
+	mi := &file_common_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationStatsRequest) ProtoMessage() {}
+
+func (x *DestinationStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationStatsRequest.ProtoReflect.Descriptor instead.
+func (*DestinationStatsRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DestinationStatsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *DestinationStatsRequest) GetWindow() uint32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *DestinationStatsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DestinationStat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// host or IP, hashed when the node hashes destinations
+	Destination   string `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Hits          uint64 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestinationStat) Reset() {
+	*x = DestinationStat{}
+	mi := &file_common_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationStat) ProtoMessage() {}
+
+func (x *DestinationStat) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationStat.ProtoReflect.Descriptor instead.
+func (*DestinationStat) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DestinationStat) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *DestinationStat) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type UserDestinationStats struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Email        string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Destinations []*DestinationStat     `protobuf:"bytes,2,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	// hits over every destination of the user
+	Hits          uint64 `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDestinationStats) Reset() {
+	*x = UserDestinationStats{}
+	mi := &file_common_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDestinationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDestinationStats) ProtoMessage() {}
+
+func (x *UserDestinationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDestinationStats.ProtoReflect.Descriptor instead.
+func (*UserDestinationStats) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *UserDestinationStats) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserDestinationStats) GetDestinations() []*DestinationStat {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *UserDestinationStats) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type OutboundStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outbound      string                 `protobuf:"bytes,1,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	Hits          uint64                 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboundStat) Reset() {
+	*x = OutboundStat{}
+	mi := &file_common_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboundStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboundStat) ProtoMessage() {}
+
+func (x *OutboundStat) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboundStat.ProtoReflect.Descriptor instead.
+func (*OutboundStat) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *OutboundStat) GetOutbound() string {
+	if x != nil {
+		return x.Outbound
+	}
+	return ""
+}
+
+func (x *OutboundStat) GetHits() uint64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type DestinationStatsResponse struct {
+	state     protoimpl.MessageState  `protogen:"open.v1"`
+	Users     []*UserDestinationStats `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Outbounds []*OutboundStat         `protobuf:"bytes,2,rep,name=outbounds,proto3" json:"outbounds,omitempty"`
+	// unix seconds of the oldest counted connections
+	Since         int64 `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	Hashed        bool  `protobuf:"varint,4,opt,name=hashed,proto3" json:"hashed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DestinationStatsResponse) Reset() {
+	*x = DestinationStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationStatsResponse) ProtoMessage() {}
+
+func (x *DestinationStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationStatsResponse.ProtoReflect.Descriptor instead.
+func (*DestinationStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DestinationStatsResponse) GetUsers() []*UserDestinationStats {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *DestinationStatsResponse) GetOutbounds() []*OutboundStat {
+	if x != nil {
+		return x.Outbounds
+	}
+	return nil
+}
+
+func (x *DestinationStatsResponse) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *DestinationStatsResponse) GetHashed() bool {
+	if x != nil {
+		return x.Hashed
+	}
+	return false
+}
+
+type BackendStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NumGoroutine  uint32                 `protobuf:"varint,1,opt,name=num_goroutine,json=numGoroutine,proto3" json:"num_goroutine,omitempty"`
+	NumGc         uint32                 `protobuf:"varint,2,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
+	Alloc         uint64                 `protobuf:"varint,3,opt,name=alloc,proto3" json:"alloc,omitempty"`
+	TotalAlloc    uint64                 `protobuf:"varint,4,opt,name=total_alloc,json=totalAlloc,proto3" json:"total_alloc,omitempty"`
+	Sys           uint64                 `protobuf:"varint,5,opt,name=sys,proto3" json:"sys,omitempty"`
+	Mallocs       uint64                 `protobuf:"varint,6,opt,name=mallocs,proto3" json:"mallocs,omitempty"`
+	Frees         uint64                 `protobuf:"varint,7,opt,name=frees,proto3" json:"frees,omitempty"`
+	LiveObjects   uint64                 `protobuf:"varint,8,opt,name=live_objects,json=liveObjects,proto3" json:"live_objects,omitempty"`
+	PauseTotalNs  uint64                 `protobuf:"varint,9,opt,name=pause_total_ns,json=pauseTotalNs,proto3" json:"pause_total_ns,omitempty"`
+	Uptime        uint32                 `protobuf:"varint,10,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackendStatsResponse) Reset() {
+	*x = BackendStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendStatsResponse) ProtoMessage() {}
+
+func (x *BackendStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendStatsResponse.ProtoReflect.Descriptor instead.
+func (*BackendStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *BackendStatsResponse) GetNumGoroutine() uint32 {
+	if x != nil {
+		return x.NumGoroutine
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetNumGc() uint32 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetAlloc() uint64 {
+	if x != nil {
+		return x.Alloc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetTotalAlloc() uint64 {
+	if x != nil {
+		return x.TotalAlloc
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetSys() uint64 {
+	if x != nil {
+		return x.Sys
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetMallocs() uint64 {
+	if x != nil {
+		return x.Mallocs
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetFrees() uint64 {
+	if x != nil {
+		return x.Frees
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetLiveObjects() uint64 {
+	if x != nil {
+		return x.LiveObjects
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetPauseTotalNs() uint64 {
+	if x != nil {
+		return x.PauseTotalNs
+	}
+	return 0
+}
+
+func (x *BackendStatsResponse) GetUptime() uint32 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type SystemStatsResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	MemTotal               uint64                 `protobuf:"varint,1,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	MemUsed                uint64                 `protobuf:"varint,2,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
+	CpuCores               uint64                 `protobuf:"varint,3,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuUsage               float64                `protobuf:"fixed64,4,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	IncomingBandwidthSpeed uint64                 `protobuf:"varint,5,opt,name=incoming_bandwidth_speed,json=incomingBandwidthSpeed,proto3" json:"incoming_bandwidth_speed,omitempty"`
+	OutgoingBandwidthSpeed uint64                 `protobuf:"varint,6,opt,name=outgoing_bandwidth_speed,json=outgoingBandwidthSpeed,proto3" json:"outgoing_bandwidth_speed,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SystemStatsResponse) Reset() {
+	*x = SystemStatsResponse{}
+	mi := &file_common_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemStatsResponse) ProtoMessage() {}
+
+func (x *SystemStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemStatsResponse.ProtoReflect.Descriptor instead.
+func (*SystemStatsResponse) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SystemStatsResponse) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetMemUsed() uint64 {
+	if x != nil {
+		return x.MemUsed
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetCpuCores() uint64 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetCpuUsage() float64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetIncomingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.IncomingBandwidthSpeed
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetOutgoingBandwidthSpeed() uint64 {
+	if x != nil {
+		return x.OutgoingBandwidthSpeed
+	}
+	return 0
+}
+
+// User
+type Vmess struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vmess) Reset() {
+	*x = Vmess{}
+	mi := &file_common_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vmess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vmess) ProtoMessage() {}
+
+func (x *Vmess) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vmess.ProtoReflect.Descriptor instead.
+func (*Vmess) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *Vmess) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Vless struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Flow          string                 `protobuf:"bytes,2,opt,name=flow,proto3" json:"flow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vless) Reset() {
+	*x = Vless{}
+	mi := &file_common_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vless) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vless) ProtoMessage() {}
+
+func (x *Vless) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vless.ProtoReflect.Descriptor instead.
+func (*Vless) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *Vless) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Vless) GetFlow() string {
+	if x != nil {
+		return x.Flow
+	}
+	return ""
+}
+
+type Trojan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trojan) Reset() {
+	*x = Trojan{}
+	mi := &file_common_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trojan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trojan) ProtoMessage() {}
+
+func (x *Trojan) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trojan.ProtoReflect.Descriptor instead.
+func (*Trojan) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *Trojan) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type Shadowsocks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Shadowsocks) Reset() {
+	*x = Shadowsocks{}
+	mi := &file_common_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shadowsocks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shadowsocks) ProtoMessage() {}
+
+func (x *Shadowsocks) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shadowsocks.ProtoReflect.Descriptor instead.
+func (*Shadowsocks) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Shadowsocks) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *Shadowsocks) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type Proxy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vmess         *Vmess                 `protobuf:"bytes,1,opt,name=vmess,proto3" json:"vmess,omitempty"`
+	Vless         *Vless                 `protobuf:"bytes,2,opt,name=vless,proto3" json:"vless,omitempty"`
+	Trojan        *Trojan                `protobuf:"bytes,3,opt,name=trojan,proto3" json:"trojan,omitempty"`
+	Shadowsocks   *Shadowsocks           `protobuf:"bytes,4,opt,name=shadowsocks,proto3" json:"shadowsocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Proxy) Reset() {
+	*x = Proxy{}
+	mi := &file_common_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proxy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proxy) ProtoMessage() {}
+
+func (x *Proxy) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proxy.ProtoReflect.Descriptor instead.
+func (*Proxy) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Proxy) GetVmess() *Vmess {
+	if x != nil {
+		return x.Vmess
+	}
+	return nil
+}
+
+func (x *Proxy) GetVless() *Vless {
+	if x != nil {
+		return x.Vless
+	}
+	return nil
+}
+
+func (x *Proxy) GetTrojan() *Trojan {
+	if x != nil {
+		return x.Trojan
+	}
+	return nil
+}
+
+func (x *Proxy) GetShadowsocks() *Shadowsocks {
+	if x != nil {
+		return x.Shadowsocks
+	}
+	return nil
+}
+
+type User struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Email    string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Proxies  *Proxy                 `protobuf:"bytes,2,opt,name=proxies,proto3" json:"proxies,omitempty"`
+	Inbounds []string               `protobuf:"bytes,3,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+	// outbound or balancer the traffic of the user leaves through, empty follows the routing of the config.
+	// The node routes the user with rules of its own, matched before the rules of the config
+	OutboundTag   string `protobuf:"bytes,4,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_common_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetProxies() *Proxy {
+	if x != nil {
+		return x.Proxies
+	}
+	return nil
+}
+
+func (x *User) GetInbounds() []string {
+	if x != nil {
+		return x.Inbounds
+	}
+	return nil
+}
+
+func (x *User) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+type Users struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Users) Reset() {
+	*x = Users{}
+	mi := &file_common_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Users) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Users) ProtoMessage() {}
+
+func (x *Users) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Users.ProtoReflect.Descriptor instead.
+func (*Users) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *Users) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          EventType              `protobuf:"varint,2,opt,name=type,proto3,enum=service.EventType" json:"type,omitempty"`
+	Severity      EventSeverity          `protobuf:"varint,3,opt,name=severity,proto3,enum=service.EventSeverity" json:"severity,omitempty"`
+	Backend       string                 `protobuf:"bytes,4,opt,name=backend,proto3" json:"backend,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Details       map[string]string      `protobuf:"bytes,7,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Vless) Reset() {
-	*x = Vless{}
-	mi := &file_common_service_proto_msgTypes[12]
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_common_service_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Vless) String() string {
+func (x *Event) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Vless) ProtoMessage() {}
+func (*Event) ProtoMessage() {}
 
-func (x *Vless) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[12]
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -899,47 +3722,83 @@ func (x *Vless) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Vless.ProtoReflect.Descriptor instead.
-func (*Vless) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *Vless) GetId() string {
+func (x *Event) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
+	return 0
+}
+
+func (x *Event) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_UNKNOWN_EVENT
+}
+
+func (x *Event) GetSeverity() EventSeverity {
+	if x != nil {
+		return x.Severity
+	}
+	return EventSeverity_INFO
+}
+
+func (x *Event) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
 	return ""
 }
 
-func (x *Vless) GetFlow() string {
+func (x *Event) GetMessage() string {
 	if x != nil {
-		return x.Flow
+		return x.Message
 	}
 	return ""
 }
 
-type Trojan struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+func (x *Event) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Event) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type WatchEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Buffered events with a greater id are replayed before live ones
+	SinceId       uint64 `protobuf:"varint,1,opt,name=since_id,json=sinceId,proto3" json:"since_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Trojan) Reset() {
-	*x = Trojan{}
-	mi := &file_common_service_proto_msgTypes[13]
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_common_service_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Trojan) String() string {
+func (x *WatchEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Trojan) ProtoMessage() {}
+func (*WatchEventsRequest) ProtoMessage() {}
 
-func (x *Trojan) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[13]
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -950,41 +3809,51 @@ func (x *Trojan) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Trojan.ProtoReflect.Descriptor instead.
-func (*Trojan) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *Trojan) GetPassword() string {
+func (x *WatchEventsRequest) GetSinceId() uint64 {
 	if x != nil {
-		return x.Password
+		return x.SinceId
 	}
-	return ""
+	return 0
 }
 
-type Shadowsocks struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
-	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+// session
+type SessionCommand struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Command:
+	//
+	//	*SessionCommand_Heartbeat
+	//	*SessionCommand_SyncUser
+	//	*SessionCommand_SyncUsers
+	//	*SessionCommand_GetStats
+	//	*SessionCommand_GetBackendStats
+	//	*SessionCommand_GetSystemStats
+	//	*SessionCommand_GetBaseInfo
+	Command       isSessionCommand_Command `protobuf_oneof:"command"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Shadowsocks) Reset() {
-	*x = Shadowsocks{}
-	mi := &file_common_service_proto_msgTypes[14]
+func (x *SessionCommand) Reset() {
+	*x = SessionCommand{}
+	mi := &file_common_service_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Shadowsocks) String() string {
+func (x *SessionCommand) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Shadowsocks) ProtoMessage() {}
+func (*SessionCommand) ProtoMessage() {}
 
-func (x *Shadowsocks) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[14]
+func (x *SessionCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -995,117 +3864,167 @@ func (x *Shadowsocks) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Shadowsocks.ProtoReflect.Descriptor instead.
-func (*Shadowsocks) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use SessionCommand.ProtoReflect.Descriptor instead.
+func (*SessionCommand) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *Shadowsocks) GetPassword() string {
+func (x *SessionCommand) GetRequestId() string {
 	if x != nil {
-		return x.Password
+		return x.RequestId
 	}
 	return ""
 }
 
-func (x *Shadowsocks) GetMethod() string {
+func (x *SessionCommand) GetCommand() isSessionCommand_Command {
 	if x != nil {
-		return x.Method
+		return x.Command
 	}
-	return ""
-}
-
-type Proxy struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vmess         *Vmess                 `protobuf:"bytes,1,opt,name=vmess,proto3" json:"vmess,omitempty"`
-	Vless         *Vless                 `protobuf:"bytes,2,opt,name=vless,proto3" json:"vless,omitempty"`
-	Trojan        *Trojan                `protobuf:"bytes,3,opt,name=trojan,proto3" json:"trojan,omitempty"`
-	Shadowsocks   *Shadowsocks           `protobuf:"bytes,4,opt,name=shadowsocks,proto3" json:"shadowsocks,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Proxy) Reset() {
-	*x = Proxy{}
-	mi := &file_common_service_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	return nil
 }
 
-func (x *Proxy) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SessionCommand) GetHeartbeat() *Empty {
+	if x != nil {
+		if x, ok := x.Command.(*SessionCommand_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
 }
 
-func (*Proxy) ProtoMessage() {}
-
-func (x *Proxy) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[15]
+func (x *SessionCommand) GetSyncUser() *User {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Command.(*SessionCommand_SyncUser); ok {
+			return x.SyncUser
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use Proxy.ProtoReflect.Descriptor instead.
-func (*Proxy) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{15}
+func (x *SessionCommand) GetSyncUsers() *Users {
+	if x != nil {
+		if x, ok := x.Command.(*SessionCommand_SyncUsers); ok {
+			return x.SyncUsers
+		}
+	}
+	return nil
 }
 
-func (x *Proxy) GetVmess() *Vmess {
+func (x *SessionCommand) GetGetStats() *StatRequest {
 	if x != nil {
-		return x.Vmess
+		if x, ok := x.Command.(*SessionCommand_GetStats); ok {
+			return x.GetStats
+		}
 	}
 	return nil
 }
 
-func (x *Proxy) GetVless() *Vless {
+func (x *SessionCommand) GetGetBackendStats() *Empty {
 	if x != nil {
-		return x.Vless
+		if x, ok := x.Command.(*SessionCommand_GetBackendStats); ok {
+			return x.GetBackendStats
+		}
 	}
 	return nil
 }
 
-func (x *Proxy) GetTrojan() *Trojan {
+func (x *SessionCommand) GetGetSystemStats() *Empty {
 	if x != nil {
-		return x.Trojan
+		if x, ok := x.Command.(*SessionCommand_GetSystemStats); ok {
+			return x.GetSystemStats
+		}
 	}
 	return nil
 }
 
-func (x *Proxy) GetShadowsocks() *Shadowsocks {
+func (x *SessionCommand) GetGetBaseInfo() *Empty {
 	if x != nil {
-		return x.Shadowsocks
+		if x, ok := x.Command.(*SessionCommand_GetBaseInfo); ok {
+			return x.GetBaseInfo
+		}
 	}
 	return nil
 }
 
-type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Proxies       *Proxy                 `protobuf:"bytes,2,opt,name=proxies,proto3" json:"proxies,omitempty"`
-	Inbounds      []string               `protobuf:"bytes,3,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+type isSessionCommand_Command interface {
+	isSessionCommand_Command()
+}
+
+type SessionCommand_Heartbeat struct {
+	Heartbeat *Empty `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type SessionCommand_SyncUser struct {
+	SyncUser *User `protobuf:"bytes,3,opt,name=sync_user,json=syncUser,proto3,oneof"`
+}
+
+type SessionCommand_SyncUsers struct {
+	SyncUsers *Users `protobuf:"bytes,4,opt,name=sync_users,json=syncUsers,proto3,oneof"`
+}
+
+type SessionCommand_GetStats struct {
+	GetStats *StatRequest `protobuf:"bytes,5,opt,name=get_stats,json=getStats,proto3,oneof"`
+}
+
+type SessionCommand_GetBackendStats struct {
+	GetBackendStats *Empty `protobuf:"bytes,6,opt,name=get_backend_stats,json=getBackendStats,proto3,oneof"`
+}
+
+type SessionCommand_GetSystemStats struct {
+	GetSystemStats *Empty `protobuf:"bytes,7,opt,name=get_system_stats,json=getSystemStats,proto3,oneof"`
+}
+
+type SessionCommand_GetBaseInfo struct {
+	GetBaseInfo *Empty `protobuf:"bytes,8,opt,name=get_base_info,json=getBaseInfo,proto3,oneof"`
+}
+
+func (*SessionCommand_Heartbeat) isSessionCommand_Command() {}
+
+func (*SessionCommand_SyncUser) isSessionCommand_Command() {}
+
+func (*SessionCommand_SyncUsers) isSessionCommand_Command() {}
+
+func (*SessionCommand_GetStats) isSessionCommand_Command() {}
+
+func (*SessionCommand_GetBackendStats) isSessionCommand_Command() {}
+
+func (*SessionCommand_GetSystemStats) isSessionCommand_Command() {}
+
+func (*SessionCommand_GetBaseInfo) isSessionCommand_Command() {}
+
+type SessionMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*SessionMessage_Heartbeat
+	//	*SessionMessage_Error
+	//	*SessionMessage_Ack
+	//	*SessionMessage_Stats
+	//	*SessionMessage_BackendStats
+	//	*SessionMessage_SystemStats
+	//	*SessionMessage_BaseInfo
+	//	*SessionMessage_Event
+	Message       isSessionMessage_Message `protobuf_oneof:"message"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *User) Reset() {
-	*x = User{}
-	mi := &file_common_service_proto_msgTypes[16]
+func (x *SessionMessage) Reset() {
+	*x = SessionMessage{}
+	mi := &file_common_service_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *User) String() string {
+func (x *SessionMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*SessionMessage) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[16]
+func (x *SessionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_common_service_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1116,95 +4035,300 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use SessionMessage.ProtoReflect.Descriptor instead.
+func (*SessionMessage) Descriptor() ([]byte, []int) {
+	return file_common_service_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *User) GetEmail() string {
+func (x *SessionMessage) GetRequestId() string {
 	if x != nil {
-		return x.Email
+		return x.RequestId
 	}
 	return ""
 }
 
-func (x *User) GetProxies() *Proxy {
+func (x *SessionMessage) GetMessage() isSessionMessage_Message {
 	if x != nil {
-		return x.Proxies
+		return x.Message
 	}
 	return nil
 }
 
-func (x *User) GetInbounds() []string {
+func (x *SessionMessage) GetHeartbeat() *Empty {
 	if x != nil {
-		return x.Inbounds
+		if x, ok := x.Message.(*SessionMessage_Heartbeat); ok {
+			return x.Heartbeat
+		}
 	}
 	return nil
 }
 
-type Users struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SessionMessage) GetError() string {
+	if x != nil {
+		if x, ok := x.Message.(*SessionMessage_Error); ok {
+			return x.Error
+		}
+	}
+	return ""
 }
 
-func (x *Users) Reset() {
-	*x = Users{}
-	mi := &file_common_service_proto_msgTypes[17]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SessionMessage) GetAck() *Empty {
+	if x != nil {
+		if x, ok := x.Message.(*SessionMessage_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
 }
 
-func (x *Users) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SessionMessage) GetStats() *StatResponse {
+	if x != nil {
+		if x, ok := x.Message.(*SessionMessage_Stats); ok {
+			return x.Stats
+		}
+	}
+	return nil
 }
 
-func (*Users) ProtoMessage() {}
+func (x *SessionMessage) GetBackendStats() *BackendStatsResponse {
+	if x != nil {
+		if x, ok := x.Message.(*SessionMessage_BackendStats); ok {
+			return x.BackendStats
+		}
+	}
+	return nil
+}
 
-func (x *Users) ProtoReflect() protoreflect.Message {
-	mi := &file_common_service_proto_msgTypes[17]
+func (x *SessionMessage) GetSystemStats() *SystemStatsResponse {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Message.(*SessionMessage_SystemStats); ok {
+			return x.SystemStats
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use Users.ProtoReflect.Descriptor instead.
-func (*Users) Descriptor() ([]byte, []int) {
-	return file_common_service_proto_rawDescGZIP(), []int{17}
+func (x *SessionMessage) GetBaseInfo() *BaseInfoResponse {
+	if x != nil {
+		if x, ok := x.Message.(*SessionMessage_BaseInfo); ok {
+			return x.BaseInfo
+		}
+	}
+	return nil
 }
 
-func (x *Users) GetUsers() []*User {
+func (x *SessionMessage) GetEvent() *Event {
 	if x != nil {
-		return x.Users
+		if x, ok := x.Message.(*SessionMessage_Event); ok {
+			return x.Event
+		}
 	}
 	return nil
 }
 
+type isSessionMessage_Message interface {
+	isSessionMessage_Message()
+}
+
+type SessionMessage_Heartbeat struct {
+	Heartbeat *Empty `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type SessionMessage_Error struct {
+	Error string `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+type SessionMessage_Ack struct {
+	Ack *Empty `protobuf:"bytes,4,opt,name=ack,proto3,oneof"`
+}
+
+type SessionMessage_Stats struct {
+	Stats *StatResponse `protobuf:"bytes,5,opt,name=stats,proto3,oneof"`
+}
+
+type SessionMessage_BackendStats struct {
+	BackendStats *BackendStatsResponse `protobuf:"bytes,6,opt,name=backend_stats,json=backendStats,proto3,oneof"`
+}
+
+type SessionMessage_SystemStats struct {
+	SystemStats *SystemStatsResponse `protobuf:"bytes,7,opt,name=system_stats,json=systemStats,proto3,oneof"`
+}
+
+type SessionMessage_BaseInfo struct {
+	BaseInfo *BaseInfoResponse `protobuf:"bytes,8,opt,name=base_info,json=baseInfo,proto3,oneof"`
+}
+
+type SessionMessage_Event struct {
+	Event *Event `protobuf:"bytes,9,opt,name=event,proto3,oneof"`
+}
+
+func (*SessionMessage_Heartbeat) isSessionMessage_Message() {}
+
+func (*SessionMessage_Error) isSessionMessage_Message() {}
+
+func (*SessionMessage_Ack) isSessionMessage_Message() {}
+
+func (*SessionMessage_Stats) isSessionMessage_Message() {}
+
+func (*SessionMessage_BackendStats) isSessionMessage_Message() {}
+
+func (*SessionMessage_SystemStats) isSessionMessage_Message() {}
+
+func (*SessionMessage_BaseInfo) isSessionMessage_Message() {}
+
+func (*SessionMessage_Event) isSessionMessage_Message() {}
+
 var File_common_service_proto protoreflect.FileDescriptor
 
 const file_common_service_proto_rawDesc = "" +
 	"\n" +
 	"\x14common/service.proto\x12\aservice\"\a\n" +
-	"\x05Empty\"r\n" +
+	"\x05Empty\"\x88\x02\n" +
 	"\x10BaseInfoResponse\x12\x18\n" +
 	"\astarted\x18\x01 \x01(\bR\astarted\x12!\n" +
 	"\fcore_version\x18\x02 \x01(\tR\vcoreVersion\x12!\n" +
-	"\fnode_version\x18\x03 \x01(\tR\vnodeVersion\"\xba\x01\n" +
+	"\fnode_version\x18\x03 \x01(\tR\vnodeVersion\x12#\n" +
+	"\rrestart_count\x18\x04 \x01(\rR\frestartCount\x12:\n" +
+	"\rbreaker_state\x18\x05 \x01(\x0e2\x15.service.BreakerStateR\fbreakerState\x123\n" +
+	"\brollback\x18\x06 \x01(\v2\x17.service.ConfigRollbackR\brollback\"\xba\x01\n" +
 	"\aBackend\x12(\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x14.service.BackendTypeR\x04type\x12\x16\n" +
 	"\x06config\x18\x02 \x01(\tR\x06config\x12#\n" +
 	"\x05users\x18\x03 \x03(\v2\r.service.UserR\x05users\x12\x1d\n" +
 	"\n" +
 	"keep_alive\x18\x04 \x01(\x04R\tkeepAlive\x12)\n" +
-	"\x10exclude_inbounds\x18\x05 \x03(\tR\x0fexcludeInbounds\"\x1d\n" +
+	"\x10exclude_inbounds\x18\x05 \x03(\tR\x0fexcludeInbounds\"U\n" +
 	"\x03Log\x12\x16\n" +
-	"\x06detail\x18\x01 \x01(\tR\x06detail\"X\n" +
+	"\x06detail\x18\x01 \x01(\tR\x06detail\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x04R\adropped\"\xcf\x01\n" +
+	"\n" +
+	"LogRequest\x12\x12\n" +
+	"\x04tail\x18\x01 \x01(\rR\x04tail\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\x12'\n" +
+	"\x05level\x18\x03 \x01(\x0e2\x11.service.LogLevelR\x05level\x12$\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x10.service.LogTypeR\x04type\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x18\n" +
+	"\ainbound\x18\x06 \x01(\tR\ainbound\x12\x18\n" +
+	"\apattern\x18\a \x01(\tR\apattern\"\xa3\x02\n" +
+	"\tAccessLog\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1b\n" +
+	"\tsource_ip\x18\x02 \x01(\tR\bsourceIp\x12\x1f\n" +
+	"\vsource_port\x18\x03 \x01(\rR\n" +
+	"sourcePort\x12\x18\n" +
+	"\anetwork\x18\x04 \x01(\tR\anetwork\x12)\n" +
+	"\x10destination_host\x18\x05 \x01(\tR\x0fdestinationHost\x12)\n" +
+	"\x10destination_port\x18\x06 \x01(\rR\x0fdestinationPort\x12\x18\n" +
+	"\ainbound\x18\a \x01(\tR\ainbound\x12\x1a\n" +
+	"\boutbound\x18\b \x01(\tR\boutbound\x12\x14\n" +
+	"\x05email\x18\t \x01(\tR\x05email\"l\n" +
+	"\x10AccessLogRequest\x12\x12\n" +
+	"\x04tail\x18\x01 \x01(\rR\x04tail\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x18\n" +
+	"\ainbound\x18\x04 \x01(\tR\ainbound\"\x9d\x02\n" +
+	"\bCoreExit\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x10\n" +
+	"\x03pid\x18\x03 \x01(\rR\x03pid\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\x03R\tstartedAt\x12\x1b\n" +
+	"\texited_at\x18\x05 \x01(\x03R\bexitedAt\x12\x1b\n" +
+	"\texit_code\x18\x06 \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06signal\x18\a \x01(\tR\x06signal\x12\x1a\n" +
+	"\bexpected\x18\b \x01(\bR\bexpected\x12\x1f\n" +
+	"\vconfig_hash\x18\t \x01(\tR\n" +
+	"configHash\x12\x1d\n" +
+	"\n" +
+	"last_lines\x18\n" +
+	" \x03(\tR\tlastLines\">\n" +
+	"\x13CoreCrashesResponse\x12'\n" +
+	"\x05exits\x18\x01 \x03(\v2\x11.service.CoreExitR\x05exits\"[\n" +
+	"\vConfigError\x122\n" +
+	"\x06source\x18\x01 \x01(\x0e2\x1a.service.ConfigErrorSourceR\x06source\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"a\n" +
+	"\x0eConfigRollback\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x1f\n" +
+	"\vconfig_hash\x18\x03 \x01(\tR\n" +
+	"configHash\"\xcc\x01\n" +
+	"\rConfigVersion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.service.BackendTypeR\x04type\x12\x1f\n" +
+	"\vconfig_hash\x18\x03 \x01(\tR\n" +
+	"configHash\x12)\n" +
+	"\x10exclude_inbounds\x18\x04 \x03(\tR\x0fexcludeInbounds\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\x03R\tstartedAt\x12\x16\n" +
+	"\x06config\x18\x06 \x01(\tR\x06config\"K\n" +
+	"\x15ConfigHistoryResponse\x122\n" +
+	"\bversions\x18\x01 \x03(\v2\x16.service.ConfigVersionR\bversions\"j\n" +
+	"\x14RestoreConfigRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12#\n" +
+	"\x05users\x18\x02 \x03(\v2\r.service.UserR\x05users\x12\x1d\n" +
+	"\n" +
+	"keep_alive\x18\x03 \x01(\x04R\tkeepAlive\"\x99\x01\n" +
+	"\vInboundInfo\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x16\n" +
+	"\x06listen\x18\x03 \x01(\tR\x06listen\x12\x12\n" +
+	"\x04port\x18\x04 \x01(\tR\x04port\x12\x14\n" +
+	"\x05users\x18\x05 \x01(\rR\x05users\x12\x1a\n" +
+	"\bexcluded\x18\x06 \x01(\bR\bexcluded\"D\n" +
+	"\x10InboundsResponse\x120\n" +
+	"\binbounds\x18\x01 \x03(\v2\x14.service.InboundInfoR\binbounds\"+\n" +
+	"\x11AddInboundRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\"(\n" +
+	"\x14RemoveInboundRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"<\n" +
+	"\fOutboundInfo\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\"H\n" +
+	"\x11OutboundsResponse\x123\n" +
+	"\toutbounds\x18\x01 \x03(\v2\x15.service.OutboundInfoR\toutbounds\")\n" +
+	"\x0fOutboundRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\")\n" +
+	"\x15RemoveOutboundRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"\x86\x01\n" +
+	"\vRoutingRule\x12\x19\n" +
+	"\brule_tag\x18\x01 \x01(\tR\aruleTag\x12!\n" +
+	"\foutbound_tag\x18\x02 \x01(\tR\voutboundTag\x12!\n" +
+	"\fbalancer_tag\x18\x03 \x01(\tR\vbalancerTag\x12\x16\n" +
+	"\x06config\x18\x04 \x01(\tR\x06config\"T\n" +
+	"\fBalancerInfo\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1a\n" +
+	"\bselector\x18\x02 \x03(\tR\bselector\x12\x16\n" +
+	"\x06config\x18\x03 \x01(\tR\x06config\"r\n" +
+	"\x0fRoutingResponse\x12*\n" +
+	"\x05rules\x18\x01 \x03(\v2\x14.service.RoutingRuleR\x05rules\x123\n" +
+	"\tbalancers\x18\x02 \x03(\v2\x15.service.BalancerInfoR\tbalancers\"E\n" +
+	"\x15AddRoutingRuleRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12\x14\n" +
+	"\x05first\x18\x02 \x01(\bR\x05first\"5\n" +
+	"\x18RemoveRoutingRuleRequest\x12\x19\n" +
+	"\brule_tag\x18\x01 \x01(\tR\aruleTag\",\n" +
+	"\x12AddBalancerRequest\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\")\n" +
+	"\x15RemoveBalancerRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"\x7f\n" +
+	"\tBlocklist\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\adomains\x18\x02 \x03(\tR\adomains\x12\x14\n" +
+	"\x05cidrs\x18\x03 \x03(\tR\x05cidrs\x12\x18\n" +
+	"\ageosite\x18\x04 \x03(\tR\ageosite\x12\x14\n" +
+	"\x05geoip\x18\x05 \x03(\tR\x05geoip\"?\n" +
+	"\x13SetBlocklistRequest\x12(\n" +
+	"\x05lists\x18\x01 \x03(\v2\x12.service.BlocklistR\x05lists\"7\n" +
+	"\rBlocklistStat\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x04R\x04hits\"k\n" +
+	"\x11BlocklistResponse\x12(\n" +
+	"\x05lists\x18\x01 \x03(\v2\x12.service.BlocklistR\x05lists\x12,\n" +
+	"\x05stats\x18\x02 \x03(\v2\x16.service.BlocklistStatR\x05stats\"\x7f\n" +
+	"\x16ValidateConfigResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12,\n" +
+	"\x06errors\x18\x02 \x03(\v2\x14.service.ConfigErrorR\x06errors\x12!\n" +
+	"\fcore_version\x18\x03 \x01(\tR\vcoreVersion\"X\n" +
 	"\x04Stat\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
@@ -1224,7 +4348,26 @@ const file_common_service_proto_rawDesc = "" +
 	"\x03ips\x18\x02 \x03(\v2+.service.StatsOnlineIpListResponse.IpsEntryR\x03ips\x1a6\n" +
 	"\bIpsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xac\x02\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"]\n" +
+	"\x17DestinationStatsRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x16\n" +
+	"\x06window\x18\x02 \x01(\rR\x06window\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"G\n" +
+	"\x0fDestinationStat\x12 \n" +
+	"\vdestination\x18\x01 \x01(\tR\vdestination\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x04R\x04hits\"~\n" +
+	"\x14UserDestinationStats\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12<\n" +
+	"\fdestinations\x18\x02 \x03(\v2\x18.service.DestinationStatR\fdestinations\x12\x12\n" +
+	"\x04hits\x18\x03 \x01(\x04R\x04hits\">\n" +
+	"\fOutboundStat\x12\x1a\n" +
+	"\boutbound\x18\x01 \x01(\tR\boutbound\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x04R\x04hits\"\xb2\x01\n" +
+	"\x18DestinationStatsResponse\x123\n" +
+	"\x05users\x18\x01 \x03(\v2\x1d.service.UserDestinationStatsR\x05users\x123\n" +
+	"\toutbounds\x18\x02 \x03(\v2\x15.service.OutboundStatR\toutbounds\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\x12\x16\n" +
+	"\x06hashed\x18\x04 \x01(\bR\x06hashed\"\xac\x02\n" +
 	"\x14BackendStatsResponse\x12#\n" +
 	"\rnum_goroutine\x18\x01 \x01(\rR\fnumGoroutine\x12\x15\n" +
 	"\x06num_gc\x18\x02 \x01(\rR\x05numGc\x12\x14\n" +
@@ -1259,34 +4402,131 @@ const file_common_service_proto_rawDesc = "" +
 	"\x05vmess\x18\x01 \x01(\v2\x0e.service.VmessR\x05vmess\x12$\n" +
 	"\x05vless\x18\x02 \x01(\v2\x0e.service.VlessR\x05vless\x12'\n" +
 	"\x06trojan\x18\x03 \x01(\v2\x0f.service.TrojanR\x06trojan\x126\n" +
-	"\vshadowsocks\x18\x04 \x01(\v2\x14.service.ShadowsocksR\vshadowsocks\"b\n" +
+	"\vshadowsocks\x18\x04 \x01(\v2\x14.service.ShadowsocksR\vshadowsocks\"\x85\x01\n" +
 	"\x04User\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12(\n" +
 	"\aproxies\x18\x02 \x01(\v2\x0e.service.ProxyR\aproxies\x12\x1a\n" +
-	"\binbounds\x18\x03 \x03(\tR\binbounds\",\n" +
+	"\binbounds\x18\x03 \x03(\tR\binbounds\x12!\n" +
+	"\foutbound_tag\x18\x04 \x01(\tR\voutboundTag\",\n" +
 	"\x05Users\x12#\n" +
-	"\x05users\x18\x01 \x03(\v2\r.service.UserR\x05users*\x17\n" +
+	"\x05users\x18\x01 \x03(\v2\r.service.UserR\x05users\"\xb8\x02\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12&\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x12.service.EventTypeR\x04type\x122\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\x16.service.EventSeverityR\bseverity\x12\x18\n" +
+	"\abackend\x18\x04 \x01(\tR\abackend\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x125\n" +
+	"\adetails\x18\a \x03(\v2\x1b.service.Event.DetailsEntryR\adetails\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"/\n" +
+	"\x12WatchEventsRequest\x12\x19\n" +
+	"\bsince_id\x18\x01 \x01(\x04R\asinceId\"\xae\x03\n" +
+	"\x0eSessionCommand\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12.\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x0e.service.EmptyH\x00R\theartbeat\x12,\n" +
+	"\tsync_user\x18\x03 \x01(\v2\r.service.UserH\x00R\bsyncUser\x12/\n" +
+	"\n" +
+	"sync_users\x18\x04 \x01(\v2\x0e.service.UsersH\x00R\tsyncUsers\x123\n" +
+	"\tget_stats\x18\x05 \x01(\v2\x14.service.StatRequestH\x00R\bgetStats\x12<\n" +
+	"\x11get_backend_stats\x18\x06 \x01(\v2\x0e.service.EmptyH\x00R\x0fgetBackendStats\x12:\n" +
+	"\x10get_system_stats\x18\a \x01(\v2\x0e.service.EmptyH\x00R\x0egetSystemStats\x124\n" +
+	"\rget_base_info\x18\b \x01(\v2\x0e.service.EmptyH\x00R\vgetBaseInfoB\t\n" +
+	"\acommand\"\xc0\x03\n" +
+	"\x0eSessionMessage\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12.\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x0e.service.EmptyH\x00R\theartbeat\x12\x16\n" +
+	"\x05error\x18\x03 \x01(\tH\x00R\x05error\x12\"\n" +
+	"\x03ack\x18\x04 \x01(\v2\x0e.service.EmptyH\x00R\x03ack\x12-\n" +
+	"\x05stats\x18\x05 \x01(\v2\x15.service.StatResponseH\x00R\x05stats\x12D\n" +
+	"\rbackend_stats\x18\x06 \x01(\v2\x1d.service.BackendStatsResponseH\x00R\fbackendStats\x12A\n" +
+	"\fsystem_stats\x18\a \x01(\v2\x1c.service.SystemStatsResponseH\x00R\vsystemStats\x128\n" +
+	"\tbase_info\x18\b \x01(\v2\x19.service.BaseInfoResponseH\x00R\bbaseInfo\x12&\n" +
+	"\x05event\x18\t \x01(\v2\x0e.service.EventH\x00R\x05eventB\t\n" +
+	"\amessage*K\n" +
+	"\fBreakerState\x12\x12\n" +
+	"\x0eBREAKER_CLOSED\x10\x00\x12\x10\n" +
+	"\fBREAKER_OPEN\x10\x01\x12\x15\n" +
+	"\x11BREAKER_HALF_OPEN\x10\x02*%\n" +
 	"\vBackendType\x12\b\n" +
-	"\x04XRAY\x10\x00*_\n" +
+	"\x04XRAY\x10\x00\x12\f\n" +
+	"\bSING_BOX\x10\x01*G\n" +
+	"\bLogLevel\x12\r\n" +
+	"\tLOG_DEBUG\x10\x00\x12\f\n" +
+	"\bLOG_INFO\x10\x01\x12\x0f\n" +
+	"\vLOG_WARNING\x10\x02\x12\r\n" +
+	"\tLOG_ERROR\x10\x03*8\n" +
+	"\aLogType\x12\f\n" +
+	"\bALL_LOGS\x10\x00\x12\x0f\n" +
+	"\vACCESS_LOGS\x10\x01\x12\x0e\n" +
+	"\n" +
+	"ERROR_LOGS\x10\x02*6\n" +
+	"\x11ConfigErrorSource\x12\x10\n" +
+	"\fCONFIG_PARSE\x10\x00\x12\x0f\n" +
+	"\vCONFIG_CORE\x10\x01*_\n" +
 	"\bStatType\x12\r\n" +
 	"\tOutbounds\x10\x00\x12\f\n" +
 	"\bOutbound\x10\x01\x12\f\n" +
 	"\bInbounds\x10\x02\x12\v\n" +
 	"\aInbound\x10\x03\x12\r\n" +
 	"\tUsersStat\x10\x04\x12\f\n" +
-	"\bUserStat\x10\x052\x9a\x05\n" +
+	"\bUserStat\x10\x05*\x86\x02\n" +
+	"\tEventType\x12\x11\n" +
+	"\rUNKNOWN_EVENT\x10\x00\x12\x10\n" +
+	"\fCORE_STARTED\x10\x01\x12\x15\n" +
+	"\x11CORE_START_FAILED\x10\x02\x12\x10\n" +
+	"\fCORE_CRASHED\x10\x03\x12\x12\n" +
+	"\x0eCORE_RESTARTED\x10\x04\x12\x17\n" +
+	"\x13CORE_RESTART_FAILED\x10\x05\x12\x12\n" +
+	"\x0eORPHAN_CLEANUP\x10\x06\x12\x11\n" +
+	"\rCERT_EXPIRING\x10\a\x12\x10\n" +
+	"\fCERT_EXPIRED\x10\b\x12\x13\n" +
+	"\x0fCORE_CRASH_LOOP\x10\t\x12\x1b\n" +
+	"\x17BANDWIDTH_QUOTA_REACHED\x10\n" +
+	"\x12\x13\n" +
+	"\x0fCONFIG_ROLLBACK\x10\v*4\n" +
+	"\rEventSeverity\x12\b\n" +
+	"\x04INFO\x10\x00\x12\v\n" +
+	"\aWARNING\x10\x01\x12\f\n" +
+	"\bCRITICAL\x10\x022\xe3\x10\n" +
 	"\vNodeService\x126\n" +
 	"\x05Start\x12\x10.service.Backend\x1a\x19.service.BaseInfoResponse\"\x00\x12(\n" +
 	"\x04Stop\x12\x0e.service.Empty\x1a\x0e.service.Empty\"\x00\x12:\n" +
-	"\vGetBaseInfo\x12\x0e.service.Empty\x1a\x19.service.BaseInfoResponse\"\x00\x12+\n" +
-	"\aGetLogs\x12\x0e.service.Empty\x1a\f.service.Log\"\x000\x01\x12@\n" +
+	"\vGetBaseInfo\x12\x0e.service.Empty\x1a\x19.service.BaseInfoResponse\"\x00\x12@\n" +
+	"\x0eGetCoreCrashes\x12\x0e.service.Empty\x1a\x1c.service.CoreCrashesResponse\"\x00\x12E\n" +
+	"\x0eValidateConfig\x12\x10.service.Backend\x1a\x1f.service.ValidateConfigResponse\"\x00\x12D\n" +
+	"\x10GetConfigHistory\x12\x0e.service.Empty\x1a\x1e.service.ConfigHistoryResponse\"\x00\x12K\n" +
+	"\rRestoreConfig\x12\x1d.service.RestoreConfigRequest\x1a\x19.service.BaseInfoResponse\"\x00\x120\n" +
+	"\aGetLogs\x12\x13.service.LogRequest\x1a\f.service.Log\"\x000\x01\x12D\n" +
+	"\x0fWatchAccessLogs\x12\x19.service.AccessLogRequest\x1a\x12.service.AccessLog\"\x000\x01\x12@\n" +
 	"\x0eGetSystemStats\x12\x0e.service.Empty\x1a\x1c.service.SystemStatsResponse\"\x00\x12B\n" +
 	"\x0fGetBackendStats\x12\x0e.service.Empty\x1a\x1d.service.BackendStatsResponse\"\x00\x129\n" +
 	"\bGetStats\x12\x14.service.StatRequest\x1a\x15.service.StatResponse\"\x00\x12I\n" +
 	"\x12GetUserOnlineStats\x12\x14.service.StatRequest\x1a\x1b.service.OnlineStatResponse\"\x00\x12V\n" +
-	"\x18GetUserOnlineIpListStats\x12\x14.service.StatRequest\x1a\".service.StatsOnlineIpListResponse\"\x00\x12-\n" +
+	"\x18GetUserOnlineIpListStats\x12\x14.service.StatRequest\x1a\".service.StatsOnlineIpListResponse\"\x00\x12\\\n" +
+	"\x13GetDestinationStats\x12 .service.DestinationStatsRequest\x1a!.service.DestinationStatsResponse\"\x00\x12;\n" +
+	"\fListInbounds\x12\x0e.service.Empty\x1a\x19.service.InboundsResponse\"\x00\x12:\n" +
+	"\n" +
+	"AddInbound\x12\x1a.service.AddInboundRequest\x1a\x0e.service.Empty\"\x00\x12@\n" +
+	"\rRemoveInbound\x12\x1d.service.RemoveInboundRequest\x1a\x0e.service.Empty\"\x00\x12=\n" +
+	"\rListOutbounds\x12\x0e.service.Empty\x1a\x1a.service.OutboundsResponse\"\x00\x129\n" +
+	"\vAddOutbound\x12\x18.service.OutboundRequest\x1a\x0e.service.Empty\"\x00\x12=\n" +
+	"\x0fReplaceOutbound\x12\x18.service.OutboundRequest\x1a\x0e.service.Empty\"\x00\x12B\n" +
+	"\x0eRemoveOutbound\x12\x1e.service.RemoveOutboundRequest\x1a\x0e.service.Empty\"\x00\x129\n" +
+	"\vListRouting\x12\x0e.service.Empty\x1a\x18.service.RoutingResponse\"\x00\x12B\n" +
+	"\x0eAddRoutingRule\x12\x1e.service.AddRoutingRuleRequest\x1a\x0e.service.Empty\"\x00\x12H\n" +
+	"\x11RemoveRoutingRule\x12!.service.RemoveRoutingRuleRequest\x1a\x0e.service.Empty\"\x00\x12<\n" +
+	"\vAddBalancer\x12\x1b.service.AddBalancerRequest\x1a\x0e.service.Empty\"\x00\x12B\n" +
+	"\x0eRemoveBalancer\x12\x1e.service.RemoveBalancerRequest\x1a\x0e.service.Empty\"\x00\x12>\n" +
+	"\fSetBlocklist\x12\x1c.service.SetBlocklistRequest\x1a\x0e.service.Empty\"\x00\x12<\n" +
+	"\fGetBlocklist\x12\x0e.service.Empty\x1a\x1a.service.BlocklistResponse\"\x00\x12-\n" +
 	"\bSyncUser\x12\r.service.User\x1a\x0e.service.Empty\"\x00(\x01\x12-\n" +
-	"\tSyncUsers\x12\x0e.service.Users\x1a\x0e.service.Empty\"\x00B#Z!github.com/pasarguard/node/commonb\x06proto3"
+	"\tSyncUsers\x12\x0e.service.Users\x1a\x0e.service.Empty\"\x00\x12A\n" +
+	"\aSession\x12\x17.service.SessionCommand\x1a\x17.service.SessionMessage\"\x00(\x010\x01\x12>\n" +
+	"\vWatchEvents\x12\x1b.service.WatchEventsRequest\x1a\x0e.service.Event\"\x000\x01B#Z!github.com/pasarguard/node/commonb\x06proto3"
 
 var (
 	file_common_service_proto_rawDescOnce sync.Once
@@ -1300,70 +4540,197 @@ func file_common_service_proto_rawDescGZIP() []byte {
 	return file_common_service_proto_rawDescData
 }
 
-var file_common_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_common_service_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_common_service_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_common_service_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
 var file_common_service_proto_goTypes = []any{
-	(BackendType)(0),                  // 0: service.BackendType
-	(StatType)(0),                     // 1: service.StatType
-	(*Empty)(nil),                     // 2: service.Empty
-	(*BaseInfoResponse)(nil),          // 3: service.BaseInfoResponse
-	(*Backend)(nil),                   // 4: service.Backend
-	(*Log)(nil),                       // 5: service.Log
-	(*Stat)(nil),                      // 6: service.Stat
-	(*StatResponse)(nil),              // 7: service.StatResponse
-	(*StatRequest)(nil),               // 8: service.StatRequest
-	(*OnlineStatResponse)(nil),        // 9: service.OnlineStatResponse
-	(*StatsOnlineIpListResponse)(nil), // 10: service.StatsOnlineIpListResponse
-	(*BackendStatsResponse)(nil),      // 11: service.BackendStatsResponse
-	(*SystemStatsResponse)(nil),       // 12: service.SystemStatsResponse
-	(*Vmess)(nil),                     // 13: service.Vmess
-	(*Vless)(nil),                     // 14: service.Vless
-	(*Trojan)(nil),                    // 15: service.Trojan
-	(*Shadowsocks)(nil),               // 16: service.Shadowsocks
-	(*Proxy)(nil),                     // 17: service.Proxy
-	(*User)(nil),                      // 18: service.User
-	(*Users)(nil),                     // 19: service.Users
-	nil,                               // 20: service.StatsOnlineIpListResponse.IpsEntry
+	(BreakerState)(0),                 // 0: service.BreakerState
+	(BackendType)(0),                  // 1: service.BackendType
+	(LogLevel)(0),                     // 2: service.LogLevel
+	(LogType)(0),                      // 3: service.LogType
+	(ConfigErrorSource)(0),            // 4: service.ConfigErrorSource
+	(StatType)(0),                     // 5: service.StatType
+	(EventType)(0),                    // 6: service.EventType
+	(EventSeverity)(0),                // 7: service.EventSeverity
+	(*Empty)(nil),                     // 8: service.Empty
+	(*BaseInfoResponse)(nil),          // 9: service.BaseInfoResponse
+	(*Backend)(nil),                   // 10: service.Backend
+	(*Log)(nil),                       // 11: service.Log
+	(*LogRequest)(nil),                // 12: service.LogRequest
+	(*AccessLog)(nil),                 // 13: service.AccessLog
+	(*AccessLogRequest)(nil),          // 14: service.AccessLogRequest
+	(*CoreExit)(nil),                  // 15: service.CoreExit
+	(*CoreCrashesResponse)(nil),       // 16: service.CoreCrashesResponse
+	(*ConfigError)(nil),               // 17: service.ConfigError
+	(*ConfigRollback)(nil),            // 18: service.ConfigRollback
+	(*ConfigVersion)(nil),             // 19: service.ConfigVersion
+	(*ConfigHistoryResponse)(nil),     // 20: service.ConfigHistoryResponse
+	(*RestoreConfigRequest)(nil),      // 21: service.RestoreConfigRequest
+	(*InboundInfo)(nil),               // 22: service.InboundInfo
+	(*InboundsResponse)(nil),          // 23: service.InboundsResponse
+	(*AddInboundRequest)(nil),         // 24: service.AddInboundRequest
+	(*RemoveInboundRequest)(nil),      // 25: service.RemoveInboundRequest
+	(*OutboundInfo)(nil),              // 26: service.OutboundInfo
+	(*OutboundsResponse)(nil),         // 27: service.OutboundsResponse
+	(*OutboundRequest)(nil),           // 28: service.OutboundRequest
+	(*RemoveOutboundRequest)(nil),     // 29: service.RemoveOutboundRequest
+	(*RoutingRule)(nil),               // 30: service.RoutingRule
+	(*BalancerInfo)(nil),              // 31: service.BalancerInfo
+	(*RoutingResponse)(nil),           // 32: service.RoutingResponse
+	(*AddRoutingRuleRequest)(nil),     // 33: service.AddRoutingRuleRequest
+	(*RemoveRoutingRuleRequest)(nil),  // 34: service.RemoveRoutingRuleRequest
+	(*AddBalancerRequest)(nil),        // 35: service.AddBalancerRequest
+	(*RemoveBalancerRequest)(nil),     // 36: service.RemoveBalancerRequest
+	(*Blocklist)(nil),                 // 37: service.Blocklist
+	(*SetBlocklistRequest)(nil),       // 38: service.SetBlocklistRequest
+	(*BlocklistStat)(nil),             // 39: service.BlocklistStat
+	(*BlocklistResponse)(nil),         // 40: service.BlocklistResponse
+	(*ValidateConfigResponse)(nil),    // 41: service.ValidateConfigResponse
+	(*Stat)(nil),                      // 42: service.Stat
+	(*StatResponse)(nil),              // 43: service.StatResponse
+	(*StatRequest)(nil),               // 44: service.StatRequest
+	(*OnlineStatResponse)(nil),        // 45: service.OnlineStatResponse
+	(*StatsOnlineIpListResponse)(nil), // 46: service.StatsOnlineIpListResponse
+	(*DestinationStatsRequest)(nil),   // 47: service.DestinationStatsRequest
+	(*DestinationStat)(nil),           // 48: service.DestinationStat
+	(*UserDestinationStats)(nil),      // 49: service.UserDestinationStats
+	(*OutboundStat)(nil),              // 50: service.OutboundStat
+	(*DestinationStatsResponse)(nil),  // 51: service.DestinationStatsResponse
+	(*BackendStatsResponse)(nil),      // 52: service.BackendStatsResponse
+	(*SystemStatsResponse)(nil),       // 53: service.SystemStatsResponse
+	(*Vmess)(nil),                     // 54: service.Vmess
+	(*Vless)(nil),                     // 55: service.Vless
+	(*Trojan)(nil),                    // 56: service.Trojan
+	(*Shadowsocks)(nil),               // 57: service.Shadowsocks
+	(*Proxy)(nil),                     // 58: service.Proxy
+	(*User)(nil),                      // 59: service.User
+	(*Users)(nil),                     // 60: service.Users
+	(*Event)(nil),                     // 61: service.Event
+	(*WatchEventsRequest)(nil),        // 62: service.WatchEventsRequest
+	(*SessionCommand)(nil),            // 63: service.SessionCommand
+	(*SessionMessage)(nil),            // 64: service.SessionMessage
+	nil,                               // 65: service.StatsOnlineIpListResponse.IpsEntry
+	nil,                               // 66: service.Event.DetailsEntry
 }
 var file_common_service_proto_depIdxs = []int32{
-	0,  // 0: service.Backend.type:type_name -> service.BackendType
-	18, // 1: service.Backend.users:type_name -> service.User
-	6,  // 2: service.StatResponse.stats:type_name -> service.Stat
-	1,  // 3: service.StatRequest.type:type_name -> service.StatType
-	20, // 4: service.StatsOnlineIpListResponse.ips:type_name -> service.StatsOnlineIpListResponse.IpsEntry
-	13, // 5: service.Proxy.vmess:type_name -> service.Vmess
-	14, // 6: service.Proxy.vless:type_name -> service.Vless
-	15, // 7: service.Proxy.trojan:type_name -> service.Trojan
-	16, // 8: service.Proxy.shadowsocks:type_name -> service.Shadowsocks
-	17, // 9: service.User.proxies:type_name -> service.Proxy
-	18, // 10: service.Users.users:type_name -> service.User
-	4,  // 11: service.NodeService.Start:input_type -> service.Backend
-	2,  // 12: service.NodeService.Stop:input_type -> service.Empty
-	2,  // 13: service.NodeService.GetBaseInfo:input_type -> service.Empty
-	2,  // 14: service.NodeService.GetLogs:input_type -> service.Empty
-	2,  // 15: service.NodeService.GetSystemStats:input_type -> service.Empty
-	2,  // 16: service.NodeService.GetBackendStats:input_type -> service.Empty
-	8,  // 17: service.NodeService.GetStats:input_type -> service.StatRequest
-	8,  // 18: service.NodeService.GetUserOnlineStats:input_type -> service.StatRequest
-	8,  // 19: service.NodeService.GetUserOnlineIpListStats:input_type -> service.StatRequest
-	18, // 20: service.NodeService.SyncUser:input_type -> service.User
-	19, // 21: service.NodeService.SyncUsers:input_type -> service.Users
-	3,  // 22: service.NodeService.Start:output_type -> service.BaseInfoResponse
-	2,  // 23: service.NodeService.Stop:output_type -> service.Empty
-	3,  // 24: service.NodeService.GetBaseInfo:output_type -> service.BaseInfoResponse
-	5,  // 25: service.NodeService.GetLogs:output_type -> service.Log
-	12, // 26: service.NodeService.GetSystemStats:output_type -> service.SystemStatsResponse
-	11, // 27: service.NodeService.GetBackendStats:output_type -> service.BackendStatsResponse
-	7,  // 28: service.NodeService.GetStats:output_type -> service.StatResponse
-	9,  // 29: service.NodeService.GetUserOnlineStats:output_type -> service.OnlineStatResponse
-	10, // 30: service.NodeService.GetUserOnlineIpListStats:output_type -> service.StatsOnlineIpListResponse
-	2,  // 31: service.NodeService.SyncUser:output_type -> service.Empty
-	2,  // 32: service.NodeService.SyncUsers:output_type -> service.Empty
-	22, // [22:33] is the sub-list for method output_type
-	11, // [11:22] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	0,  // 0: service.BaseInfoResponse.breaker_state:type_name -> service.BreakerState
+	18, // 1: service.BaseInfoResponse.rollback:type_name -> service.ConfigRollback
+	1,  // 2: service.Backend.type:type_name -> service.BackendType
+	59, // 3: service.Backend.users:type_name -> service.User
+	2,  // 4: service.LogRequest.level:type_name -> service.LogLevel
+	3,  // 5: service.LogRequest.type:type_name -> service.LogType
+	15, // 6: service.CoreCrashesResponse.exits:type_name -> service.CoreExit
+	4,  // 7: service.ConfigError.source:type_name -> service.ConfigErrorSource
+	1,  // 8: service.ConfigVersion.type:type_name -> service.BackendType
+	19, // 9: service.ConfigHistoryResponse.versions:type_name -> service.ConfigVersion
+	59, // 10: service.RestoreConfigRequest.users:type_name -> service.User
+	22, // 11: service.InboundsResponse.inbounds:type_name -> service.InboundInfo
+	26, // 12: service.OutboundsResponse.outbounds:type_name -> service.OutboundInfo
+	30, // 13: service.RoutingResponse.rules:type_name -> service.RoutingRule
+	31, // 14: service.RoutingResponse.balancers:type_name -> service.BalancerInfo
+	37, // 15: service.SetBlocklistRequest.lists:type_name -> service.Blocklist
+	37, // 16: service.BlocklistResponse.lists:type_name -> service.Blocklist
+	39, // 17: service.BlocklistResponse.stats:type_name -> service.BlocklistStat
+	17, // 18: service.ValidateConfigResponse.errors:type_name -> service.ConfigError
+	42, // 19: service.StatResponse.stats:type_name -> service.Stat
+	5,  // 20: service.StatRequest.type:type_name -> service.StatType
+	65, // 21: service.StatsOnlineIpListResponse.ips:type_name -> service.StatsOnlineIpListResponse.IpsEntry
+	48, // 22: service.UserDestinationStats.destinations:type_name -> service.DestinationStat
+	49, // 23: service.DestinationStatsResponse.users:type_name -> service.UserDestinationStats
+	50, // 24: service.DestinationStatsResponse.outbounds:type_name -> service.OutboundStat
+	54, // 25: service.Proxy.vmess:type_name -> service.Vmess
+	55, // 26: service.Proxy.vless:type_name -> service.Vless
+	56, // 27: service.Proxy.trojan:type_name -> service.Trojan
+	57, // 28: service.Proxy.shadowsocks:type_name -> service.Shadowsocks
+	58, // 29: service.User.proxies:type_name -> service.Proxy
+	59, // 30: service.Users.users:type_name -> service.User
+	6,  // 31: service.Event.type:type_name -> service.EventType
+	7,  // 32: service.Event.severity:type_name -> service.EventSeverity
+	66, // 33: service.Event.details:type_name -> service.Event.DetailsEntry
+	8,  // 34: service.SessionCommand.heartbeat:type_name -> service.Empty
+	59, // 35: service.SessionCommand.sync_user:type_name -> service.User
+	60, // 36: service.SessionCommand.sync_users:type_name -> service.Users
+	44, // 37: service.SessionCommand.get_stats:type_name -> service.StatRequest
+	8,  // 38: service.SessionCommand.get_backend_stats:type_name -> service.Empty
+	8,  // 39: service.SessionCommand.get_system_stats:type_name -> service.Empty
+	8,  // 40: service.SessionCommand.get_base_info:type_name -> service.Empty
+	8,  // 41: service.SessionMessage.heartbeat:type_name -> service.Empty
+	8,  // 42: service.SessionMessage.ack:type_name -> service.Empty
+	43, // 43: service.SessionMessage.stats:type_name -> service.StatResponse
+	52, // 44: service.SessionMessage.backend_stats:type_name -> service.BackendStatsResponse
+	53, // 45: service.SessionMessage.system_stats:type_name -> service.SystemStatsResponse
+	9,  // 46: service.SessionMessage.base_info:type_name -> service.BaseInfoResponse
+	61, // 47: service.SessionMessage.event:type_name -> service.Event
+	10, // 48: service.NodeService.Start:input_type -> service.Backend
+	8,  // 49: service.NodeService.Stop:input_type -> service.Empty
+	8,  // 50: service.NodeService.GetBaseInfo:input_type -> service.Empty
+	8,  // 51: service.NodeService.GetCoreCrashes:input_type -> service.Empty
+	10, // 52: service.NodeService.ValidateConfig:input_type -> service.Backend
+	8,  // 53: service.NodeService.GetConfigHistory:input_type -> service.Empty
+	21, // 54: service.NodeService.RestoreConfig:input_type -> service.RestoreConfigRequest
+	12, // 55: service.NodeService.GetLogs:input_type -> service.LogRequest
+	14, // 56: service.NodeService.WatchAccessLogs:input_type -> service.AccessLogRequest
+	8,  // 57: service.NodeService.GetSystemStats:input_type -> service.Empty
+	8,  // 58: service.NodeService.GetBackendStats:input_type -> service.Empty
+	44, // 59: service.NodeService.GetStats:input_type -> service.StatRequest
+	44, // 60: service.NodeService.GetUserOnlineStats:input_type -> service.StatRequest
+	44, // 61: service.NodeService.GetUserOnlineIpListStats:input_type -> service.StatRequest
+	47, // 62: service.NodeService.GetDestinationStats:input_type -> service.DestinationStatsRequest
+	8,  // 63: service.NodeService.ListInbounds:input_type -> service.Empty
+	24, // 64: service.NodeService.AddInbound:input_type -> service.AddInboundRequest
+	25, // 65: service.NodeService.RemoveInbound:input_type -> service.RemoveInboundRequest
+	8,  // 66: service.NodeService.ListOutbounds:input_type -> service.Empty
+	28, // 67: service.NodeService.AddOutbound:input_type -> service.OutboundRequest
+	28, // 68: service.NodeService.ReplaceOutbound:input_type -> service.OutboundRequest
+	29, // 69: service.NodeService.RemoveOutbound:input_type -> service.RemoveOutboundRequest
+	8,  // 70: service.NodeService.ListRouting:input_type -> service.Empty
+	33, // 71: service.NodeService.AddRoutingRule:input_type -> service.AddRoutingRuleRequest
+	34, // 72: service.NodeService.RemoveRoutingRule:input_type -> service.RemoveRoutingRuleRequest
+	35, // 73: service.NodeService.AddBalancer:input_type -> service.AddBalancerRequest
+	36, // 74: service.NodeService.RemoveBalancer:input_type -> service.RemoveBalancerRequest
+	38, // 75: service.NodeService.SetBlocklist:input_type -> service.SetBlocklistRequest
+	8,  // 76: service.NodeService.GetBlocklist:input_type -> service.Empty
+	59, // 77: service.NodeService.SyncUser:input_type -> service.User
+	60, // 78: service.NodeService.SyncUsers:input_type -> service.Users
+	63, // 79: service.NodeService.Session:input_type -> service.SessionCommand
+	62, // 80: service.NodeService.WatchEvents:input_type -> service.WatchEventsRequest
+	9,  // 81: service.NodeService.Start:output_type -> service.BaseInfoResponse
+	8,  // 82: service.NodeService.Stop:output_type -> service.Empty
+	9,  // 83: service.NodeService.GetBaseInfo:output_type -> service.BaseInfoResponse
+	16, // 84: service.NodeService.GetCoreCrashes:output_type -> service.CoreCrashesResponse
+	41, // 85: service.NodeService.ValidateConfig:output_type -> service.ValidateConfigResponse
+	20, // 86: service.NodeService.GetConfigHistory:output_type -> service.ConfigHistoryResponse
+	9,  // 87: service.NodeService.RestoreConfig:output_type -> service.BaseInfoResponse
+	11, // 88: service.NodeService.GetLogs:output_type -> service.Log
+	13, // 89: service.NodeService.WatchAccessLogs:output_type -> service.AccessLog
+	53, // 90: service.NodeService.GetSystemStats:output_type -> service.SystemStatsResponse
+	52, // 91: service.NodeService.GetBackendStats:output_type -> service.BackendStatsResponse
+	43, // 92: service.NodeService.GetStats:output_type -> service.StatResponse
+	45, // 93: service.NodeService.GetUserOnlineStats:output_type -> service.OnlineStatResponse
+	46, // 94: service.NodeService.GetUserOnlineIpListStats:output_type -> service.StatsOnlineIpListResponse
+	51, // 95: service.NodeService.GetDestinationStats:output_type -> service.DestinationStatsResponse
+	23, // 96: service.NodeService.ListInbounds:output_type -> service.InboundsResponse
+	8,  // 97: service.NodeService.AddInbound:output_type -> service.Empty
+	8,  // 98: service.NodeService.RemoveInbound:output_type -> service.Empty
+	27, // 99: service.NodeService.ListOutbounds:output_type -> service.OutboundsResponse
+	8,  // 100: service.NodeService.AddOutbound:output_type -> service.Empty
+	8,  // 101: service.NodeService.ReplaceOutbound:output_type -> service.Empty
+	8,  // 102: service.NodeService.RemoveOutbound:output_type -> service.Empty
+	32, // 103: service.NodeService.ListRouting:output_type -> service.RoutingResponse
+	8,  // 104: service.NodeService.AddRoutingRule:output_type -> service.Empty
+	8,  // 105: service.NodeService.RemoveRoutingRule:output_type -> service.Empty
+	8,  // 106: service.NodeService.AddBalancer:output_type -> service.Empty
+	8,  // 107: service.NodeService.RemoveBalancer:output_type -> service.Empty
+	8,  // 108: service.NodeService.SetBlocklist:output_type -> service.Empty
+	40, // 109: service.NodeService.GetBlocklist:output_type -> service.BlocklistResponse
+	8,  // 110: service.NodeService.SyncUser:output_type -> service.Empty
+	8,  // 111: service.NodeService.SyncUsers:output_type -> service.Empty
+	64, // 112: service.NodeService.Session:output_type -> service.SessionMessage
+	61, // 113: service.NodeService.WatchEvents:output_type -> service.Event
+	81, // [81:114] is the sub-list for method output_type
+	48, // [48:81] is the sub-list for method input_type
+	48, // [48:48] is the sub-list for extension type_name
+	48, // [48:48] is the sub-list for extension extendee
+	0,  // [0:48] is the sub-list for field type_name
 }
 
 func init() { file_common_service_proto_init() }
@@ -1371,13 +4738,32 @@ func file_common_service_proto_init() {
 	if File_common_service_proto != nil {
 		return
 	}
+	file_common_service_proto_msgTypes[55].OneofWrappers = []any{
+		(*SessionCommand_Heartbeat)(nil),
+		(*SessionCommand_SyncUser)(nil),
+		(*SessionCommand_SyncUsers)(nil),
+		(*SessionCommand_GetStats)(nil),
+		(*SessionCommand_GetBackendStats)(nil),
+		(*SessionCommand_GetSystemStats)(nil),
+		(*SessionCommand_GetBaseInfo)(nil),
+	}
+	file_common_service_proto_msgTypes[56].OneofWrappers = []any{
+		(*SessionMessage_Heartbeat)(nil),
+		(*SessionMessage_Error)(nil),
+		(*SessionMessage_Ack)(nil),
+		(*SessionMessage_Stats)(nil),
+		(*SessionMessage_BackendStats)(nil),
+		(*SessionMessage_SystemStats)(nil),
+		(*SessionMessage_BaseInfo)(nil),
+		(*SessionMessage_Event)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_service_proto_rawDesc), len(file_common_service_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   19,
+			NumEnums:      8,
+			NumMessages:   59,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
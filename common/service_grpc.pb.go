@@ -22,14 +22,36 @@ const (
 	NodeService_Start_FullMethodName                    = "/service.NodeService/Start"
 	NodeService_Stop_FullMethodName                     = "/service.NodeService/Stop"
 	NodeService_GetBaseInfo_FullMethodName              = "/service.NodeService/GetBaseInfo"
+	NodeService_GetCoreCrashes_FullMethodName           = "/service.NodeService/GetCoreCrashes"
+	NodeService_ValidateConfig_FullMethodName           = "/service.NodeService/ValidateConfig"
+	NodeService_GetConfigHistory_FullMethodName         = "/service.NodeService/GetConfigHistory"
+	NodeService_RestoreConfig_FullMethodName            = "/service.NodeService/RestoreConfig"
 	NodeService_GetLogs_FullMethodName                  = "/service.NodeService/GetLogs"
+	NodeService_WatchAccessLogs_FullMethodName          = "/service.NodeService/WatchAccessLogs"
 	NodeService_GetSystemStats_FullMethodName           = "/service.NodeService/GetSystemStats"
 	NodeService_GetBackendStats_FullMethodName          = "/service.NodeService/GetBackendStats"
 	NodeService_GetStats_FullMethodName                 = "/service.NodeService/GetStats"
 	NodeService_GetUserOnlineStats_FullMethodName       = "/service.NodeService/GetUserOnlineStats"
 	NodeService_GetUserOnlineIpListStats_FullMethodName = "/service.NodeService/GetUserOnlineIpListStats"
+	NodeService_GetDestinationStats_FullMethodName      = "/service.NodeService/GetDestinationStats"
+	NodeService_ListInbounds_FullMethodName             = "/service.NodeService/ListInbounds"
+	NodeService_AddInbound_FullMethodName               = "/service.NodeService/AddInbound"
+	NodeService_RemoveInbound_FullMethodName            = "/service.NodeService/RemoveInbound"
+	NodeService_ListOutbounds_FullMethodName            = "/service.NodeService/ListOutbounds"
+	NodeService_AddOutbound_FullMethodName              = "/service.NodeService/AddOutbound"
+	NodeService_ReplaceOutbound_FullMethodName          = "/service.NodeService/ReplaceOutbound"
+	NodeService_RemoveOutbound_FullMethodName           = "/service.NodeService/RemoveOutbound"
+	NodeService_ListRouting_FullMethodName              = "/service.NodeService/ListRouting"
+	NodeService_AddRoutingRule_FullMethodName           = "/service.NodeService/AddRoutingRule"
+	NodeService_RemoveRoutingRule_FullMethodName        = "/service.NodeService/RemoveRoutingRule"
+	NodeService_AddBalancer_FullMethodName              = "/service.NodeService/AddBalancer"
+	NodeService_RemoveBalancer_FullMethodName           = "/service.NodeService/RemoveBalancer"
+	NodeService_SetBlocklist_FullMethodName             = "/service.NodeService/SetBlocklist"
+	NodeService_GetBlocklist_FullMethodName             = "/service.NodeService/GetBlocklist"
 	NodeService_SyncUser_FullMethodName                 = "/service.NodeService/SyncUser"
 	NodeService_SyncUsers_FullMethodName                = "/service.NodeService/SyncUsers"
+	NodeService_Session_FullMethodName                  = "/service.NodeService/Session"
+	NodeService_WatchEvents_FullMethodName              = "/service.NodeService/WatchEvents"
 )
 
 // NodeServiceClient is the client API for NodeService service.
@@ -41,14 +63,36 @@ type NodeServiceClient interface {
 	Start(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*BaseInfoResponse, error)
 	Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
 	GetBaseInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BaseInfoResponse, error)
-	GetLogs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Log], error)
+	GetCoreCrashes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CoreCrashesResponse, error)
+	ValidateConfig(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*ValidateConfigResponse, error)
+	GetConfigHistory(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigHistoryResponse, error)
+	RestoreConfig(ctx context.Context, in *RestoreConfigRequest, opts ...grpc.CallOption) (*BaseInfoResponse, error)
+	GetLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Log], error)
+	WatchAccessLogs(ctx context.Context, in *AccessLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccessLog], error)
 	GetSystemStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SystemStatsResponse, error)
 	GetBackendStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackendStatsResponse, error)
 	GetStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
 	GetUserOnlineStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*OnlineStatResponse, error)
 	GetUserOnlineIpListStats(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatsOnlineIpListResponse, error)
+	GetDestinationStats(ctx context.Context, in *DestinationStatsRequest, opts ...grpc.CallOption) (*DestinationStatsResponse, error)
+	ListInbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InboundsResponse, error)
+	AddInbound(ctx context.Context, in *AddInboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListOutbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OutboundsResponse, error)
+	AddOutbound(ctx context.Context, in *OutboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	ReplaceOutbound(ctx context.Context, in *OutboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListRouting(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoutingResponse, error)
+	AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveRoutingRule(ctx context.Context, in *RemoveRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddBalancer(ctx context.Context, in *AddBalancerRequest, opts ...grpc.CallOption) (*Empty, error)
+	RemoveBalancer(ctx context.Context, in *RemoveBalancerRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetBlocklist(ctx context.Context, in *SetBlocklistRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetBlocklist(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlocklistResponse, error)
 	SyncUser(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[User, Empty], error)
 	SyncUsers(ctx context.Context, in *Users, opts ...grpc.CallOption) (*Empty, error)
+	Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionCommand, SessionMessage], error)
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
 }
 
 type nodeServiceClient struct {
@@ -89,13 +133,53 @@ func (c *nodeServiceClient) GetBaseInfo(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
-func (c *nodeServiceClient) GetLogs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Log], error) {
+func (c *nodeServiceClient) GetCoreCrashes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CoreCrashesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoreCrashesResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetCoreCrashes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ValidateConfig(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*ValidateConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateConfigResponse)
+	err := c.cc.Invoke(ctx, NodeService_ValidateConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetConfigHistory(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConfigHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfigHistoryResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetConfigHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RestoreConfig(ctx context.Context, in *RestoreConfigRequest, opts ...grpc.CallOption) (*BaseInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BaseInfoResponse)
+	err := c.cc.Invoke(ctx, NodeService_RestoreConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Log], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[0], NodeService_GetLogs_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, Log]{ClientStream: stream}
+	x := &grpc.GenericClientStream[LogRequest, Log]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -108,6 +192,25 @@ func (c *nodeServiceClient) GetLogs(ctx context.Context, in *Empty, opts ...grpc
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NodeService_GetLogsClient = grpc.ServerStreamingClient[Log]
 
+func (c *nodeServiceClient) WatchAccessLogs(ctx context.Context, in *AccessLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccessLog], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_WatchAccessLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AccessLogRequest, AccessLog]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchAccessLogsClient = grpc.ServerStreamingClient[AccessLog]
+
 func (c *nodeServiceClient) GetSystemStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SystemStatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SystemStatsResponse)
@@ -158,9 +261,159 @@ func (c *nodeServiceClient) GetUserOnlineIpListStats(ctx context.Context, in *St
 	return out, nil
 }
 
+func (c *nodeServiceClient) GetDestinationStats(ctx context.Context, in *DestinationStatsRequest, opts ...grpc.CallOption) (*DestinationStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DestinationStatsResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetDestinationStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListInbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InboundsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboundsResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListInbounds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddInbound(ctx context.Context, in *AddInboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddInbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveInbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListOutbounds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OutboundsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OutboundsResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListOutbounds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddOutbound(ctx context.Context, in *OutboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddOutbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ReplaceOutbound(ctx context.Context, in *OutboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_ReplaceOutbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveOutbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) ListRouting(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RoutingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListRouting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveRoutingRule(ctx context.Context, in *RemoveRoutingRuleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) AddBalancer(ctx context.Context, in *AddBalancerRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_AddBalancer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) RemoveBalancer(ctx context.Context, in *RemoveBalancerRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_RemoveBalancer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) SetBlocklist(ctx context.Context, in *SetBlocklistRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, NodeService_SetBlocklist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetBlocklist(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlocklistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlocklistResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetBlocklist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) SyncUser(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[User, Empty], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_SyncUser_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[2], NodeService_SyncUser_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +434,38 @@ func (c *nodeServiceClient) SyncUsers(ctx context.Context, in *Users, opts ...gr
 	return out, nil
 }
 
+func (c *nodeServiceClient) Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionCommand, SessionMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[3], NodeService_Session_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SessionCommand, SessionMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_SessionClient = grpc.BidiStreamingClient[SessionCommand, SessionMessage]
+
+func (c *nodeServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[4], NodeService_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
 // NodeServiceServer is the server API for NodeService service.
 // All implementations must embed UnimplementedNodeServiceServer
 // for forward compatibility.
@@ -190,14 +475,36 @@ type NodeServiceServer interface {
 	Start(context.Context, *Backend) (*BaseInfoResponse, error)
 	Stop(context.Context, *Empty) (*Empty, error)
 	GetBaseInfo(context.Context, *Empty) (*BaseInfoResponse, error)
-	GetLogs(*Empty, grpc.ServerStreamingServer[Log]) error
+	GetCoreCrashes(context.Context, *Empty) (*CoreCrashesResponse, error)
+	ValidateConfig(context.Context, *Backend) (*ValidateConfigResponse, error)
+	GetConfigHistory(context.Context, *Empty) (*ConfigHistoryResponse, error)
+	RestoreConfig(context.Context, *RestoreConfigRequest) (*BaseInfoResponse, error)
+	GetLogs(*LogRequest, grpc.ServerStreamingServer[Log]) error
+	WatchAccessLogs(*AccessLogRequest, grpc.ServerStreamingServer[AccessLog]) error
 	GetSystemStats(context.Context, *Empty) (*SystemStatsResponse, error)
 	GetBackendStats(context.Context, *Empty) (*BackendStatsResponse, error)
 	GetStats(context.Context, *StatRequest) (*StatResponse, error)
 	GetUserOnlineStats(context.Context, *StatRequest) (*OnlineStatResponse, error)
 	GetUserOnlineIpListStats(context.Context, *StatRequest) (*StatsOnlineIpListResponse, error)
+	GetDestinationStats(context.Context, *DestinationStatsRequest) (*DestinationStatsResponse, error)
+	ListInbounds(context.Context, *Empty) (*InboundsResponse, error)
+	AddInbound(context.Context, *AddInboundRequest) (*Empty, error)
+	RemoveInbound(context.Context, *RemoveInboundRequest) (*Empty, error)
+	ListOutbounds(context.Context, *Empty) (*OutboundsResponse, error)
+	AddOutbound(context.Context, *OutboundRequest) (*Empty, error)
+	ReplaceOutbound(context.Context, *OutboundRequest) (*Empty, error)
+	RemoveOutbound(context.Context, *RemoveOutboundRequest) (*Empty, error)
+	ListRouting(context.Context, *Empty) (*RoutingResponse, error)
+	AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*Empty, error)
+	RemoveRoutingRule(context.Context, *RemoveRoutingRuleRequest) (*Empty, error)
+	AddBalancer(context.Context, *AddBalancerRequest) (*Empty, error)
+	RemoveBalancer(context.Context, *RemoveBalancerRequest) (*Empty, error)
+	SetBlocklist(context.Context, *SetBlocklistRequest) (*Empty, error)
+	GetBlocklist(context.Context, *Empty) (*BlocklistResponse, error)
 	SyncUser(grpc.ClientStreamingServer[User, Empty]) error
 	SyncUsers(context.Context, *Users) (*Empty, error)
+	Session(grpc.BidiStreamingServer[SessionCommand, SessionMessage]) error
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
 	mustEmbedUnimplementedNodeServiceServer()
 }
 
@@ -217,9 +524,24 @@ func (UnimplementedNodeServiceServer) Stop(context.Context, *Empty) (*Empty, err
 func (UnimplementedNodeServiceServer) GetBaseInfo(context.Context, *Empty) (*BaseInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBaseInfo not implemented")
 }
-func (UnimplementedNodeServiceServer) GetLogs(*Empty, grpc.ServerStreamingServer[Log]) error {
+func (UnimplementedNodeServiceServer) GetCoreCrashes(context.Context, *Empty) (*CoreCrashesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCoreCrashes not implemented")
+}
+func (UnimplementedNodeServiceServer) ValidateConfig(context.Context, *Backend) (*ValidateConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateConfig not implemented")
+}
+func (UnimplementedNodeServiceServer) GetConfigHistory(context.Context, *Empty) (*ConfigHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigHistory not implemented")
+}
+func (UnimplementedNodeServiceServer) RestoreConfig(context.Context, *RestoreConfigRequest) (*BaseInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreConfig not implemented")
+}
+func (UnimplementedNodeServiceServer) GetLogs(*LogRequest, grpc.ServerStreamingServer[Log]) error {
 	return status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
 }
+func (UnimplementedNodeServiceServer) WatchAccessLogs(*AccessLogRequest, grpc.ServerStreamingServer[AccessLog]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAccessLogs not implemented")
+}
 func (UnimplementedNodeServiceServer) GetSystemStats(context.Context, *Empty) (*SystemStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSystemStats not implemented")
 }
@@ -235,12 +557,63 @@ func (UnimplementedNodeServiceServer) GetUserOnlineStats(context.Context, *StatR
 func (UnimplementedNodeServiceServer) GetUserOnlineIpListStats(context.Context, *StatRequest) (*StatsOnlineIpListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserOnlineIpListStats not implemented")
 }
+func (UnimplementedNodeServiceServer) GetDestinationStats(context.Context, *DestinationStatsRequest) (*DestinationStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDestinationStats not implemented")
+}
+func (UnimplementedNodeServiceServer) ListInbounds(context.Context, *Empty) (*InboundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInbounds not implemented")
+}
+func (UnimplementedNodeServiceServer) AddInbound(context.Context, *AddInboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInbound not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveInbound(context.Context, *RemoveInboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveInbound not implemented")
+}
+func (UnimplementedNodeServiceServer) ListOutbounds(context.Context, *Empty) (*OutboundsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOutbounds not implemented")
+}
+func (UnimplementedNodeServiceServer) AddOutbound(context.Context, *OutboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOutbound not implemented")
+}
+func (UnimplementedNodeServiceServer) ReplaceOutbound(context.Context, *OutboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceOutbound not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveOutbound(context.Context, *RemoveOutboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOutbound not implemented")
+}
+func (UnimplementedNodeServiceServer) ListRouting(context.Context, *Empty) (*RoutingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRouting not implemented")
+}
+func (UnimplementedNodeServiceServer) AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRoutingRule not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveRoutingRule(context.Context, *RemoveRoutingRuleRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRoutingRule not implemented")
+}
+func (UnimplementedNodeServiceServer) AddBalancer(context.Context, *AddBalancerRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBalancer not implemented")
+}
+func (UnimplementedNodeServiceServer) RemoveBalancer(context.Context, *RemoveBalancerRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBalancer not implemented")
+}
+func (UnimplementedNodeServiceServer) SetBlocklist(context.Context, *SetBlocklistRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBlocklist not implemented")
+}
+func (UnimplementedNodeServiceServer) GetBlocklist(context.Context, *Empty) (*BlocklistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlocklist not implemented")
+}
 func (UnimplementedNodeServiceServer) SyncUser(grpc.ClientStreamingServer[User, Empty]) error {
 	return status.Errorf(codes.Unimplemented, "method SyncUser not implemented")
 }
 func (UnimplementedNodeServiceServer) SyncUsers(context.Context, *Users) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SyncUsers not implemented")
 }
+func (UnimplementedNodeServiceServer) Session(grpc.BidiStreamingServer[SessionCommand, SessionMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedNodeServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
 func (UnimplementedNodeServiceServer) mustEmbedUnimplementedNodeServiceServer() {}
 func (UnimplementedNodeServiceServer) testEmbeddedByValue()                     {}
 
@@ -316,96 +689,179 @@ func _NodeService_GetBaseInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_GetLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(NodeServiceServer).GetLogs(m, &grpc.GenericServerStream[Empty, Log]{ServerStream: stream})
-}
-
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type NodeService_GetLogsServer = grpc.ServerStreamingServer[Log]
-
-func _NodeService_GetSystemStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _NodeService_GetCoreCrashes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).GetSystemStats(ctx, in)
+		return srv.(NodeServiceServer).GetCoreCrashes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NodeService_GetSystemStats_FullMethodName,
+		FullMethod: NodeService_GetCoreCrashes_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).GetSystemStats(ctx, req.(*Empty))
+		return srv.(NodeServiceServer).GetCoreCrashes(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_GetBackendStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _NodeService_ValidateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Backend)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).GetBackendStats(ctx, in)
+		return srv.(NodeServiceServer).ValidateConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NodeService_GetBackendStats_FullMethodName,
+		FullMethod: NodeService_ValidateConfig_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).GetBackendStats(ctx, req.(*Empty))
+		return srv.(NodeServiceServer).ValidateConfig(ctx, req.(*Backend))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatRequest)
+func _NodeService_GetConfigHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).GetStats(ctx, in)
+		return srv.(NodeServiceServer).GetConfigHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NodeService_GetStats_FullMethodName,
+		FullMethod: NodeService_GetConfigHistory_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).GetStats(ctx, req.(*StatRequest))
+		return srv.(NodeServiceServer).GetConfigHistory(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_GetUserOnlineStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatRequest)
+func _NodeService_RestoreConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreConfigRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).GetUserOnlineStats(ctx, in)
+		return srv.(NodeServiceServer).RestoreConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: NodeService_GetUserOnlineStats_FullMethodName,
+		FullMethod: NodeService_RestoreConfig_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NodeServiceServer).GetUserOnlineStats(ctx, req.(*StatRequest))
+		return srv.(NodeServiceServer).RestoreConfig(ctx, req.(*RestoreConfigRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _NodeService_GetUserOnlineIpListStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatRequest)
+func _NodeService_GetLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).GetLogs(m, &grpc.GenericServerStream[LogRequest, Log]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_GetLogsServer = grpc.ServerStreamingServer[Log]
+
+func _NodeService_WatchAccessLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AccessLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).WatchAccessLogs(m, &grpc.GenericServerStream[AccessLogRequest, AccessLog]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchAccessLogsServer = grpc.ServerStreamingServer[AccessLog]
+
+func _NodeService_GetSystemStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(NodeServiceServer).GetUserOnlineIpListStats(ctx, in)
+		return srv.(NodeServiceServer).GetSystemStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetSystemStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetSystemStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetBackendStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetBackendStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetBackendStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetBackendStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetStats(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetUserOnlineStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetUserOnlineStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetUserOnlineStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetUserOnlineStats(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetUserOnlineIpListStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetUserOnlineIpListStats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
@@ -417,6 +873,276 @@ func _NodeService_GetUserOnlineIpListStats_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_GetDestinationStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DestinationStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetDestinationStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetDestinationStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetDestinationStats(ctx, req.(*DestinationStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListInbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListInbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListInbounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListInbounds(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddInbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddInbound(ctx, req.(*AddInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveInbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveInbound(ctx, req.(*RemoveInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListOutbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListOutbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListOutbounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListOutbounds(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddOutbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddOutbound(ctx, req.(*OutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ReplaceOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ReplaceOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ReplaceOutbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ReplaceOutbound(ctx, req.(*OutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveOutbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveOutbound(ctx, req.(*RemoveOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_ListRouting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListRouting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListRouting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListRouting(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddRoutingRule(ctx, req.(*AddRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveRoutingRule(ctx, req.(*RemoveRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_AddBalancer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBalancerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).AddBalancer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_AddBalancer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).AddBalancer(ctx, req.(*AddBalancerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_RemoveBalancer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBalancerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).RemoveBalancer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_RemoveBalancer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).RemoveBalancer(ctx, req.(*RemoveBalancerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_SetBlocklist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBlocklistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).SetBlocklist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_SetBlocklist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).SetBlocklist(ctx, req.(*SetBlocklistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetBlocklist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetBlocklist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetBlocklist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetBlocklist(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_SyncUser_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(NodeServiceServer).SyncUser(&grpc.GenericServerStream[User, Empty]{ServerStream: stream})
 }
@@ -442,6 +1168,24 @@ func _NodeService_SyncUsers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeServiceServer).Session(&grpc.GenericServerStream[SessionCommand, SessionMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_SessionServer = grpc.BidiStreamingServer[SessionCommand, SessionMessage]
+
+func _NodeService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
 // NodeService_ServiceDesc is the grpc.ServiceDesc for NodeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -461,6 +1205,22 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBaseInfo",
 			Handler:    _NodeService_GetBaseInfo_Handler,
 		},
+		{
+			MethodName: "GetCoreCrashes",
+			Handler:    _NodeService_GetCoreCrashes_Handler,
+		},
+		{
+			MethodName: "ValidateConfig",
+			Handler:    _NodeService_ValidateConfig_Handler,
+		},
+		{
+			MethodName: "GetConfigHistory",
+			Handler:    _NodeService_GetConfigHistory_Handler,
+		},
+		{
+			MethodName: "RestoreConfig",
+			Handler:    _NodeService_RestoreConfig_Handler,
+		},
 		{
 			MethodName: "GetSystemStats",
 			Handler:    _NodeService_GetSystemStats_Handler,
@@ -481,6 +1241,66 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserOnlineIpListStats",
 			Handler:    _NodeService_GetUserOnlineIpListStats_Handler,
 		},
+		{
+			MethodName: "GetDestinationStats",
+			Handler:    _NodeService_GetDestinationStats_Handler,
+		},
+		{
+			MethodName: "ListInbounds",
+			Handler:    _NodeService_ListInbounds_Handler,
+		},
+		{
+			MethodName: "AddInbound",
+			Handler:    _NodeService_AddInbound_Handler,
+		},
+		{
+			MethodName: "RemoveInbound",
+			Handler:    _NodeService_RemoveInbound_Handler,
+		},
+		{
+			MethodName: "ListOutbounds",
+			Handler:    _NodeService_ListOutbounds_Handler,
+		},
+		{
+			MethodName: "AddOutbound",
+			Handler:    _NodeService_AddOutbound_Handler,
+		},
+		{
+			MethodName: "ReplaceOutbound",
+			Handler:    _NodeService_ReplaceOutbound_Handler,
+		},
+		{
+			MethodName: "RemoveOutbound",
+			Handler:    _NodeService_RemoveOutbound_Handler,
+		},
+		{
+			MethodName: "ListRouting",
+			Handler:    _NodeService_ListRouting_Handler,
+		},
+		{
+			MethodName: "AddRoutingRule",
+			Handler:    _NodeService_AddRoutingRule_Handler,
+		},
+		{
+			MethodName: "RemoveRoutingRule",
+			Handler:    _NodeService_RemoveRoutingRule_Handler,
+		},
+		{
+			MethodName: "AddBalancer",
+			Handler:    _NodeService_AddBalancer_Handler,
+		},
+		{
+			MethodName: "RemoveBalancer",
+			Handler:    _NodeService_RemoveBalancer_Handler,
+		},
+		{
+			MethodName: "SetBlocklist",
+			Handler:    _NodeService_SetBlocklist_Handler,
+		},
+		{
+			MethodName: "GetBlocklist",
+			Handler:    _NodeService_GetBlocklist_Handler,
+		},
 		{
 			MethodName: "SyncUsers",
 			Handler:    _NodeService_SyncUsers_Handler,
@@ -492,11 +1312,27 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _NodeService_GetLogs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchAccessLogs",
+			Handler:       _NodeService_WatchAccessLogs_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SyncUser",
 			Handler:       _NodeService_SyncUser_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Session",
+			Handler:       _NodeService_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _NodeService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "common/service.proto",
 }
@@ -1,10 +1,12 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -24,12 +26,48 @@ type Config struct {
 	Debug                 bool
 	GeneratedConfigPath   string
 	LogBufferSize         int
+	PanelAddress          string
+	PanelCAFile           string
+	PanelServerName       string
+	WebhookURLs           []string
+	WebhookSecret         string
+	WebhookEvents         []string
+	WebhookOutboxPath     string
+	BandwidthQuotaGB      int
+	MetricsPort           int
+	MetricsToken          string
+	TracingEndpoint       string
+	TracingInsecure       bool
+	LogFormat             string
+	LogLevel              string
+	LogLevels             []string
+	LogMaxSizeMB          int
+	LogRotateHours        int
+	LogMaxAgeDays         int
+	LogMaxFiles           int
+	LogCompress           bool
+	AccessLogJSONLPath    string
+	LogSyslogAddress      string
+	LogSyslogLevel        string
+	LogSyslogCAFile       string
+	LogHTTPURL            string
+	LogHTTPToken          string
+	LogHTTPLevel          string
+	AnalyticsRetention    int
+	AnalyticsHash         bool
+	AnalyticsHashSalt     string
+	RestartMaxFailures    int
+	RestartWindow         int
+	RestartCooldown       int
+	ConfigHistoryPath     string
+	ConfigHistorySize     int
+	BlocklistPath         string
 }
 
 func Load() (*Config, error) {
 	err := godotenv.Load()
 	if err != nil {
-		log.Printf("[Warning] Failed to load env file, if you're using 'Docker' and you set 'environment' or 'env_file' variable, don't worry, everything is fine. Error: %v", err)
+		slog.Warn("failed to load env file, if you're using 'Docker' and you set 'environment' or 'env_file' variable, don't worry, everything is fine", "error", err)
 	}
 
 	cfg := &Config{
@@ -44,11 +82,47 @@ func Load() (*Config, error) {
 		ServiceProtocol:       GetEnv("SERVICE_PROTOCOL", "grpc"),
 		Debug:                 GetEnvAsBool("DEBUG", false),
 		LogBufferSize:         GetEnvAsInt("LOG_BUFFER_SIZE", 1000),
+		PanelAddress:          GetEnv("PANEL_ADDRESS", ""),
+		PanelCAFile:           GetEnv("PANEL_CA_FILE", ""),
+		PanelServerName:       GetEnv("PANEL_SERVER_NAME", ""),
+		WebhookURLs:           GetEnvAsSlice("WEBHOOK_URLS"),
+		WebhookSecret:         GetEnv("WEBHOOK_SECRET", ""),
+		WebhookEvents:         GetEnvAsSlice("WEBHOOK_EVENTS"),
+		WebhookOutboxPath:     GetEnv("WEBHOOK_OUTBOX_PATH", "/var/lib/pg-node/webhook_outbox/"),
+		BandwidthQuotaGB:      GetEnvAsInt("BANDWIDTH_QUOTA_GB", 0),
+		MetricsPort:           GetEnvAsInt("METRICS_PORT", 0),
+		MetricsToken:          GetEnv("METRICS_TOKEN", ""),
+		TracingEndpoint:       GetEnv("TRACING_ENDPOINT", ""),
+		TracingInsecure:       GetEnvAsBool("TRACING_INSECURE", true),
+		LogFormat:             GetEnv("LOG_FORMAT", "text"),
+		LogLevel:              GetEnv("LOG_LEVEL", ""),
+		LogLevels:             GetEnvAsSlice("LOG_LEVELS"),
+		LogMaxSizeMB:          GetEnvAsInt("LOG_MAX_SIZE_MB", 100),
+		LogRotateHours:        GetEnvAsInt("LOG_ROTATE_HOURS", 24),
+		LogMaxAgeDays:         GetEnvAsInt("LOG_MAX_AGE_DAYS", 14),
+		LogMaxFiles:           GetEnvAsInt("LOG_MAX_FILES", 10),
+		LogCompress:           GetEnvAsBool("LOG_COMPRESS", true),
+		AccessLogJSONLPath:    GetEnv("ACCESS_LOG_JSONL_PATH", ""),
+		LogSyslogAddress:      GetEnv("LOG_SYSLOG_ADDRESS", ""),
+		LogSyslogLevel:        GetEnv("LOG_SYSLOG_LEVEL", ""),
+		LogSyslogCAFile:       GetEnv("LOG_SYSLOG_CA_FILE", ""),
+		LogHTTPURL:            GetEnv("LOG_HTTP_URL", ""),
+		LogHTTPToken:          GetEnv("LOG_HTTP_TOKEN", ""),
+		LogHTTPLevel:          GetEnv("LOG_HTTP_LEVEL", ""),
+		AnalyticsRetention:    GetEnvAsInt("ANALYTICS_RETENTION_HOURS", 0),
+		AnalyticsHash:         GetEnvAsBool("ANALYTICS_HASH_DESTINATIONS", false),
+		AnalyticsHashSalt:     GetEnv("ANALYTICS_HASH_SALT", ""),
+		RestartMaxFailures:    GetEnvAsInt("CORE_RESTART_MAX_FAILURES", 5),
+		RestartWindow:         GetEnvAsInt("CORE_RESTART_WINDOW_SECONDS", 300),
+		RestartCooldown:       GetEnvAsInt("CORE_RESTART_COOLDOWN_SECONDS", 0),
+		ConfigHistoryPath:     GetEnv("CONFIG_HISTORY_PATH", "/var/lib/pg-node/config_history/"),
+		ConfigHistorySize:     GetEnvAsInt("CONFIG_HISTORY_SIZE", 10),
+		BlocklistPath:         GetEnv("BLOCKLIST_PATH", "/var/lib/pg-node/blocklists.json"),
 	}
 
 	cfg.ApiKey, err = GetEnvAsUUID("API_KEY")
 	if err != nil {
-		log.Printf("[Error] Failed to load API Key, error: %v", err)
+		slog.Error("failed to load API Key", "error", err)
 	}
 
 	nodeHostStr := GetEnv("NODE_HOST", "0.0.0.0")
@@ -58,7 +132,7 @@ func Load() (*Config, error) {
 	if re.MatchString(nodeHostStr) {
 		cfg.NodeHost = nodeHostStr
 	} else {
-		log.Println(nodeHostStr, " is not a valid IP address.\n NODE_HOST will be set to 127.0.0.1")
+		slog.Warn("NODE_HOST is not a valid IP address, 127.0.0.1 will be used", "node_host", nodeHostStr)
 		cfg.NodeHost = "127.0.0.1"
 	}
 
@@ -69,6 +143,8 @@ func Load() (*Config, error) {
 func NewTestConfig(generatedConfigPath string, key uuid.UUID) *Config {
 	cfg, _ := Load()
 	cfg.GeneratedConfigPath = generatedConfigPath
+	cfg.ConfigHistoryPath = filepath.Join(generatedConfigPath, "config_history")
+	cfg.BlocklistPath = filepath.Join(generatedConfigPath, "blocklists.json")
 	cfg.ApiKey = key
 	return cfg
 }
@@ -97,6 +173,17 @@ func GetEnvAsInt(name string, defaultVal int) int {
 	return defaultVal
 }
 
+// GetEnvAsSlice splits a comma separated variable, empty items are skipped.
+func GetEnvAsSlice(name string) []string {
+	var values []string
+	for _, item := range strings.Split(GetEnv(name, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
 func GetEnvAsUUID(name string) (uuid.UUID, error) {
 	valStr := GetEnv(name, "")
 
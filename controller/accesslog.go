@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+// accessLogSinkBuffer is how many records the file can fall behind during bursts
+const accessLogSinkBuffer = 4096
+
+// SubscribeAccessLogs subscribes to the connection records parsed from the backend access logs,
+// the buffered records selected by the request come first.
+func (c *Controller) SubscribeAccessLogs(req *common.AccessLogRequest) ([]nodeLogger.Line, *nodeLogger.Subscription) {
+	replay := nodeLogger.Replay{Tail: int(req.GetTail())}
+	if req.GetSince() > 0 {
+		replay.Since = time.UnixMilli(req.GetSince())
+	}
+	filter := &nodeLogger.Filter{Records: true, Email: req.GetEmail(), Inbound: req.GetInbound()}
+
+	return c.Backend().Logs().Subscribe(nodeLogger.SubscriberBuffer, replay, filter)
+}
+
+// writeAccessLogs appends the connection records of the backend to a JSONL file until ctx is done.
+func writeAccessLogs(ctx context.Context, path string, hub *nodeLogger.Hub) {
+	file, err := nodeLogger.OpenFile(path)
+	if err != nil {
+		logger.Error("failed to open access log file", "file", path, "error", err)
+		return
+	}
+	defer file.Close()
+
+	_, sub := hub.Subscribe(accessLogSinkBuffer, nodeLogger.Replay{}, &nodeLogger.Filter{Records: true})
+	defer sub.Close()
+
+	var dropped uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-sub.C:
+			data, err := protojson.Marshal(line.Record)
+			if err != nil {
+				continue
+			}
+			if _, err = file.Write(append(data, '\n')); err != nil {
+				logger.Warn("failed to write access log record", "file", path, "error", err)
+			}
+
+			if total := sub.Dropped(); total > dropped {
+				logger.Warn("access log file is not keeping up, records dropped", "file", path, "dropped", total-dropped)
+				dropped = total
+			}
+		}
+	}
+}
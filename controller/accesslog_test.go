@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func TestWriteAccessLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.jsonl")
+	hub := nodeLogger.NewHub(8)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		writeAccessLogs(ctx, path, hub)
+		close(done)
+	}()
+
+	record := &common.AccessLog{SourceIp: "1.2.3.4", SourcePort: 5000, Network: "tcp", DestinationHost: "example.com", DestinationPort: 443, Email: "1.alice"}
+	// Wait for the sink to subscribe, records published before are not written
+	deadline := time.Now().Add(time.Second)
+	for {
+		hub.Publish(nodeLogger.Line{Text: "[Info] not an access log"})
+		hub.Publish(nodeLogger.Line{Text: "accepted", Access: true, Record: record})
+
+		data, _ := os.ReadFile(path)
+		if len(data) > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open sink file: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		t.Fatal("no record was written")
+	}
+	var got common.AccessLog
+	if err = protojson.Unmarshal(scanner.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+	}
+	if !proto.Equal(&got, record) {
+		t.Fatalf("unexpected record:\n got: %v\nwant: %v", &got, record)
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+// userHash identifies what the backend was given for a user.
+func userHash(user *common.User) string {
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(user)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func userHashes(users []*common.User) map[string]string {
+	hashes := make(map[string]string, len(users))
+	for _, user := range users {
+		hashes[user.GetEmail()] = userHash(user)
+	}
+	return hashes
+}
+
+// runs reports whether version is the config of the start request.
+func runs(version *common.ConfigVersion, detail *common.Backend) bool {
+	return version.GetType() == detail.GetType() &&
+		version.GetConfigHash() == backend.ConfigHash([]byte(detail.GetConfig())) &&
+		slices.Equal(version.GetExcludeInbounds(), detail.GetExcludeInbounds())
+}
+
+// userDiff returns the users of the request the backend does not have as sent,
+// users it has that are not in the request come back without inbounds so they are removed.
+func userDiff(running map[string]string, users []*common.User) []*common.User {
+	var diff []*common.User
+	requested := make(map[string]struct{}, len(users))
+	for _, user := range users {
+		requested[user.GetEmail()] = struct{}{}
+		if running[user.GetEmail()] != userHash(user) {
+			diff = append(diff, user)
+		}
+	}
+	for email := range running {
+		if _, ok := requested[email]; !ok {
+			diff = append(diff, &common.User{Email: email})
+		}
+	}
+	return diff
+}
+
+// AdoptBackend takes over the running backend for a start request with the config it runs, so a panel
+// reconnecting after a network blip does not restart the core. Users that differ are synced to it,
+// false means the request needs a new backend.
+func (c *Controller) AdoptBackend(ctx context.Context, detail *common.Backend) bool {
+	c.mu.RLock()
+	running, users := c.backend, maps.Clone(c.users)
+	adoptable := running != nil && running.Started() && c.running != nil && runs(c.running, detail)
+	c.mu.RUnlock()
+	if !adoptable {
+		return false
+	}
+
+	if diff := userDiff(users, detail.GetUsers()); len(diff) > 0 {
+		var err error
+		if patcher, ok := running.(backend.UserPatcher); ok {
+			err = patcher.PatchUsers(ctx, diff)
+		} else {
+			err = running.SyncUsers(ctx, detail.GetUsers())
+		}
+		if err != nil {
+			logger.Warn("failed to sync users to the running backend, starting a new one", "error", err)
+			return false
+		}
+		logger.Info("synced changed users to the running backend", "users", len(diff))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.backend != running {
+		return false
+	}
+	c.users = userHashes(detail.GetUsers())
+	return true
+}
+
+// SyncUser syncs a user to the backend and remembers it for adopting the backend.
+func (c *Controller) SyncUser(ctx context.Context, user *common.User) error {
+	running := c.Backend()
+	if running == nil {
+		return errNoBackend
+	}
+	if err := running.SyncUser(ctx, user); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.backend == running && c.users != nil {
+		if len(user.GetInbounds()) == 0 {
+			delete(c.users, user.GetEmail())
+		} else {
+			c.users[user.GetEmail()] = userHash(user)
+		}
+	}
+	return nil
+}
+
+// SyncUsers replaces the users of the backend and remembers them for adopting the backend.
+func (c *Controller) SyncUsers(ctx context.Context, users []*common.User) error {
+	running := c.Backend()
+	if running == nil {
+		return errNoBackend
+	}
+	if err := running.SyncUsers(ctx, users); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.backend == running {
+		c.users = userHashes(users)
+	}
+	return nil
+}
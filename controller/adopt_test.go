@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+func TestUserDiff(t *testing.T) {
+	kept := &common.User{Email: "kept", Inbounds: []string{"in"}}
+	changed := &common.User{Email: "changed", Inbounds: []string{"in"}}
+	running := userHashes([]*common.User{kept, changed, {Email: "removed", Inbounds: []string{"in"}}})
+
+	added := &common.User{Email: "added", Inbounds: []string{"in"}}
+	changedNow := &common.User{Email: "changed", Inbounds: []string{"in", "other"}}
+	diff := userDiff(running, []*common.User{kept, changedNow, added})
+
+	var emails []string
+	for _, user := range diff {
+		emails = append(emails, user.GetEmail())
+		if user.GetEmail() == "removed" && len(user.GetInbounds()) != 0 {
+			t.Errorf("removed user must come back without inbounds: %v", user)
+		}
+	}
+	slices.Sort(emails)
+	if !slices.Equal(emails, []string{"added", "changed", "removed"}) {
+		t.Fatalf("diff = %v", emails)
+	}
+
+	if diff = userDiff(running, []*common.User{kept, changed, {Email: "removed", Inbounds: []string{"in"}}}); len(diff) != 0 {
+		t.Fatalf("same users must not differ, got %v", diff)
+	}
+}
+
+func TestRuns(t *testing.T) {
+	config := `{"inbounds":[]}`
+	version := &common.ConfigVersion{Type: common.BackendType_XRAY, ConfigHash: backend.ConfigHash([]byte(config)), ExcludeInbounds: []string{"a"}}
+
+	tests := []struct {
+		name   string
+		detail *common.Backend
+		want   bool
+	}{
+		{"same", &common.Backend{Type: common.BackendType_XRAY, Config: config, ExcludeInbounds: []string{"a"}}, true},
+		{"other config", &common.Backend{Type: common.BackendType_XRAY, Config: `{}`, ExcludeInbounds: []string{"a"}}, false},
+		{"other type", &common.Backend{Type: common.BackendType_SING_BOX, Config: config, ExcludeInbounds: []string{"a"}}, false},
+		{"other excludes", &common.Backend{Type: common.BackendType_XRAY, Config: config}, false},
+	}
+	for _, tt := range tests {
+		if got := runs(version, tt.detail); got != tt.want {
+			t.Errorf("%s: runs = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+var errAnalyticsDisabled = status.Error(codes.FailedPrecondition, "destination analytics are disabled on this node")
+
+// DestinationStats returns the top destinations of users and the outbound hits counted from the access logs.
+func (c *Controller) DestinationStats(req *common.DestinationStatsRequest) (*common.DestinationStatsResponse, error) {
+	if c.analytics == nil {
+		return nil, errAnalyticsDisabled
+	}
+	window := time.Duration(req.GetWindow()) * time.Second
+	return c.analytics.Stats(req.GetEmail(), window, int(req.GetLimit())), nil
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	bandwidthCheckInterval = time.Minute
+	bandwidthUsageFile     = "bandwidth_usage.json"
+)
+
+// bandwidthUsage is the host traffic accounted in the current calendar month (UTC).
+type bandwidthUsage struct {
+	Period   string `json:"period"`
+	Bytes    uint64 `json:"bytes"`
+	Notified bool   `json:"notified"`
+}
+
+// add accounts delta bytes at now, starting over when the month changes,
+// and reports whether the quota was used up for the first time in this period.
+func (u *bandwidthUsage) add(now time.Time, delta, quota uint64) bool {
+	if period := now.UTC().Format("2006-01"); u.Period != period {
+		*u = bandwidthUsage{Period: period}
+	}
+
+	u.Bytes += delta
+	if u.Notified || u.Bytes < quota {
+		return false
+	}
+	u.Notified = true
+	return true
+}
+
+func loadBandwidthUsage(path string) *bandwidthUsage {
+	usage := &bandwidthUsage{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return usage
+	}
+	if err = json.Unmarshal(data, usage); err != nil {
+		logger.Warn("failed to read bandwidth usage, starting over", "error", err)
+	}
+	return usage
+}
+
+func (u *bandwidthUsage) save(path string) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(path, data, 0o600)
+}
+
+// watchBandwidthQuota accounts host traffic across restarts and publishes an event once the monthly quota is used up.
+// It stops when ctx is done.
+func (c *Controller) watchBandwidthQuota(ctx context.Context, quotaGB int) {
+	quota := uint64(quotaGB) << 30
+	path := filepath.Join(c.cfg.GeneratedConfigPath, bandwidthUsageFile)
+	usage := loadBandwidthUsage(path)
+
+	previous, err := tools.GetHostTraffic()
+	if err != nil {
+		logger.Warn("failed to read host traffic, bandwidth quota disabled", "error", err)
+		return
+	}
+
+	ticker := time.NewTicker(bandwidthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := tools.GetHostTraffic()
+		if err != nil {
+			logger.Warn("failed to read host traffic", "error", err)
+			continue
+		}
+
+		delta := current - previous
+		// Counters went backwards, an interface was reset
+		if current < previous {
+			delta = current
+		}
+		previous = current
+
+		if usage.add(time.Now(), delta, quota) {
+			logger.Warn("host bandwidth quota reached", "quota_gb", quotaGB, "period", usage.Period)
+			events.Publish(&common.Event{
+				Type:     common.EventType_BANDWIDTH_QUOTA_REACHED,
+				Severity: common.EventSeverity_CRITICAL,
+				Message:  fmt.Sprintf("host bandwidth quota of %d GB reached", quotaGB),
+				Details: map[string]string{
+					"period": usage.Period,
+					"bytes":  strconv.FormatUint(usage.Bytes, 10),
+					"quota":  strconv.FormatUint(quota, 10),
+				},
+			})
+		}
+
+		if err = usage.save(path); err != nil {
+			logger.Warn("failed to save bandwidth usage", "error", err)
+		}
+	}
+}
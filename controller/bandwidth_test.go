@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/config"
+)
+
+func TestBandwidthUsage_Add(t *testing.T) {
+	october := time.Date(2026, time.October, 30, 12, 0, 0, 0, time.UTC)
+	november := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+	usage := &bandwidthUsage{}
+	if usage.add(october, 60, 100) {
+		t.Fatal("quota reported before it was used up")
+	}
+	if !usage.add(october, 40, 100) {
+		t.Fatal("quota not reported once used up")
+	}
+	if usage.add(october, 10, 100) {
+		t.Fatal("quota must be reported only once per period")
+	}
+
+	if usage.add(november, 10, 100) {
+		t.Fatal("new period must start from zero")
+	}
+	if usage.Period != "2026-11" || usage.Bytes != 10 {
+		t.Fatalf("unexpected usage after period change: %+v", usage)
+	}
+}
+
+func TestBandwidthUsage_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), bandwidthUsageFile)
+
+	usage := &bandwidthUsage{Period: "2026-10", Bytes: 42, Notified: true}
+	if err := usage.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded := loadBandwidthUsage(path); *loaded != *usage {
+		t.Fatalf("expected %+v, got %+v", usage, loaded)
+	}
+}
+
+func TestController_ShutdownStopsWatchers(t *testing.T) {
+	c := &Controller{cfg: &config.Config{BandwidthQuotaGB: 1, GeneratedConfigPath: t.TempDir()}}
+	// Nothing was started yet
+	c.Shutdown()
+
+	c.StartWatchers()
+	stopped := make(chan struct{})
+	go func() {
+		c.Shutdown()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not stop the bandwidth watcher")
+	}
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tools"
+)
+
+var (
+	// blocklistNamePattern keeps list names usable in the outbound tags the lists block through
+	blocklistNamePattern = regexp.MustCompile(`^[\w.-]{1,64}$`)
+	// geoNamePattern matches geosite and geoip entries like category-ads-all, geolocation-!cn or google@ads
+	geoNamePattern = regexp.MustCompile(`^[\w.!@-]+$`)
+)
+
+// blocklistSubscriberBuffer is how many records counting hits can fall behind during bursts
+const blocklistSubscriberBuffer = 1024
+
+var errBlocklistsUnsupported = status.Error(codes.Unimplemented, "the backend can not change blocklists while running")
+
+// blocklists keeps the lists the panel set and the connections each blocked since then. With a path they are
+// stored in a file, so a node that restarts blocks them before the panel reconnects.
+type blocklists struct {
+	path  string
+	lists []*common.Blocklist
+	hits  map[string]uint64
+	mu    sync.RWMutex
+	// update keeps a change from being applied and stored while another one is
+	update sync.Mutex
+}
+
+func newBlocklists(path string) *blocklists {
+	b := &blocklists{path: path, hits: make(map[string]uint64)}
+	if path == "" {
+		return b
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Error("failed to load blocklists", "error", err)
+		}
+		return b
+	}
+	request := &common.SetBlocklistRequest{}
+	if err = protojson.Unmarshal(data, request); err == nil {
+		err = validateBlocklists(request.GetLists())
+	}
+	if err != nil {
+		logger.Warn("ignoring invalid blocklists file", "error", err)
+		return b
+	}
+	b.lists = request.GetLists()
+	return b
+}
+
+func validateBlocklists(lists []*common.Blocklist) error {
+	names := make(map[string]struct{}, len(lists))
+	for _, list := range lists {
+		name := list.GetName()
+		if !blocklistNamePattern.MatchString(name) {
+			return status.Errorf(codes.InvalidArgument, "invalid blocklist name %q", name)
+		}
+		if _, ok := names[name]; ok {
+			return status.Errorf(codes.InvalidArgument, "blocklist %q is set twice", name)
+		}
+		names[name] = struct{}{}
+
+		for _, domain := range list.GetDomains() {
+			if domain == "" || strings.ContainsAny(domain, ": \t,") {
+				return status.Errorf(codes.InvalidArgument, "blocklist %q: invalid domain %q", name, domain)
+			}
+		}
+		for _, cidr := range list.GetCidrs() {
+			if _, err := netip.ParsePrefix(cidr); err != nil {
+				if _, err = netip.ParseAddr(cidr); err != nil {
+					return status.Errorf(codes.InvalidArgument, "blocklist %q: invalid cidr %q", name, cidr)
+				}
+			}
+		}
+		for _, entry := range slices.Concat(list.GetGeosite(), list.GetGeoip()) {
+			if !geoNamePattern.MatchString(entry) {
+				return status.Errorf(codes.InvalidArgument, "blocklist %q: invalid geosite or geoip %q", name, entry)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *blocklists) get() []*common.Blocklist {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lists
+}
+
+// set replaces the lists, the hits of lists that are kept carry on.
+func (b *blocklists) set(lists []*common.Blocklist) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.path != "" {
+		if err := b.save(lists); err != nil {
+			return err
+		}
+	}
+	b.lists = lists
+	for name := range b.hits {
+		if !slices.ContainsFunc(lists, func(list *common.Blocklist) bool { return list.GetName() == name }) {
+			delete(b.hits, name)
+		}
+	}
+	return nil
+}
+
+func (b *blocklists) save(lists []*common.Blocklist) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(&common.SetBlocklistRequest{Lists: lists})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(b.path), 0o700); err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(b.path, data, 0o600)
+}
+
+// count adds a hit to the list a connection record was blocked by.
+func (b *blocklists) count(record *common.AccessLog) {
+	name, ok := backend.BlocklistName(record.GetOutbound())
+	if !ok {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if slices.ContainsFunc(b.lists, func(list *common.Blocklist) bool { return list.GetName() == name }) {
+		b.hits[name]++
+	}
+}
+
+// consume counts the blocked connections in the records of the hub until ctx is done.
+func (b *blocklists) consume(ctx context.Context, hub *nodeLogger.Hub) {
+	_, sub := hub.Subscribe(blocklistSubscriberBuffer, nodeLogger.Replay{}, &nodeLogger.Filter{Records: true})
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			b.count(line.Record)
+		}
+	}
+}
+
+func (b *blocklists) response() *common.BlocklistResponse {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	response := &common.BlocklistResponse{Lists: b.lists}
+	for _, list := range b.lists {
+		response.Stats = append(response.Stats, &common.BlocklistStat{Name: list.GetName(), Hits: b.hits[list.GetName()]})
+	}
+	return response
+}
+
+// SetBlocklist applies the lists to the running backend and stores them once it took them, a new backend
+// starts with them. Lists the backend rejects are neither kept nor stored.
+func (c *Controller) SetBlocklist(ctx context.Context, req *common.SetBlocklistRequest) error {
+	if err := validateBlocklists(req.GetLists()); err != nil {
+		return err
+	}
+
+	c.blocklists.update.Lock()
+	defer c.blocklists.update.Unlock()
+
+	var manager backend.BlocklistManager
+	if running := c.Backend(); running != nil && running.Started() {
+		var ok bool
+		if manager, ok = running.(backend.BlocklistManager); !ok {
+			return errBlocklistsUnsupported
+		}
+		if err := manager.SetBlocklists(ctx, req.GetLists()); err != nil {
+			return err
+		}
+	}
+
+	previous := c.blocklists.get()
+	if err := c.blocklists.set(req.GetLists()); err != nil {
+		// The backend goes back to the lists it would start with again
+		if manager != nil {
+			if restoreErr := manager.SetBlocklists(ctx, previous); restoreErr != nil {
+				logger.Error("failed to restore blocklists", "error", restoreErr)
+			}
+		}
+		return status.Errorf(codes.Internal, "failed to store blocklists: %v", err)
+	}
+	logger.Info("blocklists set", "lists", len(req.GetLists()))
+	return nil
+}
+
+// Blocklist returns the lists the node blocks with the hits of each.
+func (c *Controller) Blocklist() *common.BlocklistResponse {
+	return c.blocklists.response()
+}
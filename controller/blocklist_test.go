@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+func TestValidateBlocklists(t *testing.T) {
+	valid := []*common.Blocklist{{
+		Name:    "abuse-1",
+		Domains: []string{"example.com"},
+		Cidrs:   []string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"},
+		Geosite: []string{"category-ads-all", "geolocation-!cn"},
+		Geoip:   []string{"private"},
+	}}
+	if err := validateBlocklists(valid); err != nil {
+		t.Fatalf("valid lists rejected: %v", err)
+	}
+
+	for name, lists := range map[string][]*common.Blocklist{
+		"empty name":     {{Name: ""}},
+		"name with tag":  {{Name: "a:b"}},
+		"duplicate name": {{Name: "a"}, {Name: "a"}},
+		"domain prefix":  {{Name: "a", Domains: []string{"full:example.com"}}},
+		"invalid cidr":   {{Name: "a", Cidrs: []string{"10.0.0.0/33"}}},
+		"invalid geoip":  {{Name: "a", Geoip: []string{"cn ir"}}},
+	} {
+		if err := validateBlocklists(lists); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected invalid argument, got %v", name, err)
+		}
+	}
+}
+
+func TestBlocklists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lists", "blocklists.json")
+	lists := newBlocklists(path)
+	if err := lists.set([]*common.Blocklist{{Name: "ads", Domains: []string{"ads.example.com"}}, {Name: "abuse", Cidrs: []string{"192.0.2.0/24"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	lists.count(&common.AccessLog{Outbound: "blocklist:ads"})
+	lists.count(&common.AccessLog{Outbound: "blocklist:ads"})
+	lists.count(&common.AccessLog{Outbound: "blocklist:unknown"})
+	lists.count(&common.AccessLog{Outbound: "direct"})
+	stats := lists.response().GetStats()
+	if len(stats) != 2 || stats[0].GetHits() != 2 || stats[1].GetHits() != 0 {
+		t.Fatalf("unexpected hits %v", stats)
+	}
+
+	// The lists survive a restart, the hits of dropped lists are forgotten
+	if loaded := newBlocklists(path); len(loaded.get()) != 2 || loaded.get()[1].GetCidrs()[0] != "192.0.2.0/24" {
+		t.Fatalf("stored lists were not loaded: %v", loaded.get())
+	}
+	if err := lists.set([]*common.Blocklist{{Name: "abuse"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lists.hits["ads"]; ok {
+		t.Fatal("hits of a dropped list were kept")
+	}
+}
+
+// rejectingBackend is a running backend whose core refuses every blocklist
+type rejectingBackend struct {
+	backend.Backend
+}
+
+func (rejectingBackend) Started() bool { return true }
+
+func (rejectingBackend) SetBlocklists(context.Context, []*common.Blocklist) error {
+	return errors.New("core failed to start")
+}
+
+func TestController_SetBlocklistRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklists.json")
+	c := &Controller{blocklists: newBlocklists(path)}
+	if err := c.SetBlocklist(context.Background(), &common.SetBlocklistRequest{Lists: []*common.Blocklist{{Name: "ads"}}}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.backend = rejectingBackend{}
+	if err = c.SetBlocklist(context.Background(), &common.SetBlocklistRequest{Lists: []*common.Blocklist{{Name: "abuse"}}}); err == nil {
+		t.Fatal("expected the rejected lists to fail")
+	}
+	if lists := c.blocklists.get(); len(lists) != 1 || lists[0].GetName() != "ads" {
+		t.Fatalf("rejected lists were kept: %v", lists)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != string(stored) {
+		t.Fatalf("rejected lists were stored: %s (%v)", data, err)
+	}
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	certCheckInterval = 12 * time.Hour
+	certExpiryWarning = 30 * 24 * time.Hour
+)
+
+// watchCertificate publishes an event while the node certificate is close to or past its expiry,
+// until ctx is done.
+func watchCertificate(ctx context.Context, certFile string) {
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if event := certificateEvent(certFile, time.Now()); event != nil {
+			logger.Warn(event.Message, "file", certFile, "not_after", event.Details["not_after"])
+			events.Publish(event)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// certificateEvent returns the event for a certificate expiring within certExpiryWarning of now, or nil.
+func certificateEvent(certFile string, now time.Time) *common.Event {
+	expiry, err := tools.CertificateExpiry(certFile)
+	if err != nil {
+		logger.Warn("failed to check certificate expiry", "file", certFile, "error", err)
+		return nil
+	}
+	remaining := expiry.Sub(now)
+	if remaining > certExpiryWarning {
+		return nil
+	}
+
+	event := &common.Event{
+		Type:     common.EventType_CERT_EXPIRING,
+		Severity: common.EventSeverity_WARNING,
+		Message:  fmt.Sprintf("node certificate expires in %d day(s)", int(remaining.Hours()/24)),
+		Details:  map[string]string{"file": certFile, "not_after": expiry.UTC().Format(time.RFC3339)},
+	}
+	if remaining <= 0 {
+		event.Type = common.EventType_CERT_EXPIRED
+		event.Severity = common.EventSeverity_CRITICAL
+		event.Message = "node certificate has expired"
+	}
+	return event
+}
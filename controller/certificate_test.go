@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/events"
+)
+
+// writeCert writes a self signed certificate expiring at notAfter and returns its path.
+func writeCert(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ssl_cert.pem")
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCertificateEvent(t *testing.T) {
+	now := time.Now()
+
+	if event := certificateEvent(writeCert(t, now.Add(90*24*time.Hour)), now); event != nil {
+		t.Fatalf("unexpected event for a valid certificate: %v", event)
+	}
+	if event := certificateEvent(writeCert(t, now.Add(10*24*time.Hour)), now); event.GetType() != common.EventType_CERT_EXPIRING {
+		t.Fatalf("expected an expiring event, got %v", event)
+	}
+	if event := certificateEvent(writeCert(t, now.Add(-time.Hour)), now); event.GetType() != common.EventType_CERT_EXPIRED {
+		t.Fatalf("expected an expired event, got %v", event)
+	}
+}
+
+func TestController_StartWatchersCertificate(t *testing.T) {
+	// Subscribed before the watchers start, like the webhook dispatcher
+	ch, release := events.Subscribe(8)
+	defer release()
+
+	c := &Controller{cfg: &config.Config{SslCertFile: writeCert(t, time.Now().Add(-time.Hour))}}
+	c.StartWatchers()
+	defer c.Shutdown()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-ch:
+			if event.GetType() == common.EventType_CERT_EXPIRED {
+				return
+			}
+		case <-deadline:
+			t.Fatal("expired certificate was not reported on start")
+		}
+	}
+}
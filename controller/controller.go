@@ -3,24 +3,41 @@ package controller
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/pasarguard/node/analytics"
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/backend/singbox"
 	"github.com/pasarguard/node/backend/xray"
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/events"
+	nodeLogger "github.com/pasarguard/node/logger"
 	"github.com/pasarguard/node/tools"
+	"github.com/pasarguard/node/tracing"
 )
 
 const NodeVersion = "0.1.3"
 
+var logger = nodeLogger.For(nodeLogger.ComponentController)
+
+var errNoBackend = status.Error(codes.FailedPrecondition, "backend is not running")
+
 type Service interface {
 	Disconnect()
+	Backend() backend.Backend
+	SystemStats() *common.SystemStatsResponse
+	BaseInfoResponse() *common.BaseInfoResponse
+	StartWatchers()
+	Shutdown()
 }
 
 type Controller struct {
@@ -29,18 +46,97 @@ type Controller struct {
 	apiPort     int
 	clientIP    string
 	lastRequest time.Time
+	keepAlive   time.Duration
+	sessions    int
 	stats       *common.SystemStatsResponse
+	ctx         context.Context
 	cancelFunc  context.CancelFunc
-	mu          sync.RWMutex
+	analytics   *analytics.Aggregator
+	history     *configHistory
+	blocklists  *blocklists
+	// running is the config of the backend and users what it was given for each user, to adopt it on a new start
+	running     *common.ConfigVersion
+	users       map[string]string
+	stopRecords context.CancelFunc
+	// stopWatchers ends the watchers of StartWatchers, watchersDone is closed once they returned
+	stopWatchers context.CancelFunc
+	watchersDone chan struct{}
+	mu           sync.RWMutex
 }
 
 func New(cfg *config.Config) *Controller {
-	_, cancel := context.WithCancel(context.Background())
-	return &Controller{
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := &Controller{
 		cfg:        cfg,
 		apiPort:    tools.FindFreePort(),
+		ctx:        ctx,
 		cancelFunc: cancel,
+		history:    newConfigHistory(cfg.ConfigHistoryPath, cfg.ConfigHistorySize),
+		blocklists: newBlocklists(cfg.BlocklistPath),
+	}
+	if cfg.AnalyticsRetention > 0 {
+		var salt []byte
+		if cfg.AnalyticsHash {
+			salt = []byte(cfg.AnalyticsHashSalt)
+			if len(salt) == 0 {
+				salt = []byte(cfg.ApiKey.String())
+			}
+		}
+		c.analytics = analytics.New(time.Duration(cfg.AnalyticsRetention)*time.Hour, salt)
+	}
+	return c
+}
+
+// StartWatchers starts watching the host for the node lifetime, apart from any panel connection. It is called
+// once the event bus has its subscribers, so the events the watchers publish reach them.
+func (c *Controller) StartWatchers() {
+	var watchers []func(ctx context.Context)
+	// Only a node accepting connections serves its own certificate
+	if c.cfg.PanelAddress == "" {
+		watchers = append(watchers, func(ctx context.Context) { watchCertificate(ctx, c.cfg.SslCertFile) })
+	}
+	if c.cfg.BandwidthQuotaGB > 0 {
+		watchers = append(watchers, func(ctx context.Context) { c.watchBandwidthQuota(ctx, c.cfg.BandwidthQuotaGB) })
+	}
+	if len(watchers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.mu.Lock()
+	c.stopWatchers = cancel
+	c.watchersDone = done
+	c.mu.Unlock()
+
+	var running sync.WaitGroup
+	for _, watch := range watchers {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			watch(ctx)
+		}()
+	}
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+}
+
+// Shutdown stops the watchers of StartWatchers and waits for them to return.
+func (c *Controller) Shutdown() {
+	c.mu.Lock()
+	cancel, done := c.stopWatchers, c.watchersDone
+	c.stopWatchers, c.watchersDone = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
 	}
+	cancel()
+	<-done
 }
 
 func (c *Controller) ApiKey() uuid.UUID {
@@ -52,14 +148,18 @@ func (c *Controller) ApiKey() uuid.UUID {
 func (c *Controller) Connect(ip string, keepAlive uint64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	// An adopted backend is still tracked for the previous client
+	c.cancelFunc()
 	c.lastRequest = time.Now()
 	c.clientIP = ip
+	c.keepAlive = time.Duration(keepAlive) * time.Second
 
 	ctx, cancel := context.WithCancel(context.Background())
+	c.ctx = ctx
 	c.cancelFunc = cancel
 	go c.recordSystemStats(ctx)
 	if keepAlive > 0 {
-		go c.keepAliveTracker(ctx, time.Duration(keepAlive)*time.Second)
+		go c.keepAliveTracker(ctx, c.keepAlive)
 	}
 }
 
@@ -68,6 +168,10 @@ func (c *Controller) Disconnect() {
 
 	c.mu.Lock()
 	backend := c.backend
+	if c.stopRecords != nil {
+		c.stopRecords()
+		c.stopRecords = nil
+	}
 	c.mu.Unlock()
 
 	// Shutdown backend outside of lock to avoid deadlock
@@ -80,6 +184,8 @@ func (c *Controller) Disconnect() {
 	defer c.mu.Unlock()
 
 	c.backend = nil
+	c.running = nil
+	c.users = nil
 	c.apiPort = tools.FindFreePort()
 	c.clientIP = ""
 }
@@ -96,10 +202,78 @@ func (c *Controller) NewRequest() {
 	c.lastRequest = time.Now()
 }
 
-func (c *Controller) StartBackend(ctx context.Context, backendType common.BackendType) error {
+// OpenSession hands liveness tracking over to a control session until release is called.
+// The returned context is canceled when the client is disconnected.
+func (c *Controller) OpenSession() (ctx context.Context, keepAlive time.Duration, release func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions++
+
+	var once sync.Once
+	return c.ctx, c.keepAlive, func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.sessions--
+			// Give the client a full keep alive period to fall back to polling
+			c.lastRequest = time.Now()
+		})
+	}
+}
+
+// StartBackend starts the backend of a start request, ctx carries the parsed config and users of detail.
+// A config that starts is added to the config history. One that fails is rolled back to the newest version
+// of the history with the users of the request, the returned rollback reports it.
+func (c *Controller) StartBackend(ctx context.Context, detail *common.Backend) (rollback *common.ConfigRollback, err error) {
+	ctx, span := tracing.Start(ctx, "controller.StartBackend", attribute.String("backend.type", detail.GetType().String()))
+	defer func() { tracing.End(span, err) }()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	startErr := c.startBackend(ctx, detail.GetType())
+	if startErr == nil {
+		c.running = c.history.add(detail)
+		c.users = userHashes(detail.GetUsers())
+		return nil, nil
+	}
+
+	good := c.history.last()
+	if good == nil || (good.GetType() == detail.GetType() && good.GetConfigHash() == backend.ConfigHash([]byte(detail.GetConfig()))) {
+		return nil, startErr
+	}
+
+	logger.Warn("config failed to start, rolling back to the last config that started", "error", startErr, "version", good.GetId())
+	rollbackCtx, err := withConfig(ctx, &common.Backend{
+		Type:            good.GetType(),
+		Config:          good.GetConfig(),
+		Users:           detail.GetUsers(),
+		ExcludeInbounds: good.GetExcludeInbounds(),
+	})
+	if err == nil {
+		err = c.startBackend(rollbackCtx, good.GetType())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w, rollback to config version %d failed: %v", startErr, good.GetId(), err)
+	}
+	c.running = good
+	c.users = userHashes(detail.GetUsers())
+
+	events.Publish(&common.Event{
+		Type:     common.EventType_CONFIG_ROLLBACK,
+		Severity: common.EventSeverity_CRITICAL,
+		Message:  fmt.Sprintf("config failed to start, rolled back to version %d", good.GetId()),
+		Details: map[string]string{
+			"error":       startErr.Error(),
+			"version":     strconv.FormatUint(good.GetId(), 10),
+			"config_hash": good.GetConfigHash(),
+		},
+	})
+	return &common.ConfigRollback{Error: startErr.Error(), Version: good.GetId(), ConfigHash: good.GetConfigHash()}, nil
+}
+
+func (c *Controller) startBackend(ctx context.Context, backendType common.BackendType) error {
+	ctx = context.WithValue(ctx, backend.BlocklistsKey{}, c.blocklists.get())
 	switch backendType {
 	case common.BackendType_XRAY:
 		newBackend, err := xray.NewXray(ctx, c.apiPort, c.cfg)
@@ -117,9 +291,61 @@ func (c *Controller) StartBackend(ctx context.Context, backendType common.Backen
 		return errors.New("invalid backend type")
 	}
 
+	recordsCtx, cancel := context.WithCancel(context.Background())
+	c.stopRecords = cancel
+	if c.cfg.AccessLogJSONLPath != "" {
+		go writeAccessLogs(recordsCtx, c.cfg.AccessLogJSONLPath, c.backend.Logs())
+	}
+	if c.analytics != nil {
+		go c.analytics.Consume(recordsCtx, c.backend.Logs())
+	}
+	go c.blocklists.consume(recordsCtx, c.backend.Logs())
+
 	return nil
 }
 
+// withConfig parses the config of a start request into the context the backends read it from.
+func withConfig(ctx context.Context, detail *common.Backend) (context.Context, error) {
+	switch detail.GetType() {
+	case common.BackendType_XRAY:
+		config, err := xray.NewXRayConfig(detail.GetConfig(), detail.GetExcludeInbounds())
+		if err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, backend.ConfigKey{}, config)
+	case common.BackendType_SING_BOX:
+		config, err := singbox.NewSingBoxConfig(detail.GetConfig(), detail.GetExcludeInbounds())
+		if err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, backend.ConfigKey{}, config)
+	default:
+		return nil, errors.New("invalid backend type")
+	}
+
+	return context.WithValue(ctx, backend.UsersKey{}, detail.GetUsers()), nil
+}
+
+// ConfigHistory lists the configs that started, the newest is the one a failed start rolls back to.
+func (c *Controller) ConfigHistory() *common.ConfigHistoryResponse {
+	return &common.ConfigHistoryResponse{Versions: c.history.list()}
+}
+
+// RestoreRequest turns a restore of a config version into the start request it is handled as.
+func (c *Controller) RestoreRequest(req *common.RestoreConfigRequest) (*common.Backend, error) {
+	version, err := c.history.get(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &common.Backend{
+		Type:            version.GetType(),
+		Config:          version.GetConfig(),
+		Users:           req.GetUsers(),
+		KeepAlive:       req.GetKeepAlive(),
+		ExcludeInbounds: version.GetExcludeInbounds(),
+	}, nil
+}
+
 func (c *Controller) Backend() backend.Backend {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -137,9 +363,14 @@ func (c *Controller) keepAliveTracker(ctx context.Context, keepAlive time.Durati
 		case <-ticker.C:
 			c.mu.RLock()
 			lastRequest := c.lastRequest
+			sessions := c.sessions
 			c.mu.RUnlock()
+			// Open sessions track liveness through their own heartbeats
+			if sessions > 0 {
+				continue
+			}
 			if time.Since(lastRequest) >= keepAlive {
-				log.Println("disconnect automatically due to keep alive timeout")
+				logger.Info("disconnect automatically due to keep alive timeout", "keep_alive", keepAlive)
 				c.Disconnect()
 			}
 		}
@@ -154,7 +385,7 @@ func (c *Controller) recordSystemStats(ctx context.Context) {
 		default:
 			stats, err := tools.GetSystemStats()
 			if err != nil {
-				log.Printf("Failed to get system stats: %v", err)
+				logger.Warn("failed to get system stats", "error", err)
 			} else {
 				c.mu.Lock()
 				c.stats = stats
@@ -183,7 +414,13 @@ func (c *Controller) BaseInfoResponse() *common.BaseInfoResponse {
 	if c.backend != nil {
 		response.Started = c.backend.Started()
 		response.CoreVersion = c.backend.Version()
+		response.RestartCount, response.BreakerState = c.backend.Restarts()
 	}
 
 	return response
 }
+
+// CoreCrashes returns the recorded exits of core processes, they outlive the backend that started them.
+func (c *Controller) CoreCrashes() *common.CoreCrashesResponse {
+	return &common.CoreCrashesResponse{Exits: backend.CoreExits()}
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/tools"
+)
+
+// configHistory keeps the configs that started, newest last. With a size each version is a file in dir
+// so the last config that started survives node restarts, without one only that config is kept in memory.
+type configHistory struct {
+	dir      string
+	size     int
+	versions []*common.ConfigVersion
+	nextID   uint64
+	mu       sync.Mutex
+}
+
+func newConfigHistory(dir string, size int) *configHistory {
+	h := &configHistory{dir: dir, size: size, nextID: 1}
+	if size <= 0 {
+		h.dir = ""
+		return h
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		logger.Error("failed to create config history, it is kept in memory", "error", err)
+		h.dir = ""
+		return h
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		logger.Error("failed to load config history", "error", err)
+		return h
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			logger.Error("failed to load config history", "error", err)
+			continue
+		}
+		version := &common.ConfigVersion{}
+		if err = protojson.Unmarshal(data, version); err != nil || version.GetId() == 0 {
+			logger.Warn("dropping invalid config history entry", "file", filepath.Base(file))
+			_ = os.Remove(file)
+			continue
+		}
+		h.versions = append(h.versions, version)
+		h.nextID = max(h.nextID, version.GetId()+1)
+	}
+	slices.SortFunc(h.versions, func(a, b *common.ConfigVersion) int {
+		return cmp.Compare(a.GetId(), b.GetId())
+	})
+	h.prune()
+	return h
+}
+
+func (h *configHistory) path(id uint64) string {
+	return filepath.Join(h.dir, strconv.FormatUint(id, 10)+".json")
+}
+
+// add records a config that started as the newest version. Starting a config again moves it to the end.
+func (h *configHistory) add(detail *common.Backend) *common.ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	version := &common.ConfigVersion{
+		Id:              h.nextID,
+		Type:            detail.GetType(),
+		ConfigHash:      backend.ConfigHash([]byte(detail.GetConfig())),
+		ExcludeInbounds: detail.GetExcludeInbounds(),
+		StartedAt:       time.Now().UnixMilli(),
+		Config:          detail.GetConfig(),
+	}
+	h.nextID++
+
+	h.versions = slices.DeleteFunc(h.versions, func(v *common.ConfigVersion) bool {
+		if v.GetType() != version.GetType() || v.GetConfigHash() != version.GetConfigHash() ||
+			!slices.Equal(v.GetExcludeInbounds(), version.GetExcludeInbounds()) {
+			return false
+		}
+		h.remove(v)
+		return true
+	})
+	h.versions = append(h.versions, version)
+
+	if h.dir != "" {
+		if err := h.save(version); err != nil {
+			logger.Error("failed to store config history", "error", err)
+		}
+	}
+	h.prune()
+	return version
+}
+
+func (h *configHistory) save(version *common.ConfigVersion) error {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	return tools.WriteFileAtomic(h.path(version.GetId()), data, 0o600)
+}
+
+func (h *configHistory) remove(version *common.ConfigVersion) {
+	if h.dir == "" {
+		return
+	}
+	if err := os.Remove(h.path(version.GetId())); err != nil && !os.IsNotExist(err) {
+		logger.Error("failed to remove config history entry", "error", err)
+	}
+}
+
+// prune forgets the oldest versions over the size, only the newest is kept in memory without one.
+func (h *configHistory) prune() {
+	limit := max(h.size, 1)
+	for len(h.versions) > limit {
+		h.remove(h.versions[0])
+		h.versions = h.versions[1:]
+	}
+}
+
+// last returns the newest version, nil when no config started yet.
+func (h *configHistory) last() *common.ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.versions) == 0 {
+		return nil
+	}
+	return h.versions[len(h.versions)-1]
+}
+
+func (h *configHistory) get(id uint64) (*common.ConfigVersion, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, version := range h.versions {
+		if version.GetId() == id {
+			return version, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "config version %d is not in the history", id)
+}
+
+// list returns the versions without their configs, oldest first.
+func (h *configHistory) list() []*common.ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	versions := make([]*common.ConfigVersion, 0, len(h.versions))
+	for _, version := range h.versions {
+		listed := proto.Clone(version).(*common.ConfigVersion)
+		listed.Config = ""
+		versions = append(versions, listed)
+	}
+	return versions
+}
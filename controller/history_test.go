@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestConfigHistory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	history := newConfigHistory(dir, 2)
+	if history.last() != nil {
+		t.Fatal("new history must be empty")
+	}
+
+	first := history.add(&common.Backend{Type: common.BackendType_XRAY, Config: `{"a":1}`})
+	second := history.add(&common.Backend{Type: common.BackendType_SING_BOX, Config: `{"b":2}`})
+	if history.last().GetId() != second.GetId() {
+		t.Fatalf("last = %v, want version %d", history.last(), second.GetId())
+	}
+
+	// Starting a config again moves it to the end
+	again := history.add(&common.Backend{Type: common.BackendType_XRAY, Config: `{"a":1}`})
+	if again.GetId() == first.GetId() || history.last() != again {
+		t.Fatalf("restarted config was not moved to the end: %v", history.list())
+	}
+	if _, err := history.get(first.GetId()); status.Code(err) != codes.NotFound {
+		t.Fatalf("replaced version must be gone, got %v", err)
+	}
+
+	third := history.add(&common.Backend{Type: common.BackendType_XRAY, Config: `{"c":3}`, ExcludeInbounds: []string{"in"}})
+	versions := history.list()
+	if len(versions) != 2 || versions[0].GetId() != again.GetId() || versions[1].GetId() != third.GetId() {
+		t.Fatalf("history over its size was not pruned: %v", versions)
+	}
+	for _, version := range versions {
+		if version.GetConfig() != "" {
+			t.Errorf("listing must leave the config out: %v", version)
+		}
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("expected a file per version, got %v", files)
+	}
+
+	reloaded := newConfigHistory(dir, 2)
+	last := reloaded.last()
+	if last.GetId() != third.GetId() || last.GetConfig() != `{"c":3}` || len(last.GetExcludeInbounds()) != 1 {
+		t.Fatalf("reloaded history lost the last version: %v", last)
+	}
+	if next := reloaded.add(&common.Backend{Config: `{"d":4}`}); next.GetId() <= third.GetId() {
+		t.Fatalf("ids must keep growing after a reload, got %d", next.GetId())
+	}
+}
+
+func TestConfigHistory_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "7.json"), []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	history := newConfigHistory(dir, 5)
+	if history.last() != nil {
+		t.Fatalf("invalid entry was loaded: %v", history.last())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "7.json")); !os.IsNotExist(err) {
+		t.Fatal("invalid entry must be dropped")
+	}
+}
+
+func TestConfigHistory_MemoryOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	history := newConfigHistory(dir, 0)
+
+	history.add(&common.Backend{Config: `{"a":1}`})
+	second := history.add(&common.Backend{Config: `{"b":2}`})
+	if versions := history.list(); len(versions) != 1 || versions[0].GetId() != second.GetId() {
+		t.Fatalf("only the last config must be kept, got %v", versions)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatal("history without a size must not touch the disk")
+	}
+}
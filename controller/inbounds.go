@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+var errInboundsUnsupported = status.Error(codes.Unimplemented, "the backend can not change inbounds while running")
+
+func (c *Controller) inboundManager() (backend.InboundManager, error) {
+	running := c.Backend()
+	if running == nil {
+		return nil, errNoBackend
+	}
+	manager, ok := running.(backend.InboundManager)
+	if !ok {
+		return nil, errInboundsUnsupported
+	}
+	return manager, nil
+}
+
+// configChanged forgets the config the backend was started with once it was changed while running,
+// a start with that config then restarts the backend instead of adopting it.
+func (c *Controller) configChanged() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = nil
+}
+
+func (c *Controller) ListInbounds(ctx context.Context) (*common.InboundsResponse, error) {
+	manager, err := c.inboundManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListInbounds(ctx)
+}
+
+func (c *Controller) AddInbound(ctx context.Context, req *common.AddInboundRequest) error {
+	manager, err := c.inboundManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.AddInbound(ctx, req.GetConfig()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) RemoveInbound(ctx context.Context, req *common.RemoveInboundRequest) error {
+	manager, err := c.inboundManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.RemoveInbound(ctx, req.GetTag()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+var logLevels = map[common.LogLevel]slog.Level{
+	common.LogLevel_LOG_DEBUG:   slog.LevelDebug,
+	common.LogLevel_LOG_INFO:    slog.LevelInfo,
+	common.LogLevel_LOG_WARNING: slog.LevelWarn,
+	common.LogLevel_LOG_ERROR:   slog.LevelError,
+}
+
+// newLogFilter builds the filter of a log stream request, the error is meant for the client.
+func newLogFilter(req *common.LogRequest) (*nodeLogger.Filter, error) {
+	level, ok := logLevels[req.GetLevel()]
+	if !ok {
+		return nil, fmt.Errorf("unknown log level %d", req.GetLevel())
+	}
+
+	filter := &nodeLogger.Filter{
+		MinLevel: level,
+		Access:   req.GetType() == common.LogType_ACCESS_LOGS,
+		Error:    req.GetType() == common.LogType_ERROR_LOGS,
+		Email:    req.GetEmail(),
+		Inbound:  req.GetInbound(),
+	}
+
+	if req.GetPattern() != "" {
+		pattern, err := regexp.Compile(req.GetPattern())
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		filter.Pattern = pattern
+	}
+	return filter, nil
+}
+
+// SubscribeLogs subscribes to the backend output and returns the buffered lines selected by the request first.
+// Only lines matching the request filters are returned.
+func (c *Controller) SubscribeLogs(req *common.LogRequest) ([]nodeLogger.Line, *nodeLogger.Subscription, error) {
+	filter, err := newLogFilter(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replay := nodeLogger.Replay{Tail: int(req.GetTail())}
+	if req.GetSince() > 0 {
+		replay.Since = time.UnixMilli(req.GetSince())
+	}
+
+	lines, sub := c.Backend().Logs().Subscribe(nodeLogger.SubscriberBuffer, replay, filter)
+	return lines, sub, nil
+}
+
+// LogMessage converts a captured line, dropped is the count of lines the stream missed so far.
+func LogMessage(line nodeLogger.Line, dropped uint64) *common.Log {
+	return &common.Log{
+		Detail:    line.Text,
+		Timestamp: line.Time.UnixMilli(),
+		Dropped:   dropped,
+	}
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/pasarguard/node/common"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+func TestNewLogFilter(t *testing.T) {
+	filter, err := newLogFilter(&common.LogRequest{
+		Level:   common.LogLevel_LOG_WARNING,
+		Type:    common.LogType_ERROR_LOGS,
+		Pattern: `timeout`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		line nodeLogger.Line
+		want bool
+	}{
+		{line: nodeLogger.Line{Text: "[Warning] dial timeout", Level: slog.LevelWarn}, want: true},
+		{line: nodeLogger.Line{Text: "[Info] dial timeout", Level: slog.LevelInfo}, want: false},
+		{line: nodeLogger.Line{Text: "[Error] config failed", Level: slog.LevelError}, want: false},
+		{line: nodeLogger.Line{Text: "accepted timeout.com", Level: slog.LevelWarn, Access: true}, want: false},
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.line); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.line.Text, got, tt.want)
+		}
+	}
+
+	if _, err = newLogFilter(&common.LogRequest{Pattern: `(`}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if _, err = newLogFilter(&common.LogRequest{Level: common.LogLevel(42)}); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
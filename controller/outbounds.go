@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+var errOutboundsUnsupported = status.Error(codes.Unimplemented, "the backend can not change outbounds while running")
+
+func (c *Controller) outboundManager() (backend.OutboundManager, error) {
+	running := c.Backend()
+	if running == nil {
+		return nil, errNoBackend
+	}
+	manager, ok := running.(backend.OutboundManager)
+	if !ok {
+		return nil, errOutboundsUnsupported
+	}
+	return manager, nil
+}
+
+func (c *Controller) ListOutbounds(ctx context.Context) (*common.OutboundsResponse, error) {
+	manager, err := c.outboundManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListOutbounds(ctx)
+}
+
+func (c *Controller) AddOutbound(ctx context.Context, req *common.OutboundRequest) error {
+	manager, err := c.outboundManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.AddOutbound(ctx, req.GetConfig()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) ReplaceOutbound(ctx context.Context, req *common.OutboundRequest) error {
+	manager, err := c.outboundManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.ReplaceOutbound(ctx, req.GetConfig()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) RemoveOutbound(ctx context.Context, req *common.RemoveOutboundRequest) error {
+	manager, err := c.outboundManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.RemoveOutbound(ctx, req.GetTag()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/pasarguard/node/common"
+)
+
+// WatchAccessLogs streams connection records as JSON lines.
+func (s *Service) WatchAccessLogs(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var request common.AccessLogRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	replay, records := s.SubscribeAccessLogs(&request)
+	defer records.Close()
+
+	for _, line := range replay {
+		if err := writeAccessLog(w, line.Record); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case line, ok := <-records.C:
+			if !ok {
+				return
+			}
+
+			if err := writeAccessLog(w, line.Record); err != nil {
+				return
+			}
+
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeAccessLog(w http.ResponseWriter, record *common.AccessLog) error {
+	data, err := protojson.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
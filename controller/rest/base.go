@@ -3,10 +3,11 @@ package rest
 import (
 	"context"
 	"errors"
-	"log"
 	"net"
 	"net/http"
 
+	"google.golang.org/grpc/status"
+
 	"github.com/pasarguard/node/backend"
 	"github.com/pasarguard/node/backend/singbox"
 	"github.com/pasarguard/node/backend/xray"
@@ -17,32 +18,93 @@ func (s *Service) Base(w http.ResponseWriter, _ *http.Request) {
 	common.SendProtoResponse(w, s.BaseInfoResponse())
 }
 
-func (s *Service) Start(w http.ResponseWriter, r *http.Request) {
-	ctx, backendType, keepAlive, err := s.detectBackend(r)
+func (s *Service) GetCoreCrashes(w http.ResponseWriter, _ *http.Request) {
+	common.SendProtoResponse(w, s.CoreCrashes())
+}
+
+func (s *Service) ValidateConfig(w http.ResponseWriter, r *http.Request) {
+	var data common.Backend
+	if err := common.ReadProtoBody(r.Body, &data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := s.Controller.ValidateConfig(r.Context(), &data)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
 	}
 
+	common.SendProtoResponse(w, response)
+}
+
+func (s *Service) Start(w http.ResponseWriter, r *http.Request) {
+	var data common.Backend
+	if err := common.ReadProtoBody(r.Body, &data); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	s.start(w, r, &data)
+}
+
+func (s *Service) start(w http.ResponseWriter, r *http.Request, data *common.Backend) {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
 		http.Error(w, "unknown ip", http.StatusServiceUnavailable)
 		return
 	}
 
+	if s.AdoptBackend(r.Context(), data) {
+		logger.Info("client started the running config, backend adopted without a restart", "client_ip", ip)
+		s.Connect(ip, data.GetKeepAlive())
+		common.SendProtoResponse(w, s.BaseInfoResponse())
+		return
+	}
+
+	ctx, err := s.detectBackend(r.Context(), data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
 	if s.Backend() != nil {
-		log.Println("New connection from ", ip, " core control access was taken away from previous client.")
+		logger.Warn("new connection, core control access was taken away from previous client", "client_ip", ip)
 		s.Disconnect()
 	}
 
-	s.Connect(ip, keepAlive)
+	s.Connect(ip, data.GetKeepAlive())
 
-	if err = s.StartBackend(ctx, backendType); err != nil {
+	rollback, err := s.StartBackend(ctx, data)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
 	}
 
-	common.SendProtoResponse(w, s.BaseInfoResponse())
+	response := s.BaseInfoResponse()
+	response.Rollback = rollback
+	common.SendProtoResponse(w, response)
+}
+
+func (s *Service) GetConfigHistory(w http.ResponseWriter, _ *http.Request) {
+	common.SendProtoResponse(w, s.ConfigHistory())
+}
+
+func (s *Service) RestoreConfig(w http.ResponseWriter, r *http.Request) {
+	var request common.RestoreConfigRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := s.RestoreRequest(&request)
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	s.start(w, r, data)
 }
 
 func (s *Service) Stop(w http.ResponseWriter, _ *http.Request) {
@@ -51,32 +113,25 @@ func (s *Service) Stop(w http.ResponseWriter, _ *http.Request) {
 	common.SendProtoResponse(w, &common.Empty{})
 }
 
-func (s *Service) detectBackend(r *http.Request) (context.Context, common.BackendType, uint64, error) {
-	var data common.Backend
-	var ctx context.Context
-
-	if err := common.ReadProtoBody(r.Body, &data); err != nil {
-		return nil, 0, 0, err
-	}
-
+func (s *Service) detectBackend(ctx context.Context, data *common.Backend) (context.Context, error) {
 	switch data.Type {
 	case common.BackendType_XRAY:
 		config, err := xray.NewXRayConfig(data.GetConfig(), data.GetExcludeInbounds())
 		if err != nil {
-			return nil, 0, 0, err
+			return nil, err
 		}
-		ctx = context.WithValue(r.Context(), backend.ConfigKey{}, config)
+		ctx = context.WithValue(ctx, backend.ConfigKey{}, config)
 	case common.BackendType_SING_BOX:
 		config, err := singbox.NewSingBoxConfig(data.GetConfig(), data.GetExcludeInbounds())
 		if err != nil {
-			return nil, 0, 0, err
+			return nil, err
 		}
-		ctx = context.WithValue(r.Context(), backend.ConfigKey{}, config)
+		ctx = context.WithValue(ctx, backend.ConfigKey{}, config)
 	default:
-		return ctx, data.GetType(), data.GetKeepAlive(), errors.New("invalid backend type")
+		return nil, errors.New("invalid backend type")
 	}
 
 	ctx = context.WithValue(ctx, backend.UsersKey{}, data.GetUsers())
 
-	return ctx, data.GetType(), data.GetKeepAlive(), nil
+	return ctx, nil
 }
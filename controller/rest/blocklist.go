@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) SetBlocklist(w http.ResponseWriter, r *http.Request) {
+	var request common.SetBlocklistRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.SetBlocklist(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) GetBlocklist(w http.ResponseWriter, _ *http.Request) {
+	common.SendProtoResponse(w, s.Blocklist())
+}
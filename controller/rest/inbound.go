@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListInbounds(w http.ResponseWriter, r *http.Request) {
+	inbounds, err := s.Controller.ListInbounds(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, inbounds)
+}
+
+func (s *Service) AddInbound(w http.ResponseWriter, r *http.Request) {
+	var request common.AddInboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.AddInbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveInbound(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveInboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.RemoveInbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
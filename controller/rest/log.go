@@ -3,8 +3,12 @@ package rest
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/pasarguard/node/common"
 )
 
+// GetLogs streams the core output one line at a time. When the client falls behind and lines are dropped,
+// a ": dropped <n> line(s)" line carrying the total so far is written before the next line.
 func (s *Service) GetLogs(w http.ResponseWriter, r *http.Request) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -12,20 +16,46 @@ func (s *Service) GetLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var request common.LogRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	replay, logs, err := s.SubscribeLogs(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer logs.Close()
+
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	logChan := s.Backend().Logs()
+	for _, line := range replay {
+		if _, err := fmt.Fprintf(w, "%s\n", line.Text); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
 
+	var dropped uint64
 	for {
 		select {
-		case log, ok := <-logChan:
+		case line, ok := <-logs.C:
 			if !ok {
 				return
 			}
 
-			_, err := fmt.Fprintf(w, "%s\n", log)
+			if total := logs.Dropped(); total > dropped {
+				dropped = total
+				if _, err := fmt.Fprintf(w, ": dropped %d line(s)\n", dropped); err != nil {
+					return
+				}
+			}
+
+			_, err := fmt.Fprintf(w, "%s\n", line.Text)
 			if err != nil {
 				return
 			}
@@ -1,11 +1,16 @@
 package rest
 
 import (
-	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
-	"log"
-	"net/http"
+
+	"github.com/pasarguard/node/metrics"
 )
 
 func (s *Service) validateApiKey(next http.Handler) http.Handler {
@@ -53,11 +58,29 @@ func LogRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
-		log.Println(fmt.Sprintf("[API] New request from %s, %s, %s", r.RemoteAddr, r.Method, r.URL.Path))
+		logger.Debug("new request", "client_ip", r.RemoteAddr, "method", r.Method, "path", r.URL.Path)
 
+		start := time.Now()
 		next.ServeHTTP(ww, r)
 
-		log.Println(fmt.Sprintf("[API] %s, %s, %s, %d", r.RemoteAddr, r.Method, r.URL.Path, ww.Status()))
+		level := slog.LevelInfo
+		if ww.Status() >= http.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+		logger.Log(r.Context(), level, "request",
+			"client_ip", r.RemoteAddr,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", ww.Status(),
+			"duration", time.Since(start),
+		)
+
+		// Use the route pattern so unknown paths and parameters don't blow up label cardinality
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		metrics.ObserveRequest("rest", r.Method+" "+route, strconv.Itoa(ww.Status()), time.Since(start))
 	})
 }
 
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListOutbounds(w http.ResponseWriter, r *http.Request) {
+	outbounds, err := s.Controller.ListOutbounds(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, outbounds)
+}
+
+func (s *Service) AddOutbound(w http.ResponseWriter, r *http.Request) {
+	var request common.OutboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.AddOutbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) ReplaceOutbound(w http.ResponseWriter, r *http.Request) {
+	var request common.OutboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.ReplaceOutbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveOutbound(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveOutboundRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.RemoveOutbound(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -252,6 +253,33 @@ func TestREST_GetBackendStats(t *testing.T) {
 	fmt.Println(backendStats)
 }
 
+func TestREST_GetDestinationStats(t *testing.T) {
+	request, err := proto.Marshal(&common.DestinationStatsRequest{Window: 3600, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	getStats := func(retention int) *httptest.ResponseRecorder {
+		cfg := config.NewTestConfig(generatedConfigPath, apiKey)
+		cfg.AnalyticsRetention = retention
+		recorder := httptest.NewRecorder()
+		New(cfg).GetDestinationStats(recorder, httptest.NewRequest("GET", "/stats/destinations", bytes.NewReader(request)))
+		return recorder
+	}
+
+	if recorder := getStats(0); recorder.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected analytics to be disabled, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	recorder := getStats(1)
+	var stats common.DestinationStatsResponse
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected destination stats, got %d: %s", recorder.Code, recorder.Body)
+	}
+	if err = proto.Unmarshal(recorder.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to decode destination stats: %v", err)
+	}
+}
+
 func TestREST_SyncUser(t *testing.T) {
 	user := &common.User{
 		Email: "test_user1@example.com",
@@ -293,6 +321,23 @@ func TestREST_GetLogsStream(t *testing.T) {
 	}
 }
 
+func TestREST_WatchEvents(t *testing.T) {
+	reader, err := sharedTestCtx.createAuthenticatedStreamingRequest("GET", "/events")
+	if err != nil {
+		t.Fatalf("Failed to start streaming events: %v", err)
+	}
+	defer reader.Close()
+
+	// The backend started in TestMain must be in the replay buffer
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if scanner.Text() == "event: "+common.EventType_CORE_STARTED.String() {
+			return
+		}
+	}
+	t.Fatalf("core started event was not replayed: %v", scanner.Err())
+}
+
 func TestREST_GetSystemStats(t *testing.T) {
 	var systemStats common.SystemStatsResponse
 	if err := sharedTestCtx.createAuthenticatedRequest("GET", "/stats/system", &common.Empty{}, &systemStats); err != nil {
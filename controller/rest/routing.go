@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListRouting(w http.ResponseWriter, r *http.Request) {
+	routing, err := s.Controller.ListRouting(r.Context())
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, routing)
+}
+
+func (s *Service) AddRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var request common.AddRoutingRuleRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.AddRoutingRule(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveRoutingRuleRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.RemoveRoutingRule(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) AddBalancer(w http.ResponseWriter, r *http.Request) {
+	var request common.AddBalancerRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.AddBalancer(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
+
+func (s *Service) RemoveBalancer(w http.ResponseWriter, r *http.Request) {
+	var request common.RemoveBalancerRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Controller.RemoveBalancer(r.Context(), &request); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, &common.Empty{})
+}
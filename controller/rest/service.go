@@ -4,16 +4,20 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
-	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"github.com/pasarguard/node/config"
 	"github.com/pasarguard/node/controller"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tunnel"
 )
 
+var logger = nodeLogger.For(nodeLogger.ComponentAPI)
+
 func New(cfg *config.Config) *Service {
 	s := &Service{
 		Controller: *controller.New(cfg),
@@ -26,6 +30,11 @@ func (s *Service) setRouter() {
 	router := chi.NewRouter()
 
 	// Api Handlers
+	router.Use(otelhttp.NewMiddleware("rest",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	))
 	router.Use(LogRequest)
 	router.Use(s.validateApiKey)
 	router.Use(s.trackSuccessfulRequest)
@@ -33,12 +42,20 @@ func (s *Service) setRouter() {
 
 	router.Post("/start", s.Start)
 	router.Get("/info", s.Base)
+	router.Get("/crashes", s.GetCoreCrashes)
+	router.Post("/validate", s.ValidateConfig)
+	router.Get("/config/history", s.GetConfigHistory)
+	router.Post("/config/restore", s.RestoreConfig)
+	router.Get("/blocklist", s.GetBlocklist)
+	router.Put("/blocklist", s.SetBlocklist)
+	router.Get("/events", s.WatchEvents)
 
 	router.Group(func(private chi.Router) {
 		private.Use(s.checkBackendMiddleware)
 
 		private.Put("/stop", s.Stop)
 		private.Get("/logs", s.GetLogs)
+		private.Get("/access-logs", s.WatchAccessLogs)
 		// stats api
 		private.Route("/stats", func(statsGroup chi.Router) {
 			statsGroup.Get("/", s.GetStats)
@@ -46,9 +63,22 @@ func (s *Service) setRouter() {
 			statsGroup.Get("/user/online_ip", s.GetUserOnlineIpListStats)
 			statsGroup.Get("/backend", s.GetBackendStats)
 			statsGroup.Get("/system", s.GetSystemStats)
+			statsGroup.Get("/destinations", s.GetDestinationStats)
 		})
 		private.Put("/user/sync", s.SyncUser)
 		private.Put("/users/sync", s.SyncUsers)
+		private.Get("/inbounds", s.ListInbounds)
+		private.Post("/inbounds", s.AddInbound)
+		private.Delete("/inbounds", s.RemoveInbound)
+		private.Get("/outbounds", s.ListOutbounds)
+		private.Post("/outbounds", s.AddOutbound)
+		private.Put("/outbounds", s.ReplaceOutbound)
+		private.Delete("/outbounds", s.RemoveOutbound)
+		private.Get("/routing", s.ListRouting)
+		private.Post("/routing/rules", s.AddRoutingRule)
+		private.Delete("/routing/rules", s.RemoveRoutingRule)
+		private.Post("/routing/balancers", s.AddBalancer)
+		private.Delete("/routing/balancers", s.RemoveBalancer)
 	})
 
 	s.Router = router
@@ -75,13 +105,35 @@ func StartHttpListener(tlsConfig *tls.Config, addr string, cfg *config.Config) (
 	}
 
 	go func() {
-		log.Println("HTTP Server listening on", addr)
-		log.Println("Press Ctrl+C to stop")
+		logger.Info("HTTP server listening, press Ctrl+C to stop", "address", addr)
 		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Printf("HTTP server error: %v", err)
+			logger.Error("HTTP server error", "error", err)
 		}
 	}()
 
 	// Return a shutdown function for HTTP server
 	return httpServer.Shutdown, s, nil
 }
+
+// StartHttpTunnel serves the node api over a connection dialed to the panel instead of a local listener.
+// The tunnel is already protected by TLS, so the HTTP server itself speaks plain HTTP on top of it.
+func StartHttpTunnel(cfg *config.Config) (func(ctx context.Context) error, controller.Service, error) {
+	s := New(cfg)
+
+	httpServer := &http.Server{
+		Handler: s.Router,
+	}
+
+	t, err := tunnel.New(cfg, httpServer.Serve)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("HTTP server connecting to panel, press Ctrl+C to stop", "panel", cfg.PanelAddress)
+	t.Start()
+
+	return func(ctx context.Context) error {
+		t.Close()
+		return httpServer.Shutdown(ctx)
+	}, s, nil
+}
@@ -77,3 +77,20 @@ func (s *Service) GetBackendStats(w http.ResponseWriter, r *http.Request) {
 func (s *Service) GetSystemStats(w http.ResponseWriter, _ *http.Request) {
 	common.SendProtoResponse(w, s.SystemStats())
 }
+
+func (s *Service) GetDestinationStats(w http.ResponseWriter, r *http.Request) {
+	var request common.DestinationStatsRequest
+	if err := common.ReadProtoBody(r.Body, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := s.DestinationStats(&request)
+	if err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
+		return
+	}
+
+	common.SendProtoResponse(w, stats)
+}
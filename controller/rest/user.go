@@ -2,12 +2,14 @@ package rest
 
 import (
 	"io"
-	"log"
 	"net/http"
 
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/tracing"
 )
 
 func (s *Service) SyncUser(w http.ResponseWriter, r *http.Request) {
@@ -29,11 +31,12 @@ func (s *Service) SyncUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Got user: %v", user.GetEmail())
+	logger.Debug("got user", "email", user.GetEmail())
 
-	if err = s.Backend().SyncUser(r.Context(), user); err != nil {
-		log.Printf("Error syncing user: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err = s.Controller.SyncUser(r.Context(), user); err != nil {
+		logger.Error("failed to sync user", "email", user.GetEmail(), "error", err)
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
 		return
 	}
 
@@ -47,21 +50,27 @@ func (s *Service) SyncUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Service) SyncUsers(w http.ResponseWriter, r *http.Request) {
+	_, span := tracing.Start(r.Context(), "rest.decode")
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		tracing.End(span, err)
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 	defer r.Body.Close()
 
 	users := &common.Users{}
-	if err = proto.Unmarshal(body, users); err != nil {
+	err = proto.Unmarshal(body, users)
+	span.SetAttributes(attribute.Int("body.bytes", len(body)))
+	tracing.End(span, err)
+	if err != nil {
 		http.Error(w, "Failed to decode user", http.StatusBadRequest)
 		return
 	}
 
-	if err = s.Backend().SyncUsers(r.Context(), users.GetUsers()); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err = s.Controller.SyncUsers(r.Context(), users.GetUsers()); err != nil {
+		st, _ := status.FromError(err)
+		http.Error(w, st.Message(), common.GrpcCodeToHTTP(st.Code()))
 		return
 	}
 
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+var errRoutingUnsupported = status.Error(codes.Unimplemented, "the backend can not change routing while running")
+
+func (c *Controller) routingManager() (backend.RoutingManager, error) {
+	running := c.Backend()
+	if running == nil {
+		return nil, errNoBackend
+	}
+	manager, ok := running.(backend.RoutingManager)
+	if !ok {
+		return nil, errRoutingUnsupported
+	}
+	return manager, nil
+}
+
+func (c *Controller) ListRouting(ctx context.Context) (*common.RoutingResponse, error) {
+	manager, err := c.routingManager()
+	if err != nil {
+		return nil, err
+	}
+	return manager.ListRouting(ctx)
+}
+
+func (c *Controller) AddRoutingRule(ctx context.Context, req *common.AddRoutingRuleRequest) error {
+	manager, err := c.routingManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.AddRoutingRule(ctx, req.GetConfig(), req.GetFirst()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) RemoveRoutingRule(ctx context.Context, req *common.RemoveRoutingRuleRequest) error {
+	manager, err := c.routingManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.RemoveRoutingRule(ctx, req.GetRuleTag()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) AddBalancer(ctx context.Context, req *common.AddBalancerRequest) error {
+	manager, err := c.routingManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.AddBalancer(ctx, req.GetConfig()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
+
+func (c *Controller) RemoveBalancer(ctx context.Context, req *common.RemoveBalancerRequest) error {
+	manager, err := c.routingManager()
+	if err != nil {
+		return err
+	}
+	if err = manager.RemoveBalancer(ctx, req.GetTag()); err != nil {
+		return err
+	}
+	c.configChanged()
+	return nil
+}
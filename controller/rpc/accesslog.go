@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) WatchAccessLogs(request *common.AccessLogRequest, stream grpc.ServerStreamingServer[common.AccessLog]) error {
+	replay, records := s.SubscribeAccessLogs(request)
+	defer records.Close()
+
+	for _, line := range replay {
+		if err := stream.Send(line.Record); err != nil {
+			return fmt.Errorf("failed to send access log: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case line, ok := <-records.C:
+			if !ok {
+				return errors.New("access log channel closed")
+			}
+
+			if err := stream.Send(line.Record); err != nil {
+				return fmt.Errorf("failed to send access log: %w", err)
+			}
+
+		case <-stream.Context().Done():
+			// Client has disconnected or cancelled the request
+			return nil
+		}
+	}
+}
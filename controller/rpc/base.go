@@ -3,7 +3,6 @@ package rpc
 import (
 	"context"
 	"errors"
-	"log"
 	"net"
 
 	"github.com/pasarguard/node/backend"
@@ -14,11 +13,6 @@ import (
 )
 
 func (s *Service) Start(ctx context.Context, detail *common.Backend) (*common.BaseInfoResponse, error) {
-	ctx, err := s.detectBackend(ctx, detail)
-	if err != nil {
-		return nil, err
-	}
-
 	clientIP := ""
 	if p, ok := peer.FromContext(ctx); ok {
 		// Extract IP address from peer address
@@ -36,18 +30,32 @@ func (s *Service) Start(ctx context.Context, detail *common.Backend) (*common.Ba
 		}
 	}
 
+	if s.AdoptBackend(ctx, detail) {
+		logger.Info("client started the running config, backend adopted without a restart", "client_ip", clientIP)
+		s.Connect(clientIP, detail.GetKeepAlive())
+		return s.BaseInfoResponse(), nil
+	}
+
+	ctx, err := s.detectBackend(ctx, detail)
+	if err != nil {
+		return nil, err
+	}
+
 	if s.Backend() != nil {
-		log.Println("New connection from ", clientIP, " core control access was taken away from previous client.")
+		logger.Warn("new connection, core control access was taken away from previous client", "client_ip", clientIP)
 		s.Disconnect()
 	}
 
-	if err = s.StartBackend(ctx, detail.GetType()); err != nil {
+	rollback, err := s.StartBackend(ctx, detail)
+	if err != nil {
 		return nil, err
 	}
 
 	s.Connect(clientIP, detail.GetKeepAlive())
 
-	return s.BaseInfoResponse(), nil
+	response := s.BaseInfoResponse()
+	response.Rollback = rollback
+	return response, nil
 }
 
 func (s *Service) Stop(_ context.Context, _ *common.Empty) (*common.Empty, error) {
@@ -81,3 +89,23 @@ func (s *Service) detectBackend(ctx context.Context, detail *common.Backend) (co
 func (s *Service) GetBaseInfo(_ context.Context, _ *common.Empty) (*common.BaseInfoResponse, error) {
 	return s.BaseInfoResponse(), nil
 }
+
+func (s *Service) GetCoreCrashes(_ context.Context, _ *common.Empty) (*common.CoreCrashesResponse, error) {
+	return s.CoreCrashes(), nil
+}
+
+func (s *Service) GetConfigHistory(_ context.Context, _ *common.Empty) (*common.ConfigHistoryResponse, error) {
+	return s.ConfigHistory(), nil
+}
+
+func (s *Service) RestoreConfig(ctx context.Context, req *common.RestoreConfigRequest) (*common.BaseInfoResponse, error) {
+	detail, err := s.RestoreRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.Start(ctx, detail)
+}
+
+func (s *Service) ValidateConfig(ctx context.Context, detail *common.Backend) (*common.ValidateConfigResponse, error) {
+	return s.Controller.ValidateConfig(ctx, detail)
+}
@@ -0,0 +1,18 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) SetBlocklist(ctx context.Context, request *common.SetBlocklistRequest) (*common.Empty, error) {
+	if err := s.Controller.SetBlocklist(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) GetBlocklist(_ context.Context, _ *common.Empty) (*common.BlocklistResponse, error) {
+	return s.Blocklist(), nil
+}
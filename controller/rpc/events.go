@@ -0,0 +1,34 @@
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+)
+
+func (s *Service) WatchEvents(request *common.WatchEventsRequest, stream grpc.ServerStreamingServer[common.Event]) error {
+	replay, eventCh, release := events.SubscribeSince(64, request.GetSinceId())
+	defer release()
+
+	for _, event := range replay {
+		if err := stream.Send(event); err != nil {
+			return fmt.Errorf("failed to send event: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case event := <-eventCh:
+			if err := stream.Send(event); err != nil {
+				return fmt.Errorf("failed to send event: %w", err)
+			}
+
+		case <-stream.Context().Done():
+			// Client has disconnected or cancelled the request
+			return nil
+		}
+	}
+}
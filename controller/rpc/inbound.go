@@ -0,0 +1,25 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListInbounds(ctx context.Context, _ *common.Empty) (*common.InboundsResponse, error) {
+	return s.Controller.ListInbounds(ctx)
+}
+
+func (s *Service) AddInbound(ctx context.Context, request *common.AddInboundRequest) (*common.Empty, error) {
+	if err := s.Controller.AddInbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveInbound(ctx context.Context, request *common.RemoveInboundRequest) (*common.Empty, error) {
+	if err := s.Controller.RemoveInbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
@@ -4,20 +4,34 @@ import (
 	"errors"
 	"fmt"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/controller"
 )
 
-func (s *Service) GetLogs(_ *common.Empty, stream common.NodeService_GetLogsServer) error {
-	logChan := s.Backend().Logs()
+func (s *Service) GetLogs(req *common.LogRequest, stream common.NodeService_GetLogsServer) error {
+	replay, logs, err := s.SubscribeLogs(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer logs.Close()
+
+	for _, line := range replay {
+		if err := stream.Send(controller.LogMessage(line, 0)); err != nil {
+			return fmt.Errorf("failed to send log: %w", err)
+		}
+	}
 
 	for {
 		select {
-		case log, ok := <-logChan:
+		case line, ok := <-logs.C:
 			if !ok {
 				return errors.New("log channel closed")
 			}
 
-			if err := stream.Send(&common.Log{Detail: log}); err != nil {
+			if err := stream.Send(controller.LogMessage(line, logs.Dropped())); err != nil {
 				return fmt.Errorf("failed to send log: %w", err)
 			}
 
@@ -2,9 +2,9 @@ package rpc
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -13,6 +13,8 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/metrics"
 )
 
 func validateApiKey(ctx context.Context, s *Service) error {
@@ -67,7 +69,7 @@ func validateApiKeyStreamMiddleware(s *Service) grpc.StreamServerInterceptor {
 	) error {
 		// Use common session validation logic
 		if err := validateApiKey(ss.Context(), s); err != nil {
-			log.Println("invalid api key stream:", err)
+			logger.Warn("invalid api key on stream", "method", info.FullMethod, "client_ip", peerAddress(ss.Context()), "error", err)
 			return err
 		}
 
@@ -116,22 +118,31 @@ func CheckBackendStreamMiddleware(s *Service) grpc.StreamServerInterceptor {
 	}
 }
 
-func logRequest(ctx context.Context, method string, err error) {
-	// Extract client IP
-	clientIP := "unknown"
+func peerAddress(ctx context.Context) string {
 	if p, ok := peer.FromContext(ctx); ok {
-		clientIP = p.Addr.String()
+		return p.Addr.String()
 	}
+	return "unknown"
+}
 
-	logEntry := fmt.Sprintf("IP: %s, Method: %s,", clientIP, strings.TrimPrefix(method, "/service.NodeService/"))
-
-	// Log based on the response status
+func logRequest(ctx context.Context, method string, err error, start time.Time) {
+	st, _ := status.FromError(err)
+	level := slog.LevelInfo
 	if err != nil {
-		st, _ := status.FromError(err)
-		log.Println(logEntry, "Code:", st.Code())
-	} else {
-		log.Println(logEntry, "Status: Success")
+		level = slog.LevelWarn
 	}
+
+	logger.Log(ctx, level, "request",
+		"client_ip", peerAddress(ctx),
+		"method", strings.TrimPrefix(method, "/service.NodeService/"),
+		"code", st.Code().String(),
+		"duration", time.Since(start),
+	)
+}
+
+func observeRequest(method string, err error, start time.Time) {
+	st, _ := status.FromError(err)
+	metrics.ObserveRequest("grpc", strings.TrimPrefix(method, "/service.NodeService/"), st.Code().String(), time.Since(start))
 }
 
 func LoggingInterceptor(s *Service) grpc.UnaryServerInterceptor {
@@ -141,11 +152,14 @@ func LoggingInterceptor(s *Service) grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
+		start := time.Now()
+
 		// Handle the request
 		resp, err := handler(ctx, req)
 
 		// Log the request
-		logRequest(ctx, info.FullMethod, err)
+		logRequest(ctx, info.FullMethod, err, start)
+		observeRequest(info.FullMethod, err, start)
 
 		// Track successful requests
 		if err == nil {
@@ -163,17 +177,19 @@ func LoggingStreamInterceptor(s *Service) grpc.StreamServerInterceptor {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		clientIP := "unknown"
-		if p, ok := peer.FromContext(ss.Context()); ok {
-			clientIP = p.Addr.String()
-		}
-		log.Printf("Trying To Open Stream Connection, IP: %s, Method: %s,", clientIP, strings.TrimPrefix(info.FullMethod, "/service.NodeService/"))
+		logger.Debug("opening stream",
+			"client_ip", peerAddress(ss.Context()),
+			"method", strings.TrimPrefix(info.FullMethod, "/service.NodeService/"),
+		)
+
+		start := time.Now()
 
 		// Handle the request
 		err := handler(srv, ss)
 
 		// Log the request
-		logRequest(ss.Context(), info.FullMethod, err)
+		logRequest(ss.Context(), info.FullMethod, err, start)
+		observeRequest(info.FullMethod, err, start)
 
 		// Track successful requests
 		if err == nil {
@@ -190,10 +206,25 @@ var backendMethods = map[string]bool{
 	"/service.NodeService/GetUserOnlineIpListStats": true,
 	"/service.NodeService/GetBackendStats":          true,
 	"/service.NodeService/GetSystemStats":           true,
+	"/service.NodeService/GetDestinationStats":      true,
 	"/service.NodeService/Stop":                     true,
 	"/service.NodeService/SyncUser":                 true,
 	"/service.NodeService/SyncUsers":                true,
+	"/service.NodeService/ListInbounds":             true,
+	"/service.NodeService/AddInbound":               true,
+	"/service.NodeService/RemoveInbound":            true,
+	"/service.NodeService/ListOutbounds":            true,
+	"/service.NodeService/AddOutbound":              true,
+	"/service.NodeService/ReplaceOutbound":          true,
+	"/service.NodeService/RemoveOutbound":           true,
+	"/service.NodeService/ListRouting":              true,
+	"/service.NodeService/AddRoutingRule":           true,
+	"/service.NodeService/RemoveRoutingRule":        true,
+	"/service.NodeService/AddBalancer":              true,
+	"/service.NodeService/RemoveBalancer":           true,
 	"/service.NodeService/GetLogs":                  true,
+	"/service.NodeService/WatchAccessLogs":          true,
+	"/service.NodeService/Session":                  true,
 }
 
 func ConditionalMiddleware(s *Service) grpc.UnaryServerInterceptor {
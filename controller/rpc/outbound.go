@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListOutbounds(ctx context.Context, _ *common.Empty) (*common.OutboundsResponse, error) {
+	return s.Controller.ListOutbounds(ctx)
+}
+
+func (s *Service) AddOutbound(ctx context.Context, request *common.OutboundRequest) (*common.Empty, error) {
+	if err := s.Controller.AddOutbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) ReplaceOutbound(ctx context.Context, request *common.OutboundRequest) (*common.Empty, error) {
+	if err := s.Controller.ReplaceOutbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveOutbound(ctx context.Context, request *common.RemoveOutboundRequest) (*common.Empty, error) {
+	if err := s.Controller.RemoveOutbound(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
@@ -0,0 +1,39 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/pasarguard/node/common"
+)
+
+func (s *Service) ListRouting(ctx context.Context, _ *common.Empty) (*common.RoutingResponse, error) {
+	return s.Controller.ListRouting(ctx)
+}
+
+func (s *Service) AddRoutingRule(ctx context.Context, request *common.AddRoutingRuleRequest) (*common.Empty, error) {
+	if err := s.Controller.AddRoutingRule(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveRoutingRule(ctx context.Context, request *common.RemoveRoutingRuleRequest) (*common.Empty, error) {
+	if err := s.Controller.RemoveRoutingRule(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) AddBalancer(ctx context.Context, request *common.AddBalancerRequest) (*common.Empty, error) {
+	if err := s.Controller.AddBalancer(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
+
+func (s *Service) RemoveBalancer(ctx context.Context, request *common.RemoveBalancerRequest) (*common.Empty, error) {
+	if err := s.Controller.RemoveBalancer(ctx, request); err != nil {
+		return nil, err
+	}
+	return &common.Empty{}, nil
+}
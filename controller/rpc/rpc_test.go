@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -303,7 +305,7 @@ func TestGRPC_GetLogsStream(t *testing.T) {
 	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
 	defer cancel()
 
-	logs, _ := sharedTestCtx.client.GetLogs(ctx, &common.Empty{})
+	logs, _ := sharedTestCtx.client.GetLogs(ctx, &common.LogRequest{})
 loop:
 	for {
 		newLog, err := logs.Recv()
@@ -332,6 +334,578 @@ loop:
 	}
 }
 
+func TestGRPC_GetLogsTail(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	// Xray printed its startup lines when TestMain started it, they must be replayed
+	logs, err := sharedTestCtx.client.GetLogs(ctx, &common.LogRequest{Tail: 2})
+	if err != nil {
+		t.Fatalf("Failed to get logs: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		newLog, err := logs.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive replayed log %d: %v", i, err)
+		}
+		if newLog.GetDetail() == "" || newLog.GetTimestamp() == 0 {
+			t.Fatalf("Replayed log is missing its detail or timestamp: %v", newLog)
+		}
+	}
+}
+
+func TestGRPC_GetLogsFilter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	// Xray announces its start as a warning
+	logs, err := sharedTestCtx.client.GetLogs(ctx, &common.LogRequest{
+		Tail:    1,
+		Level:   common.LogLevel_LOG_WARNING,
+		Type:    common.LogType_ERROR_LOGS,
+		Pattern: `Xray \S+ started`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get logs: %v", err)
+	}
+	newLog, err := logs.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive filtered log: %v", err)
+	}
+	if !strings.Contains(newLog.GetDetail(), "started") {
+		t.Fatalf("Unexpected log: %s", newLog.GetDetail())
+	}
+
+	logs, err = sharedTestCtx.client.GetLogs(ctx, &common.LogRequest{Pattern: "("})
+	if err == nil {
+		_, err = logs.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected invalid argument for a bad pattern, got %v", err)
+	}
+}
+
+func TestGRPC_GetCoreCrashes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	// The shared xray is running, whatever exited before it was stopped by the node
+	crashes, err := sharedTestCtx.client.GetCoreCrashes(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get core crashes: %v", err)
+	}
+	for _, exit := range crashes.GetExits() {
+		if exit.GetBackend() == "" || exit.GetExitedAt() == 0 {
+			t.Errorf("Incomplete exit record: %v", exit)
+		}
+	}
+}
+
+func TestGRPC_ValidateConfig(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 30*time.Second)
+	defer cancel()
+
+	configFile, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	before, err := sharedTestCtx.client.GetBaseInfo(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get base info: %v", err)
+	}
+
+	result, err := sharedTestCtx.client.ValidateConfig(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: string(configFile)})
+	if err != nil {
+		t.Fatalf("Failed to validate config: %v", err)
+	}
+	if !result.GetValid() || len(result.GetErrors()) != 0 || result.GetCoreVersion() == "" {
+		t.Fatalf("Expected the test config to be valid, got %v", result)
+	}
+
+	result, err = sharedTestCtx.client.ValidateConfig(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: "{"})
+	if err != nil {
+		t.Fatalf("Failed to validate config: %v", err)
+	}
+	if result.GetValid() || len(result.GetErrors()) != 1 || result.GetErrors()[0].GetSource() != common.ConfigErrorSource_CONFIG_PARSE {
+		t.Fatalf("Expected a parse error, got %v", result)
+	}
+
+	rejected := strings.Replace(string(configFile), `"protocol": "vless"`, `"protocol": "no-such-protocol"`, 1)
+	result, err = sharedTestCtx.client.ValidateConfig(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: rejected})
+	if err != nil {
+		t.Fatalf("Failed to validate config: %v", err)
+	}
+	if result.GetValid() || len(result.GetErrors()) == 0 || result.GetErrors()[0].GetSource() != common.ConfigErrorSource_CONFIG_CORE {
+		t.Fatalf("Expected xray to reject the config, got %v", result)
+	}
+
+	// Validating must leave the running backend alone
+	after, err := sharedTestCtx.client.GetBaseInfo(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get base info: %v", err)
+	}
+	if !after.GetStarted() || after.GetRestartCount() != before.GetRestartCount() {
+		t.Fatalf("Backend changed while validating: before %v, after %v", before, after)
+	}
+}
+
+func TestGRPC_ConfigRollback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 30*time.Second)
+	defer cancel()
+
+	configFile, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+
+	history, err := sharedTestCtx.client.GetConfigHistory(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get config history: %v", err)
+	}
+	versions := history.GetVersions()
+	if len(versions) == 0 || versions[len(versions)-1].GetConfig() != "" {
+		t.Fatalf("Expected the started config in the history without its body, got %v", versions)
+	}
+	good := versions[len(versions)-1]
+
+	// xray rejects the config, the node goes back to the one that started
+	broken := strings.Replace(string(configFile), `"protocol": "vless"`, `"protocol": "no-such-protocol"`, 1)
+	info, err := sharedTestCtx.client.Start(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: broken, KeepAlive: 10})
+	if err != nil {
+		t.Fatalf("Expected the start to be rolled back, got %v", err)
+	}
+	if !info.GetStarted() || info.GetRollback().GetVersion() != good.GetId() || info.GetRollback().GetError() == "" {
+		t.Fatalf("Expected a rollback to version %d, got %v", good.GetId(), info)
+	}
+
+	info, err = sharedTestCtx.client.RestoreConfig(ctx, &common.RestoreConfigRequest{Id: good.GetId(), KeepAlive: 10})
+	if err != nil {
+		t.Fatalf("Failed to restore config: %v", err)
+	}
+	if !info.GetStarted() || info.GetRollback() != nil {
+		t.Fatalf("Expected the restored config to start, got %v", info)
+	}
+
+	_, err = sharedTestCtx.client.RestoreConfig(ctx, &common.RestoreConfigRequest{Id: 1 << 40})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected not found for an unknown version, got %v", err)
+	}
+}
+
+func TestGRPC_AdoptBackend(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 30*time.Second)
+	defer cancel()
+
+	configFile, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	exits := func() int {
+		crashes, err := sharedTestCtx.client.GetCoreCrashes(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to get core crashes: %v", err)
+		}
+		return len(crashes.GetExits())
+	}
+	before := exits()
+
+	// Reconnecting with the running config keeps the core, a new user is synced to it
+	user := &common.User{
+		Email:    "adopted_user@example.com",
+		Inbounds: []string{"VMESS TCP NOTLS"},
+		Proxies:  &common.Proxy{Vmess: &common.Vmess{Id: uuid.New().String()}},
+	}
+	for _, users := range [][]*common.User{nil, {user}} {
+		info, err := sharedTestCtx.client.Start(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: string(configFile), Users: users, KeepAlive: 10})
+		if err != nil {
+			t.Fatalf("Failed to start backend: %v", err)
+		}
+		if !info.GetStarted() {
+			t.Fatalf("Expected the backend to run, got %v", info)
+		}
+	}
+	if after := exits(); after != before {
+		t.Fatalf("Expected the core to keep running, %d exits recorded", after-before)
+	}
+
+	// Another config needs a new core
+	_, err = sharedTestCtx.client.Start(ctx, &common.Backend{Type: common.BackendType_XRAY, Config: string(configFile), ExcludeInbounds: []string{"VMESS TCP NOTLS"}, KeepAlive: 10})
+	if err != nil {
+		t.Fatalf("Failed to start backend: %v", err)
+	}
+	if after := exits(); after != before+1 {
+		t.Fatalf("Expected the core to be restarted for another config, %d exits recorded", after-before)
+	}
+}
+
+func TestGRPC_Inbounds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	const tag = "RUNTIME VMESS"
+	port := tools.FindFreePort()
+
+	// A user assigned to the tag before the inbound exists gets added with it
+	syncUser, err := sharedTestCtx.client.SyncUser(ctx)
+	if err != nil {
+		t.Fatalf("Failed to open user sync: %v", err)
+	}
+	if err = syncUser.Send(&common.User{
+		Email:    "runtime_user@example.com",
+		Inbounds: []string{tag},
+		Proxies:  &common.Proxy{Vmess: &common.Vmess{Id: uuid.New().String()}},
+	}); err != nil {
+		t.Fatalf("Failed to sync user: %v", err)
+	}
+	if _, err = syncUser.CloseAndRecv(); err != nil {
+		t.Fatalf("Failed to sync user: %v", err)
+	}
+
+	inbound := fmt.Sprintf(`{"tag": %q, "listen": "127.0.0.1", "port": %d, "protocol": "vmess", "settings": {"clients": []}}`, tag, port)
+	if _, err = sharedTestCtx.client.AddInbound(ctx, &common.AddInboundRequest{Config: inbound}); err != nil {
+		t.Fatalf("Failed to add inbound: %v", err)
+	}
+	if _, err = sharedTestCtx.client.AddInbound(ctx, &common.AddInboundRequest{Config: inbound}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("Expected already exists for a second inbound with the tag, got %v", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), time.Second)
+	if err != nil {
+		t.Fatalf("Added inbound does not accept connections: %v", err)
+	}
+	conn.Close()
+
+	inbounds, err := sharedTestCtx.client.ListInbounds(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to list inbounds: %v", err)
+	}
+	var found *common.InboundInfo
+	for _, info := range inbounds.GetInbounds() {
+		if info.GetTag() == "API_INBOUND" {
+			t.Errorf("The api inbound must not be listed")
+		}
+		if info.GetTag() == tag {
+			found = info
+		}
+	}
+	if found == nil || found.GetUsers() != 1 || found.GetPort() != fmt.Sprint(port) {
+		t.Fatalf("Expected the added inbound with its user, got %v", inbounds.GetInbounds())
+	}
+
+	if _, err = sharedTestCtx.client.RemoveInbound(ctx, &common.RemoveInboundRequest{Tag: tag}); err != nil {
+		t.Fatalf("Failed to remove inbound: %v", err)
+	}
+	if _, err = sharedTestCtx.client.RemoveInbound(ctx, &common.RemoveInboundRequest{Tag: tag}); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected not found for a removed inbound, got %v", err)
+	}
+}
+
+func TestGRPC_Outbounds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	const tag = "RUNTIME OUTBOUND"
+	outboundProtocol := func() string {
+		outbounds, err := sharedTestCtx.client.ListOutbounds(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to list outbounds: %v", err)
+		}
+		for _, outbound := range outbounds.GetOutbounds() {
+			if outbound.GetTag() == tag {
+				return outbound.GetProtocol()
+			}
+		}
+		return ""
+	}
+
+	if _, err := sharedTestCtx.client.ReplaceOutbound(ctx, &common.OutboundRequest{Config: fmt.Sprintf(`{"tag": %q, "protocol": "freedom"}`, tag)}); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected not found replacing a missing outbound, got %v", err)
+	}
+	if _, err := sharedTestCtx.client.AddOutbound(ctx, &common.OutboundRequest{Config: fmt.Sprintf(`{"tag": %q, "protocol": "freedom"}`, tag)}); err != nil {
+		t.Fatalf("Failed to add outbound: %v", err)
+	}
+	if protocol := outboundProtocol(); protocol != "freedom" {
+		t.Fatalf("Expected the added outbound to be listed, got %q", protocol)
+	}
+
+	if _, err := sharedTestCtx.client.ReplaceOutbound(ctx, &common.OutboundRequest{Config: fmt.Sprintf(`{"tag": %q, "protocol": "blackhole"}`, tag)}); err != nil {
+		t.Fatalf("Failed to replace outbound: %v", err)
+	}
+	if protocol := outboundProtocol(); protocol != "blackhole" {
+		t.Fatalf("Expected the replaced outbound to be listed, got %q", protocol)
+	}
+
+	if _, err := sharedTestCtx.client.RemoveOutbound(ctx, &common.RemoveOutboundRequest{Tag: tag}); err != nil {
+		t.Fatalf("Failed to remove outbound: %v", err)
+	}
+	if protocol := outboundProtocol(); protocol != "" {
+		t.Fatalf("Expected the removed outbound to be gone, got %q", protocol)
+	}
+}
+
+func TestGRPC_Routing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	const balancer = "RUNTIME BALANCER"
+	listRouting := func() *common.RoutingResponse {
+		routing, err := sharedTestCtx.client.ListRouting(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to list routing: %v", err)
+		}
+		return routing
+	}
+
+	if _, err := sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: `{"domain": ["example.com"], "outboundTag": "BLOCK"}`}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected invalid argument adding a rule without a ruleTag, got %v", err)
+	}
+	if _, err := sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: fmt.Sprintf(`{"ruleTag": "balanced", "network": "tcp", "balancerTag": %q}`, balancer)}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected failed precondition adding a rule with a missing balancer, got %v", err)
+	}
+
+	if _, err := sharedTestCtx.client.AddBalancer(ctx, &common.AddBalancerRequest{Config: fmt.Sprintf(`{"tag": %q, "selector": ["direct"]}`, balancer)}); err != nil {
+		t.Fatalf("Failed to add balancer: %v", err)
+	}
+	if _, err := sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: fmt.Sprintf(`{"ruleTag": "balanced", "network": "tcp", "balancerTag": %q}`, balancer)}); err != nil {
+		t.Fatalf("Failed to add balanced rule: %v", err)
+	}
+	if _, err := sharedTestCtx.client.AddRoutingRule(ctx, &common.AddRoutingRuleRequest{Config: `{"ruleTag": "blocked", "domain": ["example.com"], "outboundTag": "BLOCK"}`, First: true}); err != nil {
+		t.Fatalf("Failed to add blocking rule: %v", err)
+	}
+
+	routing := listRouting()
+	rules := routing.GetRules()
+	if len(rules) < 2 || rules[0].GetRuleTag() != "blocked" || rules[len(rules)-1].GetRuleTag() != "balanced" {
+		t.Fatalf("Expected the blocking rule first and the balanced rule last, got %v", rules)
+	}
+	if len(routing.GetBalancers()) != 1 || routing.GetBalancers()[0].GetTag() != balancer {
+		t.Fatalf("Expected the added balancer to be listed, got %v", routing.GetBalancers())
+	}
+
+	// The core api still answers, its rule stayed first
+	if _, err := sharedTestCtx.client.GetBackendStats(ctx, &common.Empty{}); err != nil {
+		t.Fatalf("Failed to get backend stats after changing routing: %v", err)
+	}
+
+	if _, err := sharedTestCtx.client.RemoveBalancer(ctx, &common.RemoveBalancerRequest{Tag: balancer}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected failed precondition removing a balancer in use, got %v", err)
+	}
+	for _, ruleTag := range []string{"blocked", "balanced"} {
+		if _, err := sharedTestCtx.client.RemoveRoutingRule(ctx, &common.RemoveRoutingRuleRequest{RuleTag: ruleTag}); err != nil {
+			t.Fatalf("Failed to remove rule %q: %v", ruleTag, err)
+		}
+	}
+	if _, err := sharedTestCtx.client.RemoveBalancer(ctx, &common.RemoveBalancerRequest{Tag: balancer}); err != nil {
+		t.Fatalf("Failed to remove balancer: %v", err)
+	}
+
+	routing = listRouting()
+	if len(routing.GetRules()) != len(rules)-2 || len(routing.GetBalancers()) != 0 {
+		t.Fatalf("Expected the removed rules and balancer to be gone, got %v", routing)
+	}
+}
+
+func TestGRPC_UserRoutes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	syncUser := func(user *common.User) {
+		stream, err := sharedTestCtx.client.SyncUser(ctx)
+		if err != nil {
+			t.Fatalf("Failed to open user sync: %v", err)
+		}
+		if err = stream.Send(user); err != nil {
+			t.Fatalf("Failed to sync user: %v", err)
+		}
+		if _, err = stream.CloseAndRecv(); err != nil {
+			t.Fatalf("Failed to sync user: %v", err)
+		}
+	}
+	userRule := func() *common.RoutingRule {
+		routing, err := sharedTestCtx.client.ListRouting(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to list routing: %v", err)
+		}
+		for _, rule := range routing.GetRules() {
+			if strings.HasPrefix(rule.GetRuleTag(), "user-route:") {
+				return rule
+			}
+		}
+		return nil
+	}
+
+	user := &common.User{
+		Email:       "routed_user@example.com",
+		Inbounds:    []string{"VMESS TCP NOTLS"},
+		Proxies:     &common.Proxy{Vmess: &common.Vmess{Id: uuid.New().String()}},
+		OutboundTag: "BLOCK",
+	}
+	syncUser(user)
+	rule := userRule()
+	if rule.GetOutboundTag() != "BLOCK" || !strings.Contains(rule.GetConfig(), user.GetEmail()) {
+		t.Fatalf("Expected a rule routing the user through its outbound, got %v", rule)
+	}
+	if _, err := sharedTestCtx.client.RemoveRoutingRule(ctx, &common.RemoveRoutingRuleRequest{RuleTag: rule.GetRuleTag()}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected failed precondition removing a user route, got %v", err)
+	}
+
+	user.OutboundTag = ""
+	syncUser(user)
+	if rule = userRule(); rule != nil {
+		t.Fatalf("Expected the user route to be gone, got %v", rule)
+	}
+
+	// A tag without an outbound or balancer would send the user out through the default outbound
+	user.OutboundTag = "missing"
+	if _, err := sharedTestCtx.client.SyncUsers(ctx, &common.Users{Users: []*common.User{user}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected invalid argument for an unknown outbound tag, got %v", err)
+	}
+}
+
+func TestGRPC_Blocklist(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	blockedRules := func() []string {
+		routing, err := sharedTestCtx.client.ListRouting(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to list routing: %v", err)
+		}
+		var tags []string
+		for _, rule := range routing.GetRules() {
+			if strings.HasPrefix(rule.GetRuleTag(), "blocklist:") {
+				tags = append(tags, rule.GetRuleTag())
+			}
+		}
+		return tags
+	}
+	hasOutbound := func(tag string) bool {
+		outbounds, err := sharedTestCtx.client.ListOutbounds(ctx, &common.Empty{})
+		if err != nil {
+			t.Fatalf("Failed to list outbounds: %v", err)
+		}
+		for _, outbound := range outbounds.GetOutbounds() {
+			if outbound.GetTag() == tag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if _, err := sharedTestCtx.client.SetBlocklist(ctx, &common.SetBlocklistRequest{Lists: []*common.Blocklist{{Name: "bad name"}}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected invalid argument for a bad list name, got %v", err)
+	}
+
+	lists := []*common.Blocklist{{Name: "abuse", Domains: []string{"abuse.example.com"}, Cidrs: []string{"192.0.2.0/24"}}}
+	if _, err := sharedTestCtx.client.SetBlocklist(ctx, &common.SetBlocklistRequest{Lists: lists}); err != nil {
+		t.Fatalf("Failed to set blocklist: %v", err)
+	}
+	if tags := blockedRules(); strings.Join(tags, ",") != "blocklist:abuse:domain,blocklist:abuse:ip" || !hasOutbound("blocklist:abuse") {
+		t.Fatalf("Expected the rules and outbound of the list, got %v", tags)
+	}
+	if _, err := sharedTestCtx.client.RemoveOutbound(ctx, &common.RemoveOutboundRequest{Tag: "blocklist:abuse"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected failed precondition removing a blocklist outbound, got %v", err)
+	}
+
+	blocklist, err := sharedTestCtx.client.GetBlocklist(ctx, &common.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get blocklist: %v", err)
+	}
+	if len(blocklist.GetLists()) != 1 || len(blocklist.GetStats()) != 1 || blocklist.GetStats()[0].GetName() != "abuse" {
+		t.Fatalf("Expected the list with its stats, got %v", blocklist)
+	}
+
+	// The core api still answers with the blocklist rules after its rule
+	if _, err = sharedTestCtx.client.GetBackendStats(ctx, &common.Empty{}); err != nil {
+		t.Fatalf("Failed to get backend stats with blocklists: %v", err)
+	}
+
+	if _, err = sharedTestCtx.client.SetBlocklist(ctx, &common.SetBlocklistRequest{}); err != nil {
+		t.Fatalf("Failed to clear blocklists: %v", err)
+	}
+	if tags := blockedRules(); len(tags) != 0 || hasOutbound("blocklist:abuse") {
+		t.Fatalf("Expected the list to be gone, got %v", tags)
+	}
+}
+
+func TestGRPC_Session(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 10*time.Second)
+	defer cancel()
+
+	session, err := sharedTestCtx.client.Session(ctx)
+	if err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	commands := []*common.SessionCommand{
+		{RequestId: "1", Command: &common.SessionCommand_Heartbeat{Heartbeat: &common.Empty{}}},
+		{RequestId: "2", Command: &common.SessionCommand_GetBackendStats{GetBackendStats: &common.Empty{}}},
+		{RequestId: "3", Command: &common.SessionCommand_SyncUser{SyncUser: &common.User{}}},
+	}
+
+	for _, command := range commands {
+		if err = session.Send(command); err != nil {
+			t.Fatalf("Failed to send session command: %v", err)
+		}
+
+		var message *common.SessionMessage
+		// Skip unsolicited heartbeats and events until the reply arrives
+		for message == nil || message.GetRequestId() == "" {
+			if message, err = session.Recv(); err != nil {
+				t.Fatalf("Failed to receive session message: %v", err)
+			}
+		}
+
+		if message.GetRequestId() != command.GetRequestId() {
+			t.Fatalf("expected reply to %s, got %s", command.GetRequestId(), message.GetRequestId())
+		}
+
+		switch command.GetRequestId() {
+		case "1":
+			if message.GetHeartbeat() == nil {
+				t.Fatalf("expected heartbeat, got %v", message)
+			}
+		case "2":
+			if message.GetBackendStats() == nil {
+				t.Fatalf("expected backend stats, got %v", message)
+			}
+		case "3":
+			if message.GetError() == "" {
+				t.Fatalf("expected error for user without email, got %v", message)
+			}
+		}
+	}
+
+	if err = session.CloseSend(); err != nil {
+		t.Fatalf("Failed to close session: %v", err)
+	}
+	if _, err = session.Recv(); err != io.EOF {
+		t.Fatalf("expected session to end, got %v", err)
+	}
+}
+
+func TestGRPC_WatchEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
+	defer cancel()
+
+	stream, err := sharedTestCtx.client.WatchEvents(ctx, &common.WatchEventsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to watch events: %v", err)
+	}
+
+	// The backend started in TestMain must be in the replay buffer
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("core started event was not replayed: %v", err)
+		}
+		if event.GetType() == common.EventType_CORE_STARTED {
+			return
+		}
+	}
+}
+
 func TestGRPC_GetSystemStats(t *testing.T) {
 	ctx, cancel := context.WithTimeout(sharedTestCtx.ctxWithSession, 5*time.Second)
 	defer cancel()
@@ -7,12 +7,16 @@ import (
 	"github.com/pasarguard/node/common"
 	"github.com/pasarguard/node/config"
 	"github.com/pasarguard/node/controller"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tunnel"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"log"
 	"net"
 )
 
+var logger = nodeLogger.For(nodeLogger.ComponentAPI)
+
 type Service struct {
 	common.UnimplementedNodeServiceServer
 	controller.Controller
@@ -24,35 +28,68 @@ func New(cfg *config.Config) *Service {
 	}
 }
 
-func StartGRPCListener(tlsConfig *tls.Config, addr string, cfg *config.Config) (func(ctx context.Context) error, controller.Service, error) {
-	s := New(cfg)
-
-	creds := credentials.NewTLS(tlsConfig)
-
-	// Create the gRPC server with conditional middleware
-	grpcServer := grpc.NewServer(
-		grpc.Creds(creds),
+func newGRPCServer(s *Service, opt ...grpc.ServerOption) *grpc.Server {
+	opts := append([]grpc.ServerOption{
+		// Continues the trace started by the panel from the request metadata
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.UnaryInterceptor(ConditionalMiddleware(s)),
 		grpc.StreamInterceptor(ConditionalStreamMiddleware(s)),
-	)
+	}, opt...)
+
+	// Create the gRPC server with conditional middleware
+	grpcServer := grpc.NewServer(opts...)
 
 	// Register the service
 	common.RegisterNodeServiceServer(grpcServer, s)
 
+	return grpcServer
+}
+
+func StartGRPCListener(tlsConfig *tls.Config, addr string, cfg *config.Config) (func(ctx context.Context) error, controller.Service, error) {
+	s := New(cfg)
+
+	creds := credentials.NewTLS(tlsConfig)
+	grpcServer := newGRPCServer(s, grpc.Creds(creds))
+
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
 	go func() {
-		log.Println("gRPC Server listening on", addr)
-		log.Println("Press Ctrl+C to stop")
+		logger.Info("gRPC server listening, press Ctrl+C to stop", "address", addr)
 		if err = grpcServer.Serve(listener); err != nil {
-			log.Printf("gRPC server error: %v", err)
+			logger.Error("gRPC server error", "error", err)
 		}
 	}()
 
-	// Create a shutdown function for gRPC server
+	return shutdownFunc(grpcServer), s, nil
+}
+
+// StartGRPCTunnel serves the node api over a connection dialed to the panel instead of a local listener.
+// The tunnel is already protected by TLS, so the gRPC server itself runs without transport credentials.
+func StartGRPCTunnel(cfg *config.Config) (func(ctx context.Context) error, controller.Service, error) {
+	s := New(cfg)
+
+	grpcServer := newGRPCServer(s)
+
+	t, err := tunnel.New(cfg, grpcServer.Serve)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("gRPC server connecting to panel, press Ctrl+C to stop", "panel", cfg.PanelAddress)
+	t.Start()
+
+	shutdown := shutdownFunc(grpcServer)
+	return func(ctx context.Context) error {
+		t.Close()
+		return shutdown(ctx)
+	}, s, nil
+}
+
+// Create a shutdown function for gRPC server
+func shutdownFunc(grpcServer *grpc.Server) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
 		// Graceful stop for gRPC server
 		stopped := make(chan struct{})
@@ -69,5 +106,5 @@ func StartGRPCListener(tlsConfig *tls.Config, addr string, cfg *config.Config) (
 			grpcServer.Stop() // Force stop if graceful stop times out
 			return ctx.Err()
 		}
-	}, s, nil
+	}
 }
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+)
+
+const sessionHeartbeatInterval = 5 * time.Second
+
+// Session keeps a long-lived control channel with the panel.
+// Commands are answered with the same request id, events and heartbeats are sent with an empty one.
+// While the session is open its heartbeats replace the keep alive polling.
+func (s *Service) Session(stream grpc.BidiStreamingServer[common.SessionCommand, common.SessionMessage]) error {
+	ctx, keepAlive, release := s.OpenSession()
+	defer release()
+
+	commands := make(chan *common.SessionCommand)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			command, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case commands <- command:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	eventCh, unsubscribe := events.Subscribe(16)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(sessionHeartbeatInterval)
+	defer ticker.Stop()
+	lastCommand := time.Now()
+
+	for {
+		var message *common.SessionMessage
+
+		select {
+		case <-ctx.Done():
+			return status.Errorf(codes.Aborted, "node disconnected")
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case command := <-commands:
+			lastCommand = time.Now()
+			s.NewRequest()
+			message = s.handleSessionCommand(stream.Context(), command)
+		case event := <-eventCh:
+			message = &common.SessionMessage{Message: &common.SessionMessage_Event{Event: event}}
+		case <-ticker.C:
+			if keepAlive > 0 && time.Since(lastCommand) >= keepAlive {
+				logger.Info("disconnect automatically due to missed session heartbeats", "client_ip", peerAddress(stream.Context()))
+				s.Disconnect()
+				return status.Errorf(codes.DeadlineExceeded, "session heartbeat timeout")
+			}
+			message = &common.SessionMessage{Message: &common.SessionMessage_Heartbeat{Heartbeat: &common.Empty{}}}
+		}
+
+		if err := stream.Send(message); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Service) handleSessionCommand(ctx context.Context, command *common.SessionCommand) *common.SessionMessage {
+	response := &common.SessionMessage{RequestId: command.GetRequestId()}
+
+	if _, ok := command.GetCommand().(*common.SessionCommand_Heartbeat); ok {
+		response.Message = &common.SessionMessage_Heartbeat{Heartbeat: &common.Empty{}}
+		return response
+	}
+
+	if _, ok := command.GetCommand().(*common.SessionCommand_GetBaseInfo); ok {
+		response.Message = &common.SessionMessage_BaseInfo{BaseInfo: s.BaseInfoResponse()}
+		return response
+	}
+
+	if err := checkBackendStatus(s); err != nil {
+		response.Message = &common.SessionMessage_Error{Error: err.Error()}
+		return response
+	}
+
+	var err error
+	switch cmd := command.GetCommand().(type) {
+	case *common.SessionCommand_SyncUser:
+		if cmd.SyncUser.GetEmail() == "" {
+			err = errors.New("email is required")
+			break
+		}
+		if err = s.Controller.SyncUser(ctx, cmd.SyncUser); err == nil {
+			response.Message = &common.SessionMessage_Ack{Ack: &common.Empty{}}
+		}
+	case *common.SessionCommand_SyncUsers:
+		if err = s.Controller.SyncUsers(ctx, cmd.SyncUsers.GetUsers()); err == nil {
+			response.Message = &common.SessionMessage_Ack{Ack: &common.Empty{}}
+		}
+	case *common.SessionCommand_GetStats:
+		var stats *common.StatResponse
+		if stats, err = s.GetStats(ctx, cmd.GetStats); err == nil {
+			response.Message = &common.SessionMessage_Stats{Stats: stats}
+		}
+	case *common.SessionCommand_GetBackendStats:
+		var stats *common.BackendStatsResponse
+		if stats, err = s.GetBackendStats(ctx, cmd.GetBackendStats); err == nil {
+			response.Message = &common.SessionMessage_BackendStats{BackendStats: stats}
+		}
+	case *common.SessionCommand_GetSystemStats:
+		response.Message = &common.SessionMessage_SystemStats{SystemStats: s.SystemStats()}
+	default:
+		err = errors.New("unknown session command")
+	}
+
+	if err != nil {
+		logger.Warn("session command failed", "request_id", command.GetRequestId(), "error", err)
+		response.Message = &common.SessionMessage_Error{Error: err.Error()}
+	}
+	return response
+}
@@ -40,3 +40,7 @@ func (s *Service) GetBackendStats(ctx context.Context, _ *common.Empty) (*common
 func (s *Service) GetSystemStats(_ context.Context, _ *common.Empty) (*common.SystemStatsResponse, error) {
 	return s.SystemStats(), nil
 }
+
+func (s *Service) GetDestinationStats(_ context.Context, request *common.DestinationStatsRequest) (*common.DestinationStatsResponse, error) {
+	return s.DestinationStats(request)
+}
@@ -3,7 +3,6 @@ package rpc
 import (
 	"context"
 	"errors"
-	"log"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -23,17 +22,21 @@ func (s *Service) SyncUser(stream grpc.ClientStreamingServer[common.User, common
 			return errors.New("email is required")
 		}
 
-		log.Printf("Got user: %v", user.GetEmail())
+		logger.Debug("got user", "email", user.GetEmail())
 
-		if err = s.Backend().SyncUser(stream.Context(), user); err != nil {
-			log.Printf("Error syncing user: %v", err)
+		if err = s.Controller.SyncUser(stream.Context(), user); err != nil {
+			logger.Error("failed to sync user", "email", user.GetEmail(), "error", err)
+			// Rejected users keep the code the backend gave them
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
 			return status.Errorf(codes.Internal, "failed to update user: %v", err)
 		}
 	}
 }
 
 func (s *Service) SyncUsers(ctx context.Context, users *common.Users) (*common.Empty, error) {
-	if err := s.Backend().SyncUsers(ctx, users.GetUsers()); err != nil {
+	if err := s.Controller.SyncUsers(ctx, users.GetUsers()); err != nil {
 		return nil, err
 	}
 
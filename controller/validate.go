@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/backend/singbox"
+	"github.com/pasarguard/node/backend/xray"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/tracing"
+)
+
+// ValidateConfig generates the config of a start request and has the core check it, the running backend is not touched.
+// Configs the node or the core reject are reported in the response, err is set when the core could not be run.
+func (c *Controller) ValidateConfig(ctx context.Context, detail *common.Backend) (response *common.ValidateConfigResponse, err error) {
+	ctx, span := tracing.Start(ctx, "controller.ValidateConfig", attribute.String("backend.type", detail.GetType().String()))
+	defer func() { tracing.End(span, err) }()
+
+	ctx, err = withConfig(ctx, detail)
+	if err != nil {
+		return &common.ValidateConfigResponse{
+			Errors: []*common.ConfigError{{Source: common.ConfigErrorSource_CONFIG_PARSE, Message: err.Error()}},
+		}, nil
+	}
+	ctx = context.WithValue(ctx, backend.BlocklistsKey{}, c.blocklists.get())
+
+	if detail.GetType() == common.BackendType_SING_BOX {
+		return singbox.Validate(ctx, c.cfg)
+	}
+	return xray.Validate(ctx, c.cfg)
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"testing"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+)
+
+func TestController_ValidateConfigDuringDisconnect(t *testing.T) {
+	executable, err := exec.LookPath("xray")
+	if err != nil {
+		t.Skip("xray is not available")
+	}
+	c := New(&config.Config{XrayExecutablePath: executable, XrayAssetsPath: t.TempDir()})
+
+	// Disconnect picks a new api port for the next backend while the config is checked
+	var disconnected sync.WaitGroup
+	disconnected.Add(1)
+	go func() {
+		defer disconnected.Done()
+		for range 20 {
+			c.Disconnect()
+		}
+	}()
+
+	result, err := c.ValidateConfig(context.Background(), &common.Backend{
+		Type:   common.BackendType_XRAY,
+		Config: `{"outbounds": [{"protocol": "freedom", "tag": "direct"}]}`,
+	})
+	disconnected.Wait()
+	if err != nil {
+		t.Fatalf("failed to validate config: %v", err)
+	}
+	if !result.GetValid() {
+		t.Fatalf("expected the config to be valid, got %v", result.GetErrors())
+	}
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// defaultReplaySize bounds how many past events a new watcher can catch up on
+const defaultReplaySize = 256
+
+// Bus fans out node events to every subscriber without blocking the publisher
+// and keeps the latest events so late watchers can replay what they missed.
+type Bus struct {
+	subscribers map[chan *common.Event]struct{}
+	history     []*common.Event
+	next        int
+	size        int
+	lastID      uint64
+	mu          sync.RWMutex
+}
+
+func NewBus(replaySize int) *Bus {
+	return &Bus{
+		subscribers: make(map[chan *common.Event]struct{}),
+		history:     make([]*common.Event, 0, replaySize),
+		size:        replaySize,
+	}
+}
+
+// Publish assigns the event an id and timestamp, stores it for replay and delivers it to all subscribers.
+// Subscribers that are not keeping up miss the event.
+func (b *Bus) Publish(event *common.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.Id = b.lastID
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+
+	if b.size > 0 {
+		if len(b.history) < b.size {
+			b.history = append(b.history, event)
+		} else {
+			b.history[b.next] = event
+			b.next = (b.next + 1) % b.size
+		}
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Replay returns buffered events with an id greater than sinceID, oldest first.
+func (b *Bus) Replay(sinceID uint64) []*common.Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.replayLocked(sinceID)
+}
+
+func (b *Bus) replayLocked(sinceID uint64) []*common.Event {
+	var replay []*common.Event
+	for i := range b.history {
+		event := b.history[(b.next+i)%len(b.history)]
+		if event.GetId() > sinceID {
+			replay = append(replay, event)
+		}
+	}
+	return replay
+}
+
+// Subscribe returns a channel receiving published events and a function to release it.
+func (b *Bus) Subscribe(buffer int) (<-chan *common.Event, func()) {
+	_, ch, release := b.subscribe(buffer, nil)
+	return ch, release
+}
+
+// SubscribeSince is like Subscribe but also returns the buffered events newer than sinceID.
+// No event is lost or duplicated between the replay and the channel.
+func (b *Bus) SubscribeSince(buffer int, sinceID uint64) ([]*common.Event, <-chan *common.Event, func()) {
+	return b.subscribe(buffer, &sinceID)
+}
+
+func (b *Bus) subscribe(buffer int, sinceID *uint64) ([]*common.Event, <-chan *common.Event, func()) {
+	ch := make(chan *common.Event, buffer)
+
+	b.mu.Lock()
+	var replay []*common.Event
+	if sinceID != nil {
+		replay = b.replayLocked(*sinceID)
+	}
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return replay, ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+var defaultBus = NewBus(defaultReplaySize)
+
+// Publish sends the event on the default bus.
+func Publish(event *common.Event) {
+	defaultBus.Publish(event)
+}
+
+func Subscribe(buffer int) (<-chan *common.Event, func()) {
+	return defaultBus.Subscribe(buffer)
+}
+
+func SubscribeSince(buffer int, sinceID uint64) ([]*common.Event, <-chan *common.Event, func()) {
+	return defaultBus.SubscribeSince(buffer, sinceID)
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/pasarguard/node/common"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus(4)
+
+	ch, release := bus.Subscribe(1)
+	defer release()
+
+	bus.Publish(&common.Event{Type: common.EventType_CORE_RESTARTED})
+	// The buffer is full, this one must be dropped instead of blocking
+	bus.Publish(&common.Event{Type: common.EventType_CORE_RESTART_FAILED})
+
+	event := <-ch
+	if event.GetType() != common.EventType_CORE_RESTARTED {
+		t.Fatalf("unexpected event type: %s", event.GetType())
+	}
+	if event.GetId() != 1 || event.GetTimestamp() == 0 {
+		t.Fatalf("event id and timestamp must be assigned, got id %d timestamp %d", event.GetId(), event.GetTimestamp())
+	}
+
+	select {
+	case event = <-ch:
+		t.Fatalf("expected no more events, got %s", event.GetType())
+	default:
+	}
+}
+
+func TestBus_Release(t *testing.T) {
+	bus := NewBus(4)
+
+	ch, release := bus.Subscribe(1)
+	release()
+	release()
+
+	bus.Publish(&common.Event{Type: common.EventType_CORE_RESTARTED})
+	if _, ok := <-ch; ok {
+		t.Fatal("released subscription must be closed")
+	}
+}
+
+func TestBus_Replay(t *testing.T) {
+	tests := []struct {
+		name      string
+		published int
+		sinceID   uint64
+		want      []uint64
+	}{
+		{name: "empty", published: 0, sinceID: 0, want: nil},
+		{name: "partial buffer", published: 2, sinceID: 0, want: []uint64{1, 2}},
+		{name: "wrapped buffer", published: 6, sinceID: 0, want: []uint64{3, 4, 5, 6}},
+		{name: "since id", published: 6, sinceID: 4, want: []uint64{5, 6}},
+		{name: "up to date", published: 6, sinceID: 6, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus(4)
+			for i := 0; i < tt.published; i++ {
+				bus.Publish(&common.Event{})
+			}
+
+			replay, _, release := bus.SubscribeSince(1, tt.sinceID)
+			defer release()
+
+			if len(replay) != len(tt.want) {
+				t.Fatalf("expected %d events, got %d", len(tt.want), len(replay))
+			}
+			for i, event := range replay {
+				if event.GetId() != tt.want[i] {
+					t.Fatalf("expected event %d at %d, got %d", tt.want[i], i, event.GetId())
+				}
+			}
+		})
+	}
+}
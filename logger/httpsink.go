@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+func httpFormat(w io.Writer) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})
+}
+
+// httpTransport posts batches as a JSON array of records, any 2xx response accepts the whole batch.
+type httpTransport struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newHTTPTransport(endpoint, token string) (*httpTransport, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log http url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unknown log http scheme %q, expected http or https", u.Scheme)
+	}
+	return &httpTransport{url: endpoint, token: token, client: &http.Client{}}, nil
+}
+
+func (t *httpTransport) send(ctx context.Context, batch []message) (int, error) {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, m := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(m.data)
+	}
+	body.WriteByte(']')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, &body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("log endpoint returned %s", resp.Status)
+	}
+	return len(batch), nil
+}
+
+func (t *httpTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
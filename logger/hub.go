@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+// SubscriberBuffer is how many lines a subscriber can fall behind before it starts dropping them
+const SubscriberBuffer = 256
+
+// Line is a core output line as captured by the node, with what the backend could tell about it.
+type Line struct {
+	Seq  uint64
+	Time time.Time
+	Text string
+
+	Level slog.Level
+	// Access is set for connection logs, Email and Inbound are the user and inbound tag of the connection
+	Access  bool
+	Email   string
+	Inbound string
+	// Record is set on the line completing a connection record, a connection may span several access lines
+	Record *common.AccessLog
+}
+
+// Hub fans out core output to every subscriber without blocking the core
+// and keeps the latest lines so new viewers can start with some history.
+type Hub struct {
+	subscribers map[*Subscription]struct{}
+	history     []Line
+	next        int
+	size        int
+	lastSeq     uint64
+	mu          sync.RWMutex
+}
+
+func NewHub(size int) *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscription]struct{}),
+		history:     make([]Line, 0, size),
+		size:        size,
+	}
+}
+
+// Publish assigns the line a sequence number and time, stores it and delivers it to the subscribers it matches.
+// Subscribers that are not keeping up miss the line, it is counted in their Dropped.
+func (h *Hub) Publish(line Line) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastSeq++
+	line.Seq = h.lastSeq
+	line.Time = time.Now()
+
+	if h.size > 0 {
+		if len(h.history) < h.size {
+			h.history = append(h.history, line)
+		} else {
+			h.history[h.next] = line
+			h.next = (h.next + 1) % h.size
+		}
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(line) {
+			continue
+		}
+		select {
+		case sub.ch <- line:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Replay selects buffered lines for a new subscriber, zero values select nothing.
+type Replay struct {
+	// Tail is the number of latest lines
+	Tail int
+	// Since selects lines captured after this time
+	Since time.Time
+}
+
+func (r Replay) empty() bool {
+	return r.Tail <= 0 && r.Since.IsZero()
+}
+
+func (h *Hub) replayLocked(replay Replay, filter *Filter) []Line {
+	if replay.empty() {
+		return nil
+	}
+
+	var lines []Line
+	for i := range h.history {
+		line := h.history[(h.next+i)%len(h.history)]
+		if line.Time.After(replay.Since) && filter.Match(line) {
+			lines = append(lines, line)
+		}
+	}
+	if replay.Tail > 0 && len(lines) > replay.Tail {
+		lines = lines[len(lines)-replay.Tail:]
+	}
+	return lines
+}
+
+// Subscription receives lines published after it was created.
+type Subscription struct {
+	C <-chan Line
+
+	ch      chan Line
+	filter  *Filter
+	dropped atomic.Uint64
+	hub     *Hub
+	once    sync.Once
+}
+
+// Dropped returns how many lines were missed because the subscriber was not keeping up.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close releases the subscription, it is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		delete(s.hub.subscribers, s)
+		s.hub.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// Subscribe returns the buffered lines selected by replay and a subscription for the following lines,
+// both only hold lines matching filter, a nil filter matches every line.
+// No line is lost or duplicated between the replay and the subscription.
+func (h *Hub) Subscribe(buffer int, replay Replay, filter *Filter) ([]Line, *Subscription) {
+	ch := make(chan Line, buffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	lines := h.replayLocked(replay, filter)
+	h.subscribers[sub] = struct{}{}
+	return lines, sub
+}
+
+// Filter selects lines by their properties, zero values match everything.
+type Filter struct {
+	// MinLevel drops lines below it, access lines are info
+	MinLevel slog.Leveler
+	// Access and Error select only access or only other lines, setting both matches nothing
+	Access  bool
+	Error   bool
+	Email   string
+	Inbound string
+	Pattern *regexp.Regexp
+	// Records selects only lines completing a connection record
+	Records bool
+}
+
+func (f *Filter) Match(line Line) bool {
+	switch {
+	case f == nil:
+		return true
+	case f.MinLevel != nil && line.Level < f.MinLevel.Level():
+		return false
+	case f.Access && !line.Access, f.Error && line.Access:
+		return false
+	case f.Email != "" && line.Email != f.Email:
+		return false
+	case f.Inbound != "" && line.Inbound != f.Inbound:
+		return false
+	case f.Pattern != nil && !f.Pattern.MatchString(line.Text):
+		return false
+	case f.Records && line.Record == nil:
+		return false
+	}
+	return true
+}
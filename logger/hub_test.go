@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/common"
+)
+
+func receive(t *testing.T, sub *Subscription) Line {
+	t.Helper()
+	select {
+	case line := <-sub.C:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a line")
+	}
+	return Line{}
+}
+
+func TestHub_FanOut(t *testing.T) {
+	hub := NewHub(8)
+
+	_, first := hub.Subscribe(4, Replay{}, nil)
+	defer first.Close()
+	_, second := hub.Subscribe(4, Replay{}, nil)
+	defer second.Close()
+
+	hub.Publish(Line{Text: "started"})
+
+	// Both viewers get every line instead of stealing them from each other
+	for _, sub := range []*Subscription{first, second} {
+		if line := receive(t, sub); line.Text != "started" || line.Seq != 1 || line.Time.IsZero() {
+			t.Fatalf("unexpected line: %+v", line)
+		}
+	}
+}
+
+func TestHub_Dropped(t *testing.T) {
+	hub := NewHub(8)
+
+	_, slow := hub.Subscribe(1, Replay{}, nil)
+	defer slow.Close()
+
+	hub.Publish(Line{Text: "one"})
+	hub.Publish(Line{Text: "two"})
+	hub.Publish(Line{Text: "three"})
+
+	if dropped := slow.Dropped(); dropped != 2 {
+		t.Fatalf("expected 2 dropped lines, got %d", dropped)
+	}
+	if line := receive(t, slow); line.Text != "one" {
+		t.Fatalf("expected the first line, got %q", line.Text)
+	}
+
+	// Dropped lines are still in the history
+	replay, sub := hub.Subscribe(1, Replay{Tail: 8}, nil)
+	sub.Close()
+	if len(replay) != 3 {
+		t.Fatalf("expected 3 buffered lines, got %d", len(replay))
+	}
+}
+
+func TestHub_Replay(t *testing.T) {
+	hub := NewHub(3)
+	for _, text := range []string{"a", "b", "c"} {
+		hub.Publish(Line{Text: text})
+	}
+	since := time.Now()
+	time.Sleep(time.Millisecond)
+	hub.Publish(Line{Text: "d"})
+	hub.Publish(Line{Text: "e"})
+
+	tests := []struct {
+		name   string
+		replay Replay
+		want   []string
+	}{
+		{name: "none", replay: Replay{}, want: nil},
+		{name: "tail", replay: Replay{Tail: 2}, want: []string{"d", "e"}},
+		{name: "tail larger than history", replay: Replay{Tail: 10}, want: []string{"c", "d", "e"}},
+		{name: "since", replay: Replay{Since: since}, want: []string{"d", "e"}},
+		{name: "since and tail", replay: Replay{Since: since, Tail: 1}, want: []string{"e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, sub := hub.Subscribe(1, tt.replay, nil)
+			defer sub.Close()
+
+			if len(replay) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, replay)
+			}
+			for i, line := range replay {
+				if line.Text != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, replay)
+				}
+			}
+		})
+	}
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(1)
+
+	_, sub := hub.Subscribe(1, Replay{}, nil)
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Fatal("expected a closed channel")
+	}
+	// Publishing after a subscriber left must not panic on its closed channel
+	hub.Publish(Line{Text: "after close"})
+}
+
+func TestHub_Filter(t *testing.T) {
+	hub := NewHub(8)
+	lines := []Line{
+		{Text: "[Debug] dns resolved", Level: slog.LevelDebug},
+		{Text: "[Warning] timeout", Level: slog.LevelWarn},
+		{Text: "from 1.2.3.4:1 accepted tcp:a.com:443 [IN -> DIRECT] email: 1.alice", Level: slog.LevelInfo, Access: true, Email: "1.alice", Inbound: "IN"},
+		{Text: "from 1.2.3.4:2 accepted tcp:b.com:443 [OTHER -> DIRECT] email: 2.bob", Level: slog.LevelInfo, Access: true, Email: "2.bob", Inbound: "OTHER", Record: &common.AccessLog{Email: "2.bob"}},
+	}
+	for _, line := range lines {
+		hub.Publish(line)
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   []string
+	}{
+		{name: "nil", filter: nil, want: []string{lines[0].Text, lines[1].Text, lines[2].Text, lines[3].Text}},
+		{name: "zero", filter: &Filter{}, want: []string{lines[0].Text, lines[1].Text, lines[2].Text, lines[3].Text}},
+		{name: "level", filter: &Filter{MinLevel: slog.LevelWarn}, want: []string{lines[1].Text}},
+		{name: "access", filter: &Filter{Access: true}, want: []string{lines[2].Text, lines[3].Text}},
+		{name: "error", filter: &Filter{Error: true}, want: []string{lines[0].Text, lines[1].Text}},
+		{name: "email", filter: &Filter{Email: "2.bob"}, want: []string{lines[3].Text}},
+		{name: "inbound", filter: &Filter{Inbound: "IN"}, want: []string{lines[2].Text}},
+		{name: "records", filter: &Filter{Records: true}, want: []string{lines[3].Text}},
+		{name: "pattern", filter: &Filter{Pattern: regexp.MustCompile(`a\.com|timeout`)}, want: []string{lines[1].Text, lines[2].Text}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, sub := hub.Subscribe(1, Replay{Tail: 8}, tt.filter)
+			defer sub.Close()
+
+			if len(replay) != len(tt.want) {
+				t.Fatalf("expected %d lines, got %v", len(tt.want), replay)
+			}
+			for i, line := range replay {
+				if line.Text != tt.want[i] {
+					t.Fatalf("line %d: expected %q, got %q", i, tt.want[i], line.Text)
+				}
+			}
+		})
+	}
+
+	// Live lines are filtered the same way and don't count as dropped
+	_, sub := hub.Subscribe(1, Replay{}, &Filter{Email: "1.alice"})
+	defer sub.Close()
+	hub.Publish(lines[3])
+	hub.Publish(lines[2])
+	if line := receive(t, sub); line.Email != "1.alice" || sub.Dropped() != 0 {
+		t.Fatalf("unexpected line %+v with %d dropped", line, sub.Dropped())
+	}
+}
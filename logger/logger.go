@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
@@ -20,16 +23,22 @@ const (
 
 type Logger struct {
 	outputLogs    bool
-	accessLogFile *os.File
-	errorLogFile  *os.File
+	console       *slog.Logger
+	remote        *slog.Logger
+	accessLogFile *rotatingFile
+	errorLogFile  *rotatingFile
 	accessLogger  *log.Logger
 	errorLogger   *log.Logger
 	mu            sync.RWMutex
 }
 
-func New(outputLogs bool) *Logger {
+// New creates a core logger, core lines always reach the remote sinks and with outputLogs
+// they are also printed through the component logger.
+func New(component string, outputLogs bool) *Logger {
 	return &Logger{
 		outputLogs: outputLogs,
+		console:    For(component),
+		remote:     RemoteFor(component),
 	}
 }
 
@@ -52,29 +61,72 @@ func openLogFile(path string) (*os.File, error) {
 	return f, nil
 }
 
+// SetLogFile opens the access and error log files, rotated with the settings given to Setup.
+// Empty paths disable the file.
 func (l *Logger) SetLogFile(accessPath, errorPath string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	var err error
+	rotation := current.Load().rotation
 
-	if l.accessLogFile, err = openLogFile(accessPath); err != nil {
-		return fmt.Errorf("failed to open access log: %w", err)
-	}
-	if l.accessLogFile != nil {
+	var err error
+	if accessPath != "" {
+		if l.accessLogFile, err = newRotatingFile(accessPath, rotation); err != nil {
+			return fmt.Errorf("failed to open access log: %w", err)
+		}
 		l.accessLogger = log.New(l.accessLogFile, "", 0)
 	}
 
-	if l.errorLogFile, err = openLogFile(errorPath); err != nil {
-		return fmt.Errorf("failed to open error log: %w", err)
-	}
-	if l.errorLogFile != nil {
+	if errorPath != "" {
+		// Both logs may point to the same file, it must be rotated only once
+		if errorPath == accessPath {
+			l.errorLogFile = l.accessLogFile
+		} else if l.errorLogFile, err = newRotatingFile(errorPath, rotation); err != nil {
+			return fmt.Errorf("failed to open error log: %w", err)
+		}
 		l.errorLogger = log.New(l.errorLogFile, "", 0)
 	}
 
+	openLoggers.Store(l, struct{}{})
 	return nil
 }
 
+// Reopen reopens the log files at their paths.
+func (l *Logger) Reopen() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var errs []error
+	if l.accessLogFile != nil {
+		errs = append(errs, l.accessLogFile.Reopen())
+	}
+	if l.errorLogFile != nil && l.errorLogFile != l.accessLogFile {
+		errs = append(errs, l.errorLogFile.Reopen())
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	openLoggers sync.Map
+	// openFiles holds the files opened with OpenFile
+	openFiles sync.Map
+)
+
+// ReopenFiles reopens the files of every open Logger and the ones opened with OpenFile,
+// called on SIGHUP after logrotate moved them.
+func ReopenFiles() error {
+	var errs []error
+	openLoggers.Range(func(key, _ any) bool {
+		errs = append(errs, key.(*Logger).Reopen())
+		return true
+	})
+	openFiles.Range(func(key, _ any) bool {
+		errs = append(errs, key.(*rotatingFile).Reopen())
+		return true
+	})
+	return errors.Join(errs...)
+}
+
 func (l *Logger) Log(level LogLevel, message string) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
@@ -91,20 +143,26 @@ func (l *Logger) Log(level LogLevel, message string) {
 	}
 
 	if l.outputLogs {
-		fmt.Println(message)
+		l.console.Log(context.Background(), CoreLevel(message), message)
+	} else {
+		l.remote.Log(context.Background(), CoreLevel(message), message)
 	}
 }
 
 func (l *Logger) Close() {
+	openLoggers.Delete(l)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	if l.accessLogFile != nil {
 		l.accessLogFile.Close()
-		l.accessLogFile = nil
 	}
-	if l.errorLogFile != nil {
+	if l.errorLogFile != nil && l.errorLogFile != l.accessLogFile {
 		l.errorLogFile.Close()
-		l.errorLogFile = nil
 	}
+	l.accessLogFile = nil
+	l.errorLogFile = nil
+	l.accessLogger = nil
+	l.errorLogger = nil
 }
@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pasarguard/node/config"
+)
+
+const rotatedTimeFormat = "20060102-150405.000"
+
+// Rotation controls when log files are rotated and how many rotated files are kept, zero values disable a limit.
+type Rotation struct {
+	// MaxSize rotates the file before it grows past this many bytes
+	MaxSize int64
+	// Interval rotates the file once a write falls in a new period, periods are aligned to UTC
+	Interval time.Duration
+	// MaxAge removes rotated files older than this
+	MaxAge time.Duration
+	// MaxFiles keeps at most this many rotated files
+	MaxFiles int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// NewRotation reads the rotation settings from the node config.
+func NewRotation(cfg *config.Config) Rotation {
+	return Rotation{
+		MaxSize:  int64(cfg.LogMaxSizeMB) << 20,
+		Interval: time.Duration(cfg.LogRotateHours) * time.Hour,
+		MaxAge:   time.Duration(cfg.LogMaxAgeDays) * 24 * time.Hour,
+		MaxFiles: cfg.LogMaxFiles,
+		Compress: cfg.LogCompress,
+	}
+}
+
+// rotatingFile appends to path and moves it aside to path.<timestamp> when a rotation limit is hit.
+// Compression and retention of rotated files run in the background.
+type rotatingFile struct {
+	path     string
+	rotation Rotation
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	period   time.Time
+	cleanMu  sync.Mutex
+	cleaning sync.WaitGroup
+}
+
+// OpenFile opens path for appending with the rotation given to Setup,
+// ReopenFiles reopens it until it is closed.
+func OpenFile(path string) (io.WriteCloser, error) {
+	f, err := newRotatingFile(path, current.Load().rotation)
+	if err != nil {
+		return nil, err
+	}
+	openFiles.Store(f, struct{}{})
+	return f, nil
+}
+
+func newRotatingFile(path string, rotation Rotation) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, rotation: rotation}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file at path, the period starts at the last write of an existing file
+// so a restart doesn't postpone a rotation that is already due.
+func (f *rotatingFile) open() error {
+	file, err := openLogFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.period = f.periodOf(time.Now())
+	if f.size > 0 {
+		f.period = f.periodOf(info.ModTime())
+	}
+	return nil
+}
+
+func (f *rotatingFile) periodOf(t time.Time) time.Time {
+	if f.rotation.Interval <= 0 {
+		return time.Time{}
+	}
+	return t.UTC().Truncate(f.rotation.Interval)
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && f.due(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if f.size == 0 {
+		f.period = f.periodOf(time.Now())
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) due(next int64) bool {
+	if f.rotation.MaxSize > 0 && f.size+next > f.rotation.MaxSize {
+		return true
+	}
+	return f.rotation.Interval > 0 && !f.periodOf(time.Now()).Equal(f.period)
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	rotated := f.path + "." + time.Now().UTC().Format(rotatedTimeFormat)
+	renameErr := os.Rename(f.path, rotated)
+	if err := f.open(); err != nil {
+		return err
+	}
+	// Keep appending to the current file rather than losing lines
+	if renameErr != nil {
+		logger.Warn("failed to rotate log file", "file", f.path, "error", renameErr)
+		return nil
+	}
+
+	f.cleaning.Add(1)
+	go func() {
+		defer f.cleaning.Done()
+		f.cleanup(rotated)
+	}()
+	return nil
+}
+
+// Reopen closes and reopens the file at the same path, for when an external tool such as logrotate moved it.
+func (f *rotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	_ = f.file.Close()
+	f.file = nil
+	return f.open()
+}
+
+func (f *rotatingFile) Close() error {
+	openFiles.Delete(f)
+
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+	return err
+}
+
+// wait blocks until background cleanups finished.
+func (f *rotatingFile) wait() {
+	f.cleaning.Wait()
+}
+
+// cleanup compresses a freshly rotated file and enforces retention on all rotated files.
+func (f *rotatingFile) cleanup(rotated string) {
+	f.cleanMu.Lock()
+	defer f.cleanMu.Unlock()
+
+	if f.rotation.Compress {
+		if err := compressFile(rotated); err != nil {
+			logger.Warn("failed to compress rotated log file", "file", rotated, "error", err)
+		}
+	}
+
+	files, err := f.rotatedFiles()
+	if err != nil {
+		logger.Warn("failed to list rotated log files", "file", f.path, "error", err)
+		return
+	}
+
+	now := time.Now()
+	for i, file := range files {
+		tooMany := f.rotation.MaxFiles > 0 && i >= f.rotation.MaxFiles
+		tooOld := f.rotation.MaxAge > 0 && now.Sub(file.rotatedAt) > f.rotation.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err = os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			logger.Warn("failed to remove rotated log file", "file", file.path, "error", err)
+		}
+	}
+}
+
+type rotatedFile struct {
+	path      string
+	rotatedAt time.Time
+}
+
+// rotatedFiles lists the rotated files of path, newest first.
+func (f *rotatingFile) rotatedFiles() ([]rotatedFile, error) {
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(f.path) + "."
+	var files []rotatedFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		rotatedAt, err := time.Parse(rotatedTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		files = append(files, rotatedFile{path: filepath.Join(filepath.Dir(f.path), name), rotatedAt: rotatedAt})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].rotatedAt.After(files[j].rotatedAt) })
+	return files, nil
+}
+
+// compressFile replaces path with path.gz.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
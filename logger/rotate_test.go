@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLines(t *testing.T, f *rotatingFile, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if _, err := f.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+}
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := newRotatingFile(path, Rotation{MaxSize: 16, MaxFiles: 2, Compress: true})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer f.Close()
+
+	// Every second line overflows the limit, rotation timestamps must stay unique
+	for _, line := range []string{"line-1", "line-2", "line-3", "line-4", "line-5", "line-6", "line-7", "line-8"} {
+		writeLines(t, f, line)
+		time.Sleep(2 * time.Millisecond)
+	}
+	f.wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read current file: %v", err)
+	}
+	if string(data) != "line-7\nline-8\n" {
+		t.Fatalf("unexpected current file content: %q", data)
+	}
+
+	files, err := f.rotatedFiles()
+	if err != nil {
+		t.Fatalf("failed to list rotated files: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 rotated files to be kept, got %d", len(files))
+	}
+
+	// The newest rotated file holds the lines before the current ones
+	if !strings.HasSuffix(files[0].path, ".gz") {
+		t.Fatalf("rotated file was not compressed: %s", files[0].path)
+	}
+	gz, err := os.Open(files[0].path)
+	if err != nil {
+		t.Fatalf("failed to open rotated file: %v", err)
+	}
+	defer gz.Close()
+	reader, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("invalid gzip file: %v", err)
+	}
+	data, _ = io.ReadAll(reader)
+	if string(data) != "line-5\nline-6\n" {
+		t.Fatalf("unexpected rotated file content: %q", data)
+	}
+}
+
+func TestRotatingFile_Interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "error.log")
+	if err := os.WriteFile(path, []byte("yesterday\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if err := os.Chtimes(path, yesterday, yesterday); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := newRotatingFile(path, Rotation{Interval: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer f.Close()
+
+	writeLines(t, f, "today", "still today")
+	f.wait()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "today\nstill today\n" {
+		t.Fatalf("unexpected current file content: %q", data)
+	}
+	files, _ := f.rotatedFiles()
+	if len(files) != 1 {
+		t.Fatalf("expected 1 rotated file, got %d", len(files))
+	}
+	data, _ = os.ReadFile(files[0].path)
+	if string(data) != "yesterday\n" {
+		t.Fatalf("unexpected rotated file content: %q", data)
+	}
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	old := path + "." + time.Now().Add(-48*time.Hour).UTC().Format(rotatedTimeFormat) + ".gz"
+	unrelated := path + ".bak"
+	for _, name := range []string{old, unrelated} {
+		if err := os.WriteFile(name, []byte("old\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := newRotatingFile(path, Rotation{MaxSize: 4, MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer f.Close()
+
+	writeLines(t, f, "first", "second")
+	f.wait()
+
+	if _, err = os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expired rotated file was not removed: %v", err)
+	}
+	if _, err = os.Stat(unrelated); err != nil {
+		t.Fatalf("unrelated file must be kept: %v", err)
+	}
+	if files, _ := f.rotatedFiles(); len(files) != 1 {
+		t.Fatalf("expected only the fresh rotated file, got %d", len(files))
+	}
+}
+
+func TestLogger_ReopenFiles(t *testing.T) {
+	dir := t.TempDir()
+	accessLog := filepath.Join(dir, "access.log")
+
+	l := New(ComponentXray, false)
+	if err := l.SetLogFile(accessLog, accessLog); err != nil {
+		t.Fatalf("failed to set log files: %v", err)
+	}
+	defer l.Close()
+
+	l.Log(LogInfo, "before logrotate")
+	// logrotate moves the file away and sends SIGHUP
+	if err := os.Rename(accessLog, accessLog+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReopenFiles(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	l.Log(LogError, "after logrotate")
+
+	data, _ := os.ReadFile(accessLog)
+	if string(data) != "after logrotate\n" {
+		t.Fatalf("unexpected content after reopen: %q", data)
+	}
+	data, _ = os.ReadFile(accessLog + ".1")
+	if string(data) != "before logrotate\n" {
+		t.Fatalf("unexpected content in moved file: %q", data)
+	}
+}
+
+func TestOpenFile_ReopenFiles(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "access.jsonl")
+
+	f, err := OpenFile(jsonl)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+
+	_, _ = f.Write([]byte("{\"n\":1}\n"))
+	if err = os.Rename(jsonl, jsonl+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = ReopenFiles(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	_, _ = f.Write([]byte("{\"n\":2}\n"))
+
+	data, _ := os.ReadFile(jsonl)
+	if string(data) != "{\"n\":2}\n" {
+		t.Fatalf("unexpected content after reopen: %q", data)
+	}
+	data, _ = os.ReadFile(jsonl + ".1")
+	if string(data) != "{\"n\":1}\n" {
+		t.Fatalf("unexpected content in moved file: %q", data)
+	}
+
+	// a closed file is not reopened again
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(jsonl); err != nil {
+		t.Fatal(err)
+	}
+	if err = ReopenFiles(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if _, err = os.Stat(jsonl); !os.IsNotExist(err) {
+		t.Fatalf("closed file was reopened: %v", err)
+	}
+}
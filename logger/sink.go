@@ -0,0 +1,291 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pasarguard/node/config"
+)
+
+const (
+	// sinkBuffer is how many records a sink holds while its destination is slow or down, newer ones are dropped
+	sinkBuffer = 10000
+	// sinkBatchSize is the most records sent at once
+	sinkBatchSize = 100
+	// sinkFlushInterval is how long records wait for a batch to fill up
+	sinkFlushInterval = time.Second
+
+	sinkMinBackoff = time.Second
+	sinkMaxBackoff = time.Minute
+)
+
+// message is a formatted record waiting in a sink.
+type message struct {
+	time  time.Time
+	level slog.Level
+	data  []byte
+}
+
+// transport delivers batches to a remote destination, it is only used by the sink goroutine.
+// send returns how many records of the batch were delivered, they are not retried.
+type transport interface {
+	send(ctx context.Context, batch []message) (int, error)
+	close() error
+}
+
+// sink ships records at or above its level to a transport from a background goroutine,
+// batching them and retrying failed batches with backoff so logging never blocks on the network.
+type sink struct {
+	name      string
+	level     slog.Level
+	format    func(w io.Writer) slog.Handler
+	transport transport
+	// report logs sink failures to the console only, a failing sink must not feed itself
+	report *slog.Logger
+
+	queue   chan message
+	dropped atomic.Uint64
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newSink(name string, level slog.Level, format func(io.Writer) slog.Handler, t transport, console slog.Handler) *sink {
+	s := &sink{
+		name:      name,
+		level:     level,
+		format:    format,
+		transport: t,
+		report:    slog.New(console).With("component", ComponentNode, "sink", name),
+		queue:     make(chan message, sinkBuffer),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *sink) enqueue(m message) {
+	select {
+	case s.queue <- m:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (s *sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(sinkFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]message, 0, sinkBatchSize)
+	var backoff time.Duration
+	var reported uint64
+	for {
+		select {
+		case m := <-s.queue:
+			batch = append(batch, m)
+			if len(batch) < sinkBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-s.stop:
+			s.drain(batch)
+			return
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		sent, err := s.transport.send(ctx, batch)
+		cancel()
+		batch = append(batch[:0], batch[sent:]...)
+		if err != nil {
+			if backoff == 0 {
+				s.report.Warn("failed to ship logs, retrying", "error", err)
+			}
+			backoff = min(max(backoff*2, sinkMinBackoff), sinkMaxBackoff)
+			// The batch is kept and new records wait in the queue, dropped once it is full
+			select {
+			case <-time.After(backoff):
+			case <-s.stop:
+				s.drain(batch)
+				return
+			}
+			continue
+		}
+		if backoff > 0 {
+			s.report.Info("log shipping recovered")
+			backoff = 0
+		}
+
+		if total := s.dropped.Load(); total > reported {
+			s.report.Warn("log sink was not keeping up, records dropped", "dropped", total-reported)
+			reported = total
+		}
+	}
+}
+
+// drain makes a last attempt at the pending records on shutdown.
+func (s *sink) drain(batch []message) {
+	for len(s.queue) > 0 {
+		batch = append(batch, <-s.queue)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for len(batch) > 0 {
+		sent, err := s.transport.send(ctx, batch[:min(len(batch), sinkBatchSize)])
+		batch = batch[sent:]
+		if err != nil {
+			s.report.Warn("failed to ship logs on shutdown", "error", err, "lost", len(batch))
+			break
+		}
+	}
+	if err := s.transport.close(); err != nil {
+		s.report.Warn("failed to close log sink", "error", err)
+	}
+}
+
+// Close sends the pending records and releases the transport.
+func (s *sink) Close() {
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+// sinkHandler formats records for a sink, attributes and groups are replayed on a fresh formatter per record.
+type sinkHandler struct {
+	sink *sink
+	wrap []func(slog.Handler) slog.Handler
+}
+
+func (h *sinkHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.sink.level
+}
+
+func (h *sinkHandler) Handle(ctx context.Context, record slog.Record) error {
+	var buf bytes.Buffer
+	handler := h.sink.format(&buf)
+	for _, wrap := range h.wrap {
+		handler = wrap(handler)
+	}
+	if err := handler.Handle(ctx, record); err != nil {
+		return err
+	}
+
+	h.sink.enqueue(message{time: record.Time, level: record.Level, data: bytes.TrimSuffix(buf.Bytes(), []byte("\n"))})
+	return nil
+}
+
+func (h *sinkHandler) with(wrap func(slog.Handler) slog.Handler) *sinkHandler {
+	return &sinkHandler{sink: h.sink, wrap: append(h.wrap[:len(h.wrap):len(h.wrap)], wrap)}
+}
+
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+// multiHandler passes records to every handler that is enabled for them.
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, record.Level) {
+			errs = append(errs, h.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// sinkLevel parses the level of a sink, empty means info.
+func sinkLevel(value string) (slog.Level, error) {
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	return ParseLevel(value)
+}
+
+// newSinks creates the remote sinks enabled in the config.
+func newSinks(cfg *config.Config, console slog.Handler) ([]*sink, error) {
+	var sinks []*sink
+	fail := func(err error) ([]*sink, error) {
+		closeSinks(sinks)
+		return nil, err
+	}
+
+	if cfg.LogSyslogAddress != "" {
+		level, err := sinkLevel(cfg.LogSyslogLevel)
+		if err != nil {
+			return fail(err)
+		}
+		t, err := newSyslogTransport(cfg.LogSyslogAddress, cfg.LogSyslogCAFile)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, newSink("syslog", level, syslogFormat, t, console))
+	}
+
+	if cfg.LogHTTPURL != "" {
+		level, err := sinkLevel(cfg.LogHTTPLevel)
+		if err != nil {
+			return fail(err)
+		}
+		t, err := newHTTPTransport(cfg.LogHTTPURL, cfg.LogHTTPToken)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, newSink("http", level, httpFormat, t, console))
+	}
+
+	return sinks, nil
+}
+
+func closeSinks(sinks []*sink) {
+	var wg sync.WaitGroup
+	for _, s := range sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Close()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/config"
+)
+
+func setupSinks(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	previous := current.Load()
+	if err := setup(cfg, io.Discard); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	t.Cleanup(func() {
+		Shutdown()
+		current.Store(previous)
+	})
+}
+
+func TestSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	setupSinks(t, &config.Config{LogSyslogAddress: "udp://" + conn.LocalAddr().String(), LogSyslogLevel: "warn"})
+
+	xray := For(ComponentXray)
+	xray.Info("not shipped")
+	xray.Warn("core restarted", "pid", 42)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no syslog message: %v", err)
+	}
+	msg := string(buf[:n])
+
+	// daemon facility (3) and warning severity (4)
+	if !strings.HasPrefix(msg, "<28>1 ") {
+		t.Errorf("unexpected header: %q", msg)
+	}
+	for _, want := range []string{" pg-node ", `msg="core restarted"`, "component=xray", "pid=42"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q does not contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "level=") || strings.Contains(msg, "not shipped") {
+		t.Errorf("message %q has the level or an info record", msg)
+	}
+}
+
+func TestSyslogSinkTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	setupSinks(t, &config.Config{LogSyslogAddress: "tcp://" + ln.Addr().String()})
+
+	logger := For(ComponentController)
+	logger.Info("first")
+	logger.Error("second")
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	for _, want := range []struct {
+		prefix, msg string
+	}{{"<30>1 ", "msg=first"}, {"<27>1 ", "msg=second"}} {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			t.Fatalf("failed to read frame length: %v", err)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			t.Fatalf("invalid frame length %q", length)
+		}
+		frame := make([]byte, n)
+		if _, err = io.ReadFull(reader, frame); err != nil {
+			t.Fatalf("failed to read frame: %v", err)
+		}
+		if !strings.HasPrefix(string(frame), want.prefix) || !strings.HasSuffix(string(frame), want.msg+" component=controller") {
+			t.Errorf("unexpected frame %q", frame)
+		}
+	}
+}
+
+func TestHTTPSinkRetries(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var records []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// The first delivery fails, the batch must be sent again
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch []map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("invalid batch: %v", err)
+		}
+		records = append(records, batch...)
+	}))
+	defer server.Close()
+
+	setupSinks(t, &config.Config{LogHTTPURL: server.URL, LogHTTPToken: "secret"})
+
+	For(ComponentAPI).Info("request", "method", "/service.NodeService/Start")
+	For(ComponentAPI).Debug("not shipped")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		done := len(records) > 0
+		mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record after %d attempts, got %v", attempts, records)
+	}
+	if records[0]["msg"] != "request" || records[0]["component"] != ComponentAPI || records[0]["level"] != "INFO" {
+		t.Errorf("unexpected record %v", records[0])
+	}
+}
+
+func TestShutdownFlushesSinks(t *testing.T) {
+	var mu sync.Mutex
+	var body bytes.Buffer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.Copy(&body, r.Body)
+	}))
+	defer server.Close()
+
+	previous := current.Load()
+	defer current.Store(previous)
+	if err := setup(&config.Config{LogHTTPURL: server.URL}, io.Discard); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	For(ComponentNode).Error("failed to start api")
+	Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(body.String(), "failed to start api") {
+		t.Errorf("record was not flushed, got %q", body.String())
+	}
+}
+
+func TestCoreLinesReachSinks(t *testing.T) {
+	var mu sync.Mutex
+	var body bytes.Buffer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.Copy(&body, r.Body)
+	}))
+	defer server.Close()
+
+	previous := current.Load()
+	defer current.Store(previous)
+	var console bytes.Buffer
+	if err := setup(&config.Config{LogHTTPURL: server.URL}, &console); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	// Without debug core lines are shipped but not echoed
+	core := New(ComponentXray, false)
+	core.Log(LogInfo, "[Info] core: Xray 25.1.1 started")
+	Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(body.String(), "Xray 25.1.1 started") || !strings.Contains(body.String(), ComponentXray) {
+		t.Errorf("core line was not shipped, got %q", body.String())
+	}
+	if console.Len() != 0 {
+		t.Errorf("core line was echoed on the console: %q", console.String())
+	}
+}
@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pasarguard/node/config"
+)
+
+// Components with their own level, set with LOG_LEVELS.
+const (
+	ComponentNode       = "node"
+	ComponentAPI        = "api"
+	ComponentController = "controller"
+	ComponentXray       = "xray"
+	ComponentSingBox    = "singbox"
+	ComponentTunnel     = "tunnel"
+	ComponentWebhook    = "webhook"
+	ComponentMetrics    = "metrics"
+)
+
+// state is the active output and levels, swapped atomically so component loggers
+// created before Setup pick up the configuration.
+type state struct {
+	handler slog.Handler
+	// remote only reaches the sinks, it discards records without them
+	remote   slog.Handler
+	level    slog.Level
+	levels   map[string]slog.Level
+	rotation Rotation
+	sinks    []*sink
+}
+
+func (s *state) levelOf(component string) slog.Level {
+	if level, ok := s.levels[component]; ok {
+		return level
+	}
+	return s.level
+}
+
+var current atomic.Pointer[state]
+
+var logger = For(ComponentNode)
+
+func init() {
+	current.Store(&state{handler: slog.NewTextHandler(os.Stderr, nil), remote: slog.DiscardHandler, level: slog.LevelInfo})
+}
+
+// Setup configures the output format and levels of every component logger
+// and routes the standard library logger through them.
+// It also sets the rotation of core log files opened afterward and starts the remote sinks,
+// replacing the ones of a previous Setup.
+func Setup(cfg *config.Config) error {
+	return setup(cfg, os.Stderr)
+}
+
+func setup(cfg *config.Config, w io.Writer) error {
+	level := slog.LevelInfo
+	if cfg.Debug {
+		level = slog.LevelDebug
+	}
+	if cfg.LogLevel != "" {
+		parsed, err := ParseLevel(cfg.LogLevel)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	}
+
+	levels := make(map[string]slog.Level)
+	for _, item := range cfg.LogLevels {
+		component, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("invalid component log level %q, expected component=level", item)
+		}
+		parsed, err := ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		levels[strings.TrimSpace(component)] = parsed
+	}
+
+	// Components filter on their own, the handler lets everything through
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.LogFormat) {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q, expected text or json", cfg.LogFormat)
+	}
+
+	sinks, err := newSinks(cfg, handler)
+	if err != nil {
+		return err
+	}
+	var remote slog.Handler = slog.DiscardHandler
+	if len(sinks) > 0 {
+		handlers := multiHandler{}
+		for _, s := range sinks {
+			handlers = append(handlers, &sinkHandler{sink: s})
+		}
+		remote = handlers
+		handler = append(multiHandler{handler}, handlers...)
+	}
+
+	previous := current.Swap(&state{handler: handler, remote: remote, level: level, levels: levels, rotation: NewRotation(cfg), sinks: sinks})
+	closeSinks(previous.sinks)
+
+	// Dependencies and leftovers using the log package end up in the node component
+	slog.SetDefault(For(ComponentNode))
+	log.SetFlags(0)
+	return nil
+}
+
+// Shutdown sends the records waiting in the remote sinks and stops them,
+// later records only reach the console.
+func Shutdown() {
+	s := current.Load()
+	closeSinks(s.sinks)
+}
+
+// ParseLevel accepts debug, info, warn/warning and error in any case.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", value)
+	}
+}
+
+// For returns the logger of a component, records carry a component field
+// and are filtered by the component level.
+func For(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: component})
+}
+
+// RemoteFor returns the logger of a component that only ships to the remote sinks, for records
+// that must not be echoed on the console.
+func RemoteFor(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: component, remote: true})
+}
+
+// componentHandler resolves the active state on every record.
+// Attributes and groups added with With are replayed on top of it.
+type componentHandler struct {
+	component string
+	remote    bool
+	wrap      []func(slog.Handler) slog.Handler
+	cached    atomic.Pointer[resolved]
+}
+
+type resolved struct {
+	state   *state
+	handler slog.Handler
+}
+
+func (h *componentHandler) resolve() slog.Handler {
+	s := current.Load()
+	if r := h.cached.Load(); r != nil && r.state == s {
+		return r.handler
+	}
+
+	handler := s.handler
+	if h.remote {
+		handler = s.remote
+	}
+	handler = handler.WithAttrs([]slog.Attr{slog.String("component", h.component)})
+	for _, wrap := range h.wrap {
+		handler = wrap(handler)
+	}
+	h.cached.Store(&resolved{state: s, handler: handler})
+	return handler
+}
+
+func (h *componentHandler) Enabled(_ context.Context, level slog.Level) bool {
+	s := current.Load()
+	if h.remote && len(s.sinks) == 0 {
+		return false
+	}
+	return level >= s.levelOf(h.component)
+}
+
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.resolve().Handle(ctx, record)
+}
+
+func (h *componentHandler) with(wrap func(slog.Handler) slog.Handler) *componentHandler {
+	return &componentHandler{
+		component: h.component,
+		remote:    h.remote,
+		wrap:      append(h.wrap[:len(h.wrap):len(h.wrap)], wrap),
+	}
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+// CoreLevel guesses the level of a line printed by a core process from its level tag,
+// lines without one are reported as info.
+func CoreLevel(line string) slog.Level {
+	upper := strings.ToUpper(line)
+	switch {
+	case strings.Contains(upper, "[ERROR]"), strings.Contains(upper, " ERROR "),
+		strings.Contains(upper, "FATAL"), strings.Contains(upper, "PANIC"):
+		return slog.LevelError
+	case strings.Contains(upper, "[WARNING]"), strings.Contains(upper, " WARN "):
+		return slog.LevelWarn
+	case strings.Contains(upper, "[DEBUG]"), strings.Contains(upper, " DEBUG "),
+		strings.Contains(upper, " TRACE "):
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/pasarguard/node/config"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{value: "debug", want: slog.LevelDebug},
+		{value: "INFO", want: slog.LevelInfo},
+		{value: " warning ", want: slog.LevelWarn},
+		{value: "warn", want: slog.LevelWarn},
+		{value: "error", want: slog.LevelError},
+		{value: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLevel(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCoreLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want slog.Level
+	}{
+		{line: "2025/01/01 00:00:00 [Warning] core: Xray 1.8.0 started", want: slog.LevelWarn},
+		{line: "2025/01/01 00:00:00 [Error] failed to read config", want: slog.LevelError},
+		{line: "2025/01/01 00:00:00 [Debug] app/dns: domain resolved", want: slog.LevelDebug},
+		{line: "+0000 2025-01-01 00:00:00 ERROR [1234 0ms] inbound/vless: connection reset", want: slog.LevelError},
+		{line: "+0000 2025-01-01 00:00:00 WARN router: geoip database is outdated", want: slog.LevelWarn},
+		{line: "+0000 2025-01-01 00:00:00 FATAL start service: bind: address already in use", want: slog.LevelError},
+		{line: "from 1.2.3.4:5678 accepted tcp:example.com:443 [inbound >> direct] email: user", want: slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		if got := CoreLevel(tt.line); got != tt.want {
+			t.Errorf("CoreLevel(%q) = %s, want %s", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSetup_ComponentLevels(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+	defer current.Store(current.Load())
+
+	// Created before setup on purpose, package loggers are
+	api := For(ComponentAPI).With("client_ip", "127.0.0.1")
+	xray := For(ComponentXray)
+
+	var buf bytes.Buffer
+	cfg := &config.Config{LogFormat: "json", LogLevel: "warn", LogLevels: []string{"api=debug", "xray=error"}}
+	if err := setup(cfg, &buf); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	api.Debug("api debug")
+	xray.Warn("xray warning")
+	xray.Error("xray error", "pid", 42)
+	For(ComponentController).Info("controller info")
+	For(ComponentController).Warn("controller warning")
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid json line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	want := []struct{ component, msg string }{
+		{ComponentAPI, "api debug"},
+		{ComponentXray, "xray error"},
+		{ComponentController, "controller warning"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %s", len(want), len(records), buf.String())
+	}
+	for i, w := range want {
+		if records[i]["component"] != w.component || records[i]["msg"] != w.msg {
+			t.Errorf("record %d: expected %s %q, got %v", i, w.component, w.msg, records[i])
+		}
+	}
+	if records[0]["client_ip"] != "127.0.0.1" {
+		t.Errorf("attributes added before setup were lost: %v", records[0])
+	}
+	if records[1]["pid"] != float64(42) {
+		t.Errorf("expected pid field, got %v", records[1])
+	}
+}
+
+func TestSetup_Invalid(t *testing.T) {
+	defer current.Store(current.Load())
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{name: "format", cfg: &config.Config{LogFormat: "xml"}},
+		{name: "level", cfg: &config.Config{LogLevel: "loud"}},
+		{name: "component level", cfg: &config.Config{LogLevels: []string{"api"}}},
+		{name: "syslog scheme", cfg: &config.Config{LogSyslogAddress: "ftp://127.0.0.1:514"}},
+		{name: "syslog port", cfg: &config.Config{LogSyslogAddress: "udp://127.0.0.1"}},
+		{name: "syslog level", cfg: &config.Config{LogSyslogAddress: "udp://127.0.0.1:514", LogSyslogLevel: "loud"}},
+		{name: "http scheme", cfg: &config.Config{LogHTTPURL: "ftp://127.0.0.1/logs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := setup(tt.cfg, &bytes.Buffer{}); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	syslogAppName = "pg-node"
+	// syslogFacility is daemon
+	syslogFacility = 3
+)
+
+// syslogFormat leaves time and level out of the message, they are in the syslog header.
+func syslogFormat(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// syslogTransport sends RFC 5424 messages, one datagram each over UDP
+// and octet-counted (RFC 6587) over TCP and TLS.
+type syslogTransport struct {
+	network  string
+	address  string
+	tls      *tls.Config
+	hostname string
+	conn     net.Conn
+}
+
+// newSyslogTransport accepts udp://, tcp:// and tls:// addresses, caFile adds a CA for tls.
+func newSyslogTransport(address, caFile string) (*syslogTransport, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address: %w", err)
+	}
+	if u.Port() == "" {
+		return nil, fmt.Errorf("syslog address %q has no port", address)
+	}
+
+	t := &syslogTransport{address: u.Host, hostname: "-"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		t.hostname = hostname
+	}
+
+	switch u.Scheme {
+	case "udp", "tcp":
+		t.network = u.Scheme
+	case "tls":
+		t.network = "tcp"
+		t.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+		if caFile != "" {
+			if t.tls.RootCAs, err = tools.LoadClientPool(caFile); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown syslog scheme %q, expected udp, tcp or tls", u.Scheme)
+	}
+	return t, nil
+}
+
+func (t *syslogTransport) dial(ctx context.Context) error {
+	if t.conn != nil {
+		return nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, t.network, t.address)
+	if err != nil {
+		return err
+	}
+	if t.tls != nil {
+		tlsConn := tls.Client(conn, t.tls)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return err
+		}
+		conn = tlsConn
+	}
+	t.conn = conn
+	return nil
+}
+
+// frame renders a message with its RFC 5424 header.
+func (t *syslogTransport) frame(m message) []byte {
+	header := fmt.Sprintf("<%d>1 %s %s %s %d - - ",
+		syslogFacility*8+syslogSeverity(m.level),
+		m.time.UTC().Format(time.RFC3339Nano), t.hostname, syslogAppName, os.Getpid())
+	data := append([]byte(header), m.data...)
+	if t.network == "udp" {
+		return data
+	}
+	return append([]byte(strconv.Itoa(len(data))+" "), data...)
+}
+
+func (t *syslogTransport) send(ctx context.Context, batch []message) (int, error) {
+	if err := t.dial(ctx); err != nil {
+		return 0, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = t.conn.SetWriteDeadline(deadline)
+	}
+
+	for i, m := range batch {
+		if _, err := t.conn.Write(t.frame(m)); err != nil {
+			// A stream may be left mid frame, the next batch starts on a new connection
+			_ = t.conn.Close()
+			t.conn = nil
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
+
+func (t *syslogTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
@@ -3,62 +3,142 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/pasarguard/node/backend/xray"
 	"github.com/pasarguard/node/config"
 	"github.com/pasarguard/node/controller"
 	"github.com/pasarguard/node/controller/rest"
 	"github.com/pasarguard/node/controller/rpc"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/metrics"
 	"github.com/pasarguard/node/tools"
+	"github.com/pasarguard/node/tracing"
+	"github.com/pasarguard/node/webhook"
 )
 
+var logger = nodeLogger.For(nodeLogger.ComponentNode)
+
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal(err)
+		fatal("failed to load config", err)
 	}
 
-	addr := fmt.Sprintf("%s:%d", cfg.NodeHost, cfg.ServicePort)
+	if err = nodeLogger.Setup(cfg); err != nil {
+		fatal("failed to setup logger", err)
+	}
 
-	tlsConfig, err := tools.LoadTLSCredentials(cfg.SslCertFile, cfg.SslKeyFile)
-	if err != nil {
-		log.Fatal(err)
+	logger.Info("starting node", "version", controller.NodeVersion, "protocol", cfg.ServiceProtocol)
+
+	if err = xray.SetAssetLocation(cfg.XrayAssetsPath); err != nil {
+		fatal("failed to set xray assets location", err)
 	}
 
-	log.Printf("Starting Node: v%s", controller.NodeVersion)
+	tracingShutdown, err := tracing.Setup(cfg, controller.NodeVersion)
+	if err != nil {
+		fatal("failed to setup tracing", err)
+	}
 
 	var shutdownFunc func(ctx context.Context) error
 	var service controller.Service
 
-	if cfg.ServiceProtocol == "rest" {
-		shutdownFunc, service, err = rest.StartHttpListener(tlsConfig, addr, cfg)
+	if cfg.PanelAddress != "" {
+		// Reverse mode: dial the panel instead of accepting connections
+		if cfg.ServiceProtocol == "rest" {
+			shutdownFunc, service, err = rest.StartHttpTunnel(cfg)
+		} else {
+			shutdownFunc, service, err = rpc.StartGRPCTunnel(cfg)
+		}
 	} else {
-		shutdownFunc, service, err = rpc.StartGRPCListener(tlsConfig, addr, cfg)
+		addr := fmt.Sprintf("%s:%d", cfg.NodeHost, cfg.ServicePort)
+
+		tlsConfig, tlsErr := tools.LoadTLSCredentials(cfg.SslCertFile, cfg.SslKeyFile)
+		if tlsErr != nil {
+			fatal("failed to load TLS credentials", tlsErr)
+		}
+
+		if cfg.ServiceProtocol == "rest" {
+			shutdownFunc, service, err = rest.StartHttpListener(tlsConfig, addr, cfg)
+		} else {
+			shutdownFunc, service, err = rpc.StartGRPCListener(tlsConfig, addr, cfg)
+		}
 	}
 	if err != nil {
-		log.Fatal(err)
+		fatal("failed to start api", err)
 	}
 
 	defer service.Disconnect()
 
+	if cfg.MetricsPort > 0 {
+		metricsShutdown, metricsErr := metrics.StartListener(fmt.Sprintf("%s:%d", cfg.NodeHost, cfg.MetricsPort), cfg.MetricsToken, service)
+		if metricsErr != nil {
+			fatal("failed to start metrics listener", metricsErr)
+		}
+		defer metricsShutdown(context.Background())
+	}
+
+	if len(cfg.WebhookURLs) > 0 {
+		dispatcher, webhookErr := webhook.New(cfg)
+		if webhookErr != nil {
+			fatal("failed to create webhook dispatcher", webhookErr)
+		}
+		if webhookErr = dispatcher.Start(); webhookErr != nil {
+			fatal("failed to start webhook dispatcher", webhookErr)
+		}
+		defer dispatcher.Close()
+	}
+
+	// The watchers publish events, started once the dispatcher subscribed to them
+	service.StartWatchers()
+	defer service.Shutdown()
+
+	go reopenLogsOnHangup()
+
 	stopChan := make(chan os.Signal, 1)
 	signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 
 	// Wait for interrupt
 	<-stopChan
-	log.Println("Shutting down server...")
+	logger.Info("shutting down server")
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err = shutdownFunc(ctx); err != nil {
-		log.Printf("Server shutdown error: %v", err)
+		logger.Error("server shutdown error", "error", err)
 	}
 
-	log.Println("Server gracefully stopped")
+	if err = tracingShutdown(ctx); err != nil {
+		logger.Error("tracing shutdown error", "error", err)
+	}
+
+	logger.Info("server gracefully stopped")
+	nodeLogger.Shutdown()
+}
+
+// fatal logs the error and exits, deferred calls are skipped like with log.Fatal.
+// The remote log sinks are flushed first so the error reaches them.
+func fatal(msg string, err error) {
+	logger.Error(msg, "error", err)
+	nodeLogger.Shutdown()
+	os.Exit(1)
+}
+
+// reopenLogsOnHangup reopens the core log files and the access log file on SIGHUP, after logrotate moved them.
+func reopenLogsOnHangup() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	for range hangup {
+		if err := nodeLogger.ReopenFiles(); err != nil {
+			logger.Warn("failed to reopen log files", "error", err)
+			continue
+		}
+		logger.Info("log files reopened")
+	}
 }
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+)
+
+const (
+	// collectTimeout bounds how long a scrape waits for the core api
+	collectTimeout = 5 * time.Second
+	// onlineCacheTTL is how long the online connections read for a scrape are reused,
+	// the core needs one api call per user for them
+	onlineCacheTTL = 30 * time.Second
+)
+
+func desc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+var (
+	infoDesc = desc("info", "Node and core versions, the value is 1.", "node_version", "core_version")
+	upDesc   = desc("core_up", "Whether the core is started.")
+
+	memTotalDesc     = desc("memory_total_bytes", "Total host memory.")
+	memUsedDesc      = desc("memory_used_bytes", "Used host memory.")
+	cpuCoresDesc     = desc("cpu_cores", "Number of logical cpu cores.")
+	cpuUsageDesc     = desc("cpu_usage_percent", "Host cpu usage.")
+	incomingRateDesc = desc("incoming_bandwidth_bytes_per_second", "Host incoming bandwidth.")
+	outgoingRateDesc = desc("outgoing_bandwidth_bytes_per_second", "Host outgoing bandwidth.")
+
+	coreGoroutinesDesc = desc("core_goroutines", "Goroutines in the core process.")
+	coreGCDesc         = desc("core_gc_total", "Garbage collections in the core process.")
+	coreAllocDesc      = desc("core_alloc_bytes", "Bytes allocated and in use by the core.")
+	coreTotalAllocDesc = desc("core_alloc_bytes_total", "Bytes allocated by the core since start.")
+	coreSysDesc        = desc("core_sys_bytes", "Bytes obtained from the system by the core.")
+	coreMallocsDesc    = desc("core_mallocs_total", "Heap allocations in the core.")
+	coreFreesDesc      = desc("core_frees_total", "Heap frees in the core.")
+	coreLiveDesc       = desc("core_live_objects", "Live heap objects in the core.")
+	corePauseDesc      = desc("core_gc_pause_seconds_total", "Total garbage collection pause of the core.")
+	coreUptimeDesc     = desc("core_uptime_seconds", "Core uptime.")
+
+	// Core counters go back to zero whenever the panel reads them with reset, which Prometheus treats as a counter reset
+	trafficDesc = desc("traffic_bytes_total", "Traffic since the last reset by the panel.", "kind", "name", "direction")
+	onlineDesc  = desc("user_online_connections", "Online connections per user.", "user")
+	onlineTotal = desc("online_users", "Users with at least one online connection.")
+)
+
+// collector reads node and core stats at scrape time so values are never stale,
+// apart from the online connections which are cached for onlineCacheTTL.
+type collector struct {
+	source Source
+	online onlineCache
+}
+
+// onlineCache keeps the online connections per user read from a backend.
+type onlineCache struct {
+	backend backend.Backend
+	read    time.Time
+	users   map[string]int64
+	mu      sync.Mutex
+}
+
+func newCollector(source Source) *collector {
+	return &collector{source: source}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		infoDesc, upDesc,
+		memTotalDesc, memUsedDesc, cpuCoresDesc, cpuUsageDesc, incomingRateDesc, outgoingRateDesc,
+		coreGoroutinesDesc, coreGCDesc, coreAllocDesc, coreTotalAllocDesc, coreSysDesc,
+		coreMallocsDesc, coreFreesDesc, coreLiveDesc, corePauseDesc, coreUptimeDesc,
+		trafficDesc, onlineDesc, onlineTotal,
+	} {
+		ch <- d
+	}
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	info := c.source.BaseInfoResponse()
+	ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, info.GetNodeVersion(), info.GetCoreVersion())
+
+	up := 0.0
+	if info.GetStarted() {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+
+	if stats := c.source.SystemStats(); stats != nil {
+		ch <- prometheus.MustNewConstMetric(memTotalDesc, prometheus.GaugeValue, float64(stats.GetMemTotal()))
+		ch <- prometheus.MustNewConstMetric(memUsedDesc, prometheus.GaugeValue, float64(stats.GetMemUsed()))
+		ch <- prometheus.MustNewConstMetric(cpuCoresDesc, prometheus.GaugeValue, float64(stats.GetCpuCores()))
+		ch <- prometheus.MustNewConstMetric(cpuUsageDesc, prometheus.GaugeValue, stats.GetCpuUsage())
+		ch <- prometheus.MustNewConstMetric(incomingRateDesc, prometheus.GaugeValue, float64(stats.GetIncomingBandwidthSpeed()))
+		ch <- prometheus.MustNewConstMetric(outgoingRateDesc, prometheus.GaugeValue, float64(stats.GetOutgoingBandwidthSpeed()))
+	}
+
+	back := c.source.Backend()
+	if back == nil || !info.GetStarted() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	if stats, err := back.GetSysStats(ctx); err == nil {
+		c.collectCore(ch, stats)
+	}
+
+	var users []string
+	for _, kind := range []struct {
+		name     string
+		statType common.StatType
+	}{
+		{"inbound", common.StatType_Inbounds},
+		{"outbound", common.StatType_Outbounds},
+		{"user", common.StatType_UsersStat},
+	} {
+		// Never reset here, the panel owns the counters
+		stats, err := back.GetStats(ctx, &common.StatRequest{Type: kind.statType})
+		if err != nil {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, stat := range stats.GetStats() {
+			ch <- prometheus.MustNewConstMetric(trafficDesc, prometheus.CounterValue, float64(stat.GetValue()),
+				kind.name, stat.GetName(), stat.GetType())
+			if kind.statType == common.StatType_UsersStat && !seen[stat.GetName()] {
+				seen[stat.GetName()] = true
+				users = append(users, stat.GetName())
+			}
+		}
+	}
+
+	c.collectOnline(ctx, ch, users)
+}
+
+func (c *collector) collectCore(ch chan<- prometheus.Metric, stats *common.BackendStatsResponse) {
+	ch <- prometheus.MustNewConstMetric(coreGoroutinesDesc, prometheus.GaugeValue, float64(stats.GetNumGoroutine()))
+	ch <- prometheus.MustNewConstMetric(coreGCDesc, prometheus.CounterValue, float64(stats.GetNumGc()))
+	ch <- prometheus.MustNewConstMetric(coreAllocDesc, prometheus.GaugeValue, float64(stats.GetAlloc()))
+	ch <- prometheus.MustNewConstMetric(coreTotalAllocDesc, prometheus.CounterValue, float64(stats.GetTotalAlloc()))
+	ch <- prometheus.MustNewConstMetric(coreSysDesc, prometheus.GaugeValue, float64(stats.GetSys()))
+	ch <- prometheus.MustNewConstMetric(coreMallocsDesc, prometheus.CounterValue, float64(stats.GetMallocs()))
+	ch <- prometheus.MustNewConstMetric(coreFreesDesc, prometheus.CounterValue, float64(stats.GetFrees()))
+	ch <- prometheus.MustNewConstMetric(coreLiveDesc, prometheus.GaugeValue, float64(stats.GetLiveObjects()))
+	ch <- prometheus.MustNewConstMetric(corePauseDesc, prometheus.CounterValue, float64(stats.GetPauseTotalNs())/float64(time.Second))
+	ch <- prometheus.MustNewConstMetric(coreUptimeDesc, prometheus.GaugeValue, float64(stats.GetUptime()))
+}
+
+// collectOnline reports online connections for users that have traffic counters,
+// the core has no api listing online users directly.
+func (c *collector) collectOnline(ctx context.Context, ch chan<- prometheus.Metric, users []string) {
+	back := c.source.Backend()
+	if back == nil {
+		return
+	}
+
+	online := c.online.get(ctx, back, users)
+	for user, value := range online {
+		ch <- prometheus.MustNewConstMetric(onlineDesc, prometheus.GaugeValue, float64(value), user)
+	}
+	ch <- prometheus.MustNewConstMetric(onlineTotal, prometheus.GaugeValue, float64(len(online)))
+}
+
+// get returns the users with online connections, read again from back once the cached ones are older
+// than onlineCacheTTL or were read from another backend. Concurrent scrapes wait for a single read.
+func (o *onlineCache) get(ctx context.Context, back backend.Backend, users []string) map[string]int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.backend == back && time.Since(o.read) < onlineCacheTTL {
+		return o.users
+	}
+
+	online := make(map[string]int64)
+	for _, user := range users {
+		stat, err := back.GetUserOnlineStats(ctx, user)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Warn("online stats timed out", "users", len(online))
+				break
+			}
+			continue
+		}
+		if stat.GetValue() > 0 {
+			online[user] = stat.GetValue()
+		}
+	}
+
+	o.backend, o.read, o.users = back, time.Now(), online
+	return online
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+	nodeLogger "github.com/pasarguard/node/logger"
+)
+
+const namespace = "pg_node"
+
+var logger = nodeLogger.For(nodeLogger.ComponentMetrics)
+
+// Source is the part of the controller the metrics are collected from.
+type Source interface {
+	Backend() backend.Backend
+	SystemStats() *common.SystemStatsResponse
+	BaseInfoResponse() *common.BaseInfoResponse
+}
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Duration of api requests by protocol, method and response code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"protocol", "method", "code"})
+
+	eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_total",
+		Help:      "Node events published by type.",
+	}, []string{"type", "backend"})
+
+	coreRestartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "core_restarts_total",
+		Help:      "Core restarts after a failed health check.",
+	}, []string{"backend"})
+)
+
+// ObserveRequest records the duration of a finished api request.
+func ObserveRequest(protocol, method, code string, duration time.Duration) {
+	requestDuration.WithLabelValues(protocol, method, code).Observe(duration.Seconds())
+}
+
+func newRegistry(source Source) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration,
+		eventsTotal,
+		coreRestartsTotal,
+		newCollector(source),
+	)
+	return registry
+}
+
+// countEvents keeps the event counters up to date until ctx is canceled.
+func countEvents(ctx context.Context) {
+	eventCh, release := events.Subscribe(64)
+	defer release()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-eventCh:
+			eventsTotal.WithLabelValues(event.GetType().String(), event.GetBackend()).Inc()
+			if event.GetType() == common.EventType_CORE_RESTARTED {
+				coreRestartsTotal.WithLabelValues(event.GetBackend()).Inc()
+			}
+		}
+	}
+}
+
+func newHandler(source Source, token string) http.Handler {
+	handler := promhttp.HandlerFor(newRegistry(source), promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// StartListener serves /metrics on its own port, separate from the node api.
+// If token is set, scrapes must send it as a bearer token.
+func StartListener(addr, token string, source Source) (func(ctx context.Context) error, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", newHandler(source, token))
+	httpServer := &http.Server{Handler: mux}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go countEvents(ctx)
+
+	go func() {
+		logger.Info("metrics listening", "address", addr)
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server error", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) error {
+		cancel()
+		return httpServer.Shutdown(ctx)
+	}, nil
+}
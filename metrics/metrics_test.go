@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pasarguard/node/backend"
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/events"
+)
+
+// stubSource is a controller without a started backend.
+type stubSource struct{}
+
+func (stubSource) Backend() backend.Backend { return nil }
+
+func (stubSource) SystemStats() *common.SystemStatsResponse {
+	return &common.SystemStatsResponse{MemTotal: 1024, CpuCores: 4}
+}
+
+func (stubSource) BaseInfoResponse() *common.BaseInfoResponse {
+	return &common.BaseInfoResponse{NodeVersion: "0.0.0"}
+}
+
+func scrape(t *testing.T, handler http.Handler, header string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestHandler_ExposesMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go countEvents(ctx)
+	// Give the subscriber a moment to register before publishing
+	time.Sleep(50 * time.Millisecond)
+
+	ObserveRequest("grpc", "GetStats", "OK", 20*time.Millisecond)
+	events.Publish(&common.Event{Type: common.EventType_CORE_RESTARTED, Backend: "xray"})
+
+	handler := newHandler(stubSource{}, "")
+
+	var body string
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(body, `pg_node_core_restarts_total{backend="xray"} 1`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("core restart was not counted:\n%s", body)
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, body = scrape(t, handler, "")
+	}
+
+	for _, want := range []string{
+		`pg_node_request_duration_seconds_count{code="OK",method="GetStats",protocol="grpc"} 1`,
+		`pg_node_info{core_version="",node_version="0.0.0"} 1`,
+		`pg_node_core_up 0`,
+		`pg_node_memory_total_bytes 1024`,
+		`pg_node_cpu_cores 4`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in scrape", want)
+		}
+	}
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	handler := newHandler(stubSource{}, "secret")
+
+	if code, _ := scrape(t, handler, ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", code)
+	}
+	if code, _ := scrape(t, handler, "Bearer wrong"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong token, got %d", code)
+	}
+	if code, _ := scrape(t, handler, "Bearer secret"); code != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", code)
+	}
+}
+
+// onlineBackend is a started backend with two users, it counts the online stats read from it.
+type onlineBackend struct {
+	backend.Backend
+	reads atomic.Int32
+}
+
+func (*onlineBackend) GetSysStats(context.Context) (*common.BackendStatsResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*onlineBackend) GetStats(_ context.Context, req *common.StatRequest) (*common.StatResponse, error) {
+	if req.GetType() != common.StatType_UsersStat {
+		return &common.StatResponse{}, nil
+	}
+	return &common.StatResponse{Stats: []*common.Stat{
+		{Name: "alice", Type: "uplink", Value: 10},
+		{Name: "alice", Type: "downlink", Value: 20},
+		{Name: "bob", Type: "uplink", Value: 5},
+	}}, nil
+}
+
+func (b *onlineBackend) GetUserOnlineStats(_ context.Context, email string) (*common.OnlineStatResponse, error) {
+	b.reads.Add(1)
+	if email == "alice" {
+		return &common.OnlineStatResponse{Name: email, Value: 2}, nil
+	}
+	return &common.OnlineStatResponse{Name: email}, nil
+}
+
+type onlineSource struct {
+	stubSource
+	back *onlineBackend
+}
+
+func (s onlineSource) Backend() backend.Backend { return s.back }
+
+func (onlineSource) BaseInfoResponse() *common.BaseInfoResponse {
+	return &common.BaseInfoResponse{NodeVersion: "0.0.0", Started: true}
+}
+
+func TestHandler_CachesOnlineStats(t *testing.T) {
+	back := &onlineBackend{}
+	handler := newHandler(onlineSource{back: back}, "")
+
+	for range 3 {
+		_, body := scrape(t, handler, "")
+		for _, want := range []string{
+			`pg_node_user_online_connections{user="alice"} 2`,
+			`pg_node_online_users 1`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Fatalf("missing %q in scrape:\n%s", want, body)
+			}
+		}
+	}
+
+	// One read per user, the later scrapes reuse them
+	if reads := back.reads.Load(); reads != 2 {
+		t.Fatalf("expected 2 online stats reads, got %d", reads)
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff computes exponentially growing delays with jitter between Min and Max.
+type Backoff struct {
+	Min     time.Duration
+	Max     time.Duration
+	attempt int
+}
+
+func NewBackoff(min, max time.Duration) *Backoff {
+	return &Backoff{Min: min, Max: max}
+}
+
+// Next returns the delay to wait before the next attempt and advances the attempt counter.
+func (b *Backoff) Next() time.Duration {
+	delay := b.Max
+	if b.attempt < 32 {
+		if d := b.Min << b.attempt; d > 0 && d < b.Max {
+			delay = d
+		}
+	}
+	b.attempt++
+
+	// Jitter in [delay/2, delay) keeps retries spread out without collapsing to zero
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
@@ -11,3 +11,12 @@ func ReadFileAsString(filePath string) (string, error) {
 	}
 	return string(fileBytes), nil
 }
+
+// WriteFileAtomic writes data to a temporary file renamed over path, so a crash never leaves it half written.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"strconv"
 )
 
 func isPortFree(port int) bool {
@@ -24,3 +25,17 @@ func FindFreePort() int {
 	}
 	return apiPort
 }
+
+// SplitHostPort splits an address as printed in core logs, brackets around IPv6 hosts are removed.
+// An address without a valid port is returned as the host with port 0.
+func SplitHostPort(addr string) (string, uint32) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 0
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return host, 0
+	}
+	return host, uint32(port)
+}
@@ -80,3 +80,20 @@ func getBandwidthSpeed() (uint64, uint64, error) {
 	// 5) Return the totals
 	return totalRx, totalTx, nil
 }
+
+// GetHostTraffic returns the total bytes received and sent on all non-loopback interfaces since boot.
+func GetHostTraffic() (uint64, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	for _, c := range counters {
+		if c.Name == "lo" {
+			continue
+		}
+		total += c.BytesRecv + c.BytesSent
+	}
+	return total, nil
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"os"
@@ -52,3 +53,22 @@ func CreateHTTPClient(certPool *x509.CertPool, hostname string) *http.Client {
 		Timeout:   10 * time.Second,
 	}
 }
+
+// CertificateExpiry returns the expiry time of the first certificate in the PEM file.
+func CertificateExpiry(certFile string) (time.Time, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no PEM data found in %s", certFile)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/pasarguard/node/config"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return recorder
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(&config.Config{}, "0.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Panel trace context must be understood even without an exporter
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) == 0 {
+		t.Fatal("no propagator installed")
+	}
+}
+
+func TestStart_ContinuesPanelTrace(t *testing.T) {
+	recorder := setupRecorder(t)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	carrier := propagation.MapCarrier{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	_, span := Start(ctx, "controller.StartBackend")
+	End(span, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Fatalf("span is not part of the panel trace, got trace id %s", got)
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	_, span := Start(context.Background(), "xray.core.Start")
+	End(span, errors.New("failed to start xray"))
+
+	_, span = Start(context.Background(), "xray.core.Stop")
+	End(span, context.Canceled)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) != 1 {
+		t.Fatalf("error was not recorded: %+v", spans[0].Status())
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Fatal("cancellation must not mark the span as failed")
+	}
+}
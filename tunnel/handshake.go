@@ -0,0 +1,188 @@
+package tunnel
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
+)
+
+const (
+	protocolVersion = 1
+	maxFrameSize    = 4096
+	// maxClockSkew bounds how old a hello may be before the panel refuses it
+	maxClockSkew = 5 * time.Minute
+)
+
+// Hello is the first frame the node sends after the TLS connection to the panel is established.
+// The signature proves the node knows the api key without sending it over the wire.
+type Hello struct {
+	Version     int    `json:"version"`
+	NodeVersion string `json:"node_version"`
+	Protocol    string `json:"protocol"`
+	KeyID       string `json:"key_id"`
+	Timestamp   int64  `json:"timestamp"`
+	Nonce       string `json:"nonce"`
+	Signature   string `json:"signature"`
+}
+
+type helloReply struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// KeyID returns a short identifier the panel can use to look up the node's api key.
+func KeyID(apiKey uuid.UUID) string {
+	sum := sha256.Sum256([]byte(apiKey.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+func sign(apiKey uuid.UUID, nonce string, timestamp int64, protocol string) string {
+	mac := hmac.New(sha256.New, []byte(apiKey.String()))
+	mac.Write([]byte(nonce + ":" + strconv.FormatInt(timestamp, 10) + ":" + protocol))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newHello(apiKey uuid.UUID, nodeVersion, protocol string) (*Hello, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	hello := &Hello{
+		Version:     protocolVersion,
+		NodeVersion: nodeVersion,
+		Protocol:    protocol,
+		KeyID:       KeyID(apiKey),
+		Timestamp:   time.Now().Unix(),
+		Nonce:       hex.EncodeToString(nonce),
+	}
+	hello.Signature = sign(apiKey, hello.Nonce, hello.Timestamp, hello.Protocol)
+	return hello, nil
+}
+
+// Verify checks the hello signature and freshness against the given api key.
+func (h *Hello) Verify(apiKey uuid.UUID) error {
+	if h.Version != protocolVersion {
+		return fmt.Errorf("unsupported tunnel protocol version %d", h.Version)
+	}
+
+	skew := time.Since(time.Unix(h.Timestamp, 0))
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return errors.New("hello timestamp is outside the allowed clock skew")
+	}
+
+	expected := sign(apiKey, h.Nonce, h.Timestamp, h.Protocol)
+	if !hmac.Equal([]byte(expected), []byte(h.Signature)) {
+		return errors.New("invalid hello signature")
+	}
+	return nil
+}
+
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFrameSize {
+		return errors.New("handshake frame too large")
+	}
+
+	frame := make([]byte, 2+len(data))
+	binary.BigEndian.PutUint16(frame, uint16(len(data)))
+	copy(frame[2:], data)
+	_, err = w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader, v interface{}) error {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+
+	size := binary.BigEndian.Uint16(header[:])
+	if size > maxFrameSize {
+		return errors.New("handshake frame too large")
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// handshake runs the node side of the handshake over an established connection.
+func handshake(conn net.Conn, apiKey uuid.UUID, nodeVersion, protocol string) error {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return err
+	}
+	defer conn.SetDeadline(time.Time{})
+
+	hello, err := newHello(apiKey, nodeVersion, protocol)
+	if err != nil {
+		return err
+	}
+
+	if err = writeFrame(conn, hello); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	var reply helloReply
+	if err = readFrame(conn, &reply); err != nil {
+		return fmt.Errorf("failed to read hello reply: %w", err)
+	}
+	if !reply.OK {
+		return fmt.Errorf("panel rejected the node: %s", reply.Error)
+	}
+	return nil
+}
+
+// AcceptNode runs the panel side of the handshake on a connection dialed by a node
+// and returns the multiplexed session the panel can open NodeService streams on.
+// lookup resolves the api key for the key id announced by the node.
+func AcceptNode(conn net.Conn, lookup func(keyID string) (uuid.UUID, bool)) (*Hello, *yamux.Session, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return nil, nil, err
+	}
+
+	var hello Hello
+	if err := readFrame(conn, &hello); err != nil {
+		return nil, nil, fmt.Errorf("failed to read hello: %w", err)
+	}
+
+	verifyErr := errors.New("unknown node")
+	if apiKey, ok := lookup(hello.KeyID); ok {
+		verifyErr = hello.Verify(apiKey)
+	}
+
+	if verifyErr != nil {
+		_ = writeFrame(conn, helloReply{OK: false, Error: verifyErr.Error()})
+		return nil, nil, verifyErr
+	}
+
+	if err := writeFrame(conn, helloReply{OK: true}); err != nil {
+		return nil, nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, nil, err
+	}
+
+	session, err := yamux.Client(conn, yamuxConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	return &hello, session, nil
+}
@@ -0,0 +1,217 @@
+package tunnel
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
+
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/controller"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	handshakeTimeout  = 10 * time.Second
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+	// A session that stayed up at least this long resets the reconnect backoff
+	stableSessionDuration = 30 * time.Second
+)
+
+var logger = nodeLogger.For(nodeLogger.ComponentTunnel)
+
+// ServeFunc serves the node api on the listener until it is closed.
+type ServeFunc func(net.Listener) error
+
+// Tunnel keeps a persistent connection from the node to the panel and
+// serves the node api over a multiplexed session on top of it.
+type Tunnel struct {
+	address   string
+	tlsConfig *tls.Config
+	apiKey    uuid.UUID
+	protocol  string
+	serve     ServeFunc
+	backoff   *tools.Backoff
+	session   *yamux.Session
+	started   bool
+	closed    bool
+	stop      chan struct{}
+	done      chan struct{}
+	mu        sync.Mutex
+}
+
+func New(cfg *config.Config, serve ServeFunc) (*Tunnel, error) {
+	if cfg.PanelAddress == "" {
+		return nil, errors.New("panel address is empty")
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tunnel{
+		address:   cfg.PanelAddress,
+		tlsConfig: tlsConfig,
+		apiKey:    cfg.ApiKey,
+		protocol:  cfg.ServiceProtocol,
+		serve:     serve,
+		backoff:   tools.NewBackoff(minReconnectDelay, maxReconnectDelay),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	serverName := cfg.PanelServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(cfg.PanelAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid panel address: %w", err)
+		}
+		serverName = host
+	}
+
+	var certPool *x509.CertPool
+	if cfg.PanelCAFile != "" {
+		pool, err := tools.LoadClientPool(cfg.PanelCAFile)
+		if err != nil {
+			return nil, err
+		}
+		certPool = pool
+	}
+
+	return &tls.Config{
+		RootCAs:    certPool,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+func yamuxConfig() *yamux.Config {
+	cfg := yamux.DefaultConfig()
+	cfg.LogOutput = nil
+	cfg.Logger = slog.NewLogLogger(logger.Handler(), slog.LevelWarn)
+	return cfg
+}
+
+// Start dials the panel in the background and keeps reconnecting with backoff until Close is called.
+func (t *Tunnel) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started || t.closed {
+		return
+	}
+	t.started = true
+	go t.run()
+}
+
+func (t *Tunnel) run() {
+	defer close(t.done)
+
+	for {
+		start := time.Now()
+		err := t.connect()
+		if t.isClosed() {
+			return
+		}
+
+		if err != nil {
+			logger.Warn("panel tunnel error", "panel", t.address, "error", err)
+		} else {
+			logger.Info("panel tunnel closed", "panel", t.address)
+		}
+
+		if time.Since(start) >= stableSessionDuration {
+			t.backoff.Reset()
+		}
+		delay := t.backoff.Next()
+		logger.Info("reconnecting to panel", "panel", t.address, "delay", delay.Round(time.Millisecond))
+
+		select {
+		case <-time.After(delay):
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+func (t *Tunnel) connect() error {
+	dialer := &net.Dialer{Timeout: handshakeTimeout, KeepAlive: 30 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", t.address, t.tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to dial panel: %w", err)
+	}
+
+	if err = handshake(conn, t.apiKey, controller.NodeVersion, t.protocol); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	session, err := yamux.Server(conn, yamuxConfig())
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		_ = session.Close()
+		return nil
+	}
+	t.session = session
+	t.mu.Unlock()
+
+	logger.Info("connected to panel", "panel", t.address)
+
+	err = t.serve(session)
+	_ = session.Close()
+
+	t.mu.Lock()
+	t.session = nil
+	t.mu.Unlock()
+
+	// The listener fails with these once the session is gone, which is a normal disconnect
+	if errors.Is(err, io.EOF) || errors.Is(err, yamux.ErrSessionShutdown) {
+		return nil
+	}
+	return err
+}
+
+func (t *Tunnel) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Close tears down the current session and stops reconnecting.
+func (t *Tunnel) Close() {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.closed = true
+	close(t.stop)
+	session := t.session
+	started := t.started
+	t.mu.Unlock()
+
+	if session != nil {
+		_ = session.Close()
+	}
+	if started {
+		<-t.done
+	}
+}
@@ -0,0 +1,243 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/tools"
+)
+
+// panelStandIn is a minimal panel that accepts node connections over TLS.
+type panelStandIn struct {
+	listener net.Listener
+	apiKey   uuid.UUID
+	sessions chan *yamux.Session
+	errors   chan error
+}
+
+func newPanelStandIn(t *testing.T, apiKey uuid.UUID) (*panelStandIn, string) {
+	t.Helper()
+
+	certPEM, keyPEM := generateCert(t)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "panel_ca.pem")
+	if err = os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &panelStandIn{
+		listener: listener,
+		apiKey:   apiKey,
+		sessions: make(chan *yamux.Session, 4),
+		errors:   make(chan error, 4),
+	}
+	go p.acceptLoop()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return p, caFile
+}
+
+func (p *panelStandIn) acceptLoop() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		_, session, err := AcceptNode(conn, func(keyID string) (uuid.UUID, bool) {
+			return p.apiKey, keyID == KeyID(p.apiKey)
+		})
+		if err != nil {
+			_ = conn.Close()
+			p.errors <- err
+			continue
+		}
+		p.sessions <- session
+	}
+}
+
+func (p *panelStandIn) nextSession(t *testing.T) *yamux.Session {
+	t.Helper()
+	select {
+	case session := <-p.sessions:
+		return session
+	case err := <-p.errors:
+		t.Fatalf("node handshake failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("node did not connect to the panel")
+	}
+	return nil
+}
+
+func generateCert(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func newTestTunnel(t *testing.T, addr, caFile string, apiKey uuid.UUID, serve ServeFunc) *Tunnel {
+	t.Helper()
+
+	tun, err := New(&config.Config{
+		PanelAddress:    addr,
+		PanelCAFile:     caFile,
+		ApiKey:          apiKey,
+		ServiceProtocol: "grpc",
+	}, serve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tun.backoff = tools.NewBackoff(10*time.Millisecond, 50*time.Millisecond)
+	return tun
+}
+
+func checkHealth(t *testing.T, session *yamux.Session) {
+	t.Helper()
+
+	conn, err := grpc.NewClient("passthrough:///node",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return session.Open()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("rpc over tunnel failed: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("unexpected health status: %v", resp.GetStatus())
+	}
+}
+
+func TestTunnel_ServesAndReconnects(t *testing.T) {
+	apiKey := uuid.New()
+	panel, caFile := newPanelStandIn(t, apiKey)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	defer grpcServer.Stop()
+
+	tun := newTestTunnel(t, panel.listener.Addr().String(), caFile, apiKey, grpcServer.Serve)
+	tun.Start()
+	defer tun.Close()
+
+	session := panel.nextSession(t)
+	checkHealth(t, session)
+
+	// Drop the connection from the panel side, the node must dial back on its own
+	_ = session.Close()
+
+	session = panel.nextSession(t)
+	checkHealth(t, session)
+}
+
+func TestTunnel_RejectsWrongKey(t *testing.T) {
+	panel, caFile := newPanelStandIn(t, uuid.New())
+
+	tun := newTestTunnel(t, panel.listener.Addr().String(), caFile, uuid.New(), func(net.Listener) error {
+		t.Error("node must not serve on a rejected connection")
+		return nil
+	})
+	tun.Start()
+	defer tun.Close()
+
+	select {
+	case err := <-panel.errors:
+		if err == nil {
+			t.Fatal("expected handshake error")
+		}
+	case <-panel.sessions:
+		t.Fatal("panel accepted a node with a wrong api key")
+	case <-time.After(5 * time.Second):
+		t.Fatal("node did not connect to the panel")
+	}
+}
+
+func TestHello_Verify(t *testing.T) {
+	apiKey := uuid.New()
+
+	hello, err := newHello(apiKey, "0.0.0", "grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = hello.Verify(apiKey); err != nil {
+		t.Fatalf("valid hello rejected: %v", err)
+	}
+
+	tampered := *hello
+	tampered.Protocol = "rest"
+	if err = tampered.Verify(apiKey); err == nil {
+		t.Fatal("tampered hello accepted")
+	}
+
+	stale := *hello
+	stale.Timestamp = time.Now().Add(-time.Hour).Unix()
+	stale.Signature = sign(apiKey, stale.Nonce, stale.Timestamp, stale.Protocol)
+	if err = stale.Verify(apiKey); err == nil {
+		t.Fatal("stale hello accepted")
+	}
+}
@@ -0,0 +1,436 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/controller"
+	"github.com/pasarguard/node/events"
+	nodeLogger "github.com/pasarguard/node/logger"
+	"github.com/pasarguard/node/tools"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	minRetryDelay  = 5 * time.Second
+	maxRetryDelay  = 10 * time.Minute
+	// Deliveries older than this are dropped instead of retried
+	maxDeliveryAge = 24 * time.Hour
+	// maxConcurrentDeliveries bounds the requests in flight, so a hanging url doesn't hold up the others
+	maxConcurrentDeliveries = 4
+	// eventBuffer is how many events can wait to be stored in the outbox
+	eventBuffer = 64
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	IDHeader        = "X-Webhook-Id"
+	EventHeader     = "X-Webhook-Event"
+)
+
+var logger = nodeLogger.For(nodeLogger.ComponentWebhook)
+
+var defaultEventTypes = []common.EventType{
+	common.EventType_CORE_CRASH_LOOP,
+	common.EventType_CORE_RESTART_FAILED,
+	common.EventType_CERT_EXPIRING,
+	common.EventType_CERT_EXPIRED,
+	common.EventType_BANDWIDTH_QUOTA_REACHED,
+	common.EventType_CONFIG_ROLLBACK,
+}
+
+// Sign returns the signature sent in the X-Webhook-Signature header,
+// receivers recompute it over the timestamp header and the raw body.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// delivery is a pending notification for a single url, persisted in the outbox until it is delivered.
+type delivery struct {
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"attempts"`
+
+	next    time.Time
+	backoff *tools.Backoff
+}
+
+// Dispatcher posts signed JSON notifications for selected node events to the configured urls.
+// Pending notifications are kept in an outbox directory so they survive restarts.
+type Dispatcher struct {
+	urls          []string
+	secret        []byte
+	types         map[common.EventType]bool
+	outbox        string
+	client        *http.Client
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+	pending       []*delivery
+	wake          chan struct{}
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+	started       bool
+	mu            sync.Mutex
+}
+
+func New(cfg *config.Config) (*Dispatcher, error) {
+	if len(cfg.WebhookURLs) == 0 {
+		return nil, errors.New("no webhook url configured")
+	}
+
+	types := make(map[common.EventType]bool)
+	if len(cfg.WebhookEvents) == 0 {
+		for _, eventType := range defaultEventTypes {
+			types[eventType] = true
+		}
+	}
+	for _, name := range cfg.WebhookEvents {
+		value, ok := common.EventType_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown webhook event type: %s", name)
+		}
+		types[common.EventType(value)] = true
+	}
+
+	secret := cfg.WebhookSecret
+	if secret == "" {
+		secret = cfg.ApiKey.String()
+	}
+
+	if err := os.MkdirAll(cfg.WebhookOutboxPath, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create webhook outbox: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		urls:          cfg.WebhookURLs,
+		secret:        []byte(secret),
+		types:         types,
+		outbox:        cfg.WebhookOutboxPath,
+		client:        &http.Client{Timeout: requestTimeout},
+		minRetryDelay: minRetryDelay,
+		maxRetryDelay: maxRetryDelay,
+		wake:          make(chan struct{}, 1),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// Start resumes the deliveries left in the outbox and begins forwarding new events.
+func (d *Dispatcher) Start() error {
+	if err := d.loadOutbox(); err != nil {
+		return err
+	}
+
+	eventCh, release := events.Subscribe(eventBuffer)
+
+	d.mu.Lock()
+	d.started = true
+	d.mu.Unlock()
+
+	var running sync.WaitGroup
+	running.Add(2)
+	go func() {
+		defer running.Done()
+		defer release()
+		d.run(eventCh)
+	}()
+	go func() {
+		defer running.Done()
+		d.deliver()
+	}()
+	go func() {
+		running.Wait()
+		close(d.done)
+	}()
+	return nil
+}
+
+// Close stops delivering, undelivered notifications stay in the outbox.
+func (d *Dispatcher) Close() {
+	d.cancel()
+
+	d.mu.Lock()
+	started := d.started
+	d.mu.Unlock()
+
+	if started {
+		<-d.done
+	}
+}
+
+// run stores the selected events in the outbox, deliveries never hold it up so the bus doesn't drop events.
+func (d *Dispatcher) run(eventCh <-chan *common.Event) {
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case event := <-eventCh:
+			if d.types[event.GetType()] {
+				d.enqueue(event)
+			}
+		}
+	}
+}
+
+// deliver posts the deliveries of the outbox once they are due.
+func (d *Dispatcher) deliver() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+
+		d.deliverDue()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d.nextWait())
+	}
+}
+
+// enqueue stores one delivery per url in the outbox before scheduling it.
+func (d *Dispatcher) enqueue(event *common.Event) {
+	payload, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(event)
+	if err != nil {
+		logger.Error("failed to encode webhook payload", "error", err)
+		return
+	}
+
+	for _, url := range d.urls {
+		item := &delivery{
+			ID:        uuid.NewString(),
+			URL:       url,
+			EventType: event.GetType().String(),
+			Payload:   payload,
+			CreatedAt: time.Now(),
+		}
+		if err = d.save(item); err != nil {
+			logger.Error("failed to store webhook in outbox", "error", err)
+		}
+		d.schedule(item, time.Now())
+	}
+}
+
+func (d *Dispatcher) schedule(item *delivery, next time.Time) {
+	item.next = next
+	item.backoff = tools.NewBackoff(d.minRetryDelay, d.maxRetryDelay)
+
+	d.mu.Lock()
+	d.pending = append(d.pending, item)
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverDue attempts the due deliveries, at most maxConcurrentDeliveries at once, and waits for them.
+func (d *Dispatcher) deliverDue() {
+	now := time.Now()
+
+	d.mu.Lock()
+	var due []*delivery
+	for _, item := range d.pending {
+		if !item.next.After(now) {
+			due = append(due, item)
+		}
+	}
+	d.mu.Unlock()
+
+	var attempts sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentDeliveries)
+	for _, item := range due {
+		select {
+		case <-d.ctx.Done():
+			attempts.Wait()
+			return
+		case slots <- struct{}{}:
+		}
+
+		attempts.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				attempts.Done()
+			}()
+			d.attempt(item)
+		}()
+	}
+	attempts.Wait()
+}
+
+func (d *Dispatcher) attempt(item *delivery) {
+	item.Attempts++
+	err := d.post(item)
+	if err == nil {
+		d.finish(item)
+		return
+	}
+
+	var permanent *permanentError
+	switch {
+	case errors.As(err, &permanent):
+		logger.Warn("webhook rejected, dropping it", "event", item.EventType, "url", item.URL, "error", err)
+		d.finish(item)
+	case time.Since(item.CreatedAt) >= maxDeliveryAge:
+		logger.Warn("webhook expired", "event", item.EventType, "url", item.URL, "attempts", item.Attempts, "error", err)
+		d.finish(item)
+	default:
+		delay := item.backoff.Next()
+		item.next = time.Now().Add(delay)
+		logger.Info("webhook failed, retrying", "event", item.EventType, "url", item.URL, "delay", delay.Round(time.Millisecond), "error", err)
+		if err = d.save(item); err != nil {
+			logger.Error("failed to update webhook outbox", "error", err)
+		}
+	}
+}
+
+// permanentError marks failures that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (d *Dispatcher) post(item *delivery) error {
+	ctx, cancel := context.WithTimeout(d.ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, item.URL, bytes.NewReader(item.Payload))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pasarguard-node/"+controller.NodeVersion)
+	req.Header.Set(IDHeader, item.ID)
+	req.Header.Set(EventHeader, item.EventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(d.secret, timestamp, item.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	// Client errors won't go away by retrying, except for timeouts and rate limits
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return &permanentError{err: fmt.Errorf("unexpected status code %d", resp.StatusCode)}
+	default:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+}
+
+func (d *Dispatcher) finish(item *delivery) {
+	d.mu.Lock()
+	d.pending = slices.DeleteFunc(d.pending, func(p *delivery) bool { return p == item })
+	d.mu.Unlock()
+
+	if err := os.Remove(d.path(item)); err != nil && !os.IsNotExist(err) {
+		logger.Error("failed to remove webhook from outbox", "error", err)
+	}
+}
+
+func (d *Dispatcher) nextWait() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	wait := d.maxRetryDelay
+	for _, item := range d.pending {
+		if until := time.Until(item.next); until < wait {
+			wait = until
+		}
+	}
+	return max(wait, 0)
+}
+
+func (d *Dispatcher) path(item *delivery) string {
+	return filepath.Join(d.outbox, item.ID+".json")
+}
+
+func (d *Dispatcher) save(item *delivery) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	return tools.WriteFileAtomic(d.path(item), data, 0o600)
+}
+
+func (d *Dispatcher) loadOutbox() error {
+	files, err := filepath.Glob(filepath.Join(d.outbox, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		item := &delivery{}
+		if err = json.Unmarshal(data, item); err != nil || item.ID == "" {
+			logger.Warn("dropping invalid webhook outbox entry", "file", filepath.Base(file))
+			_ = os.Remove(file)
+			continue
+		}
+		if !slices.Contains(d.urls, item.URL) {
+			logger.Warn("dropping webhook, url is no longer configured", "url", item.URL)
+			_ = os.Remove(file)
+			continue
+		}
+
+		d.schedule(item, time.Now())
+	}
+
+	if len(files) > 0 {
+		logger.Info("resuming webhooks from outbox", "count", len(d.pending))
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pasarguard/node/common"
+	"github.com/pasarguard/node/config"
+	"github.com/pasarguard/node/events"
+)
+
+const testSecret = "test-secret"
+
+// receiver is a local stand-in for a webhook endpoint that checks signatures.
+type receiver struct {
+	server   *httptest.Server
+	failures atomic.Int32
+	received chan map[string]interface{}
+	invalid  chan string
+}
+
+func newReceiver(t *testing.T, failures int32) *receiver {
+	t.Helper()
+
+	r := &receiver{
+		received: make(chan map[string]interface{}, 8),
+		invalid:  make(chan string, 8),
+	}
+	r.failures.Store(failures)
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		timestamp, _ := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+		if req.Header.Get(SignatureHeader) != Sign([]byte(testSecret), timestamp, body) {
+			r.invalid <- "signature mismatch"
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			r.invalid <- err.Error()
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.received <- payload
+	}))
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *receiver) next(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case payload := <-r.received:
+		return payload
+	case reason := <-r.invalid:
+		t.Fatalf("invalid webhook: %s", reason)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	return nil
+}
+
+func newTestDispatcher(t *testing.T, url, outbox string) *Dispatcher {
+	t.Helper()
+
+	d, err := New(&config.Config{
+		ApiKey:            uuid.New(),
+		WebhookURLs:       []string{url},
+		WebhookSecret:     testSecret,
+		WebhookOutboxPath: outbox,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.minRetryDelay = 10 * time.Millisecond
+	d.maxRetryDelay = 50 * time.Millisecond
+	return d
+}
+
+func outboxSize(t *testing.T, outbox string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(outbox, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestDispatcher_DeliversSelectedEvents(t *testing.T) {
+	r := newReceiver(t, 0)
+	d := newTestDispatcher(t, r.server.URL, t.TempDir())
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	events.Publish(&common.Event{Type: common.EventType_CORE_STARTED, Message: "not selected"})
+	events.Publish(&common.Event{Type: common.EventType_CORE_CRASH_LOOP, Message: "crash loop"})
+
+	payload := r.next(t)
+	if payload["type"] != common.EventType_CORE_CRASH_LOOP.String() || payload["message"] != "crash loop" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+
+	select {
+	case payload = <-r.received:
+		t.Fatalf("unselected event was delivered: %v", payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	r := newReceiver(t, 2)
+	outbox := t.TempDir()
+	d := newTestDispatcher(t, r.server.URL, outbox)
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	events.Publish(&common.Event{Type: common.EventType_CORE_RESTART_FAILED})
+	r.next(t)
+
+	// The outbox entry is removed right after the successful attempt
+	deadline := time.Now().Add(time.Second)
+	for outboxSize(t, outbox) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("delivered webhook is still in the outbox")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDispatcher_ResumesOutbox(t *testing.T) {
+	outbox := t.TempDir()
+
+	// The endpoint is down, the notification must stay in the outbox
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	d := newTestDispatcher(t, down.URL, outbox)
+	d.enqueue(&common.Event{Type: common.EventType_CERT_EXPIRING, Message: "expiring"})
+	d.deliverDue()
+	if size := outboxSize(t, outbox); size != 1 {
+		t.Fatalf("expected 1 webhook in outbox, got %d", size)
+	}
+
+	// Pretend the node restarted with a working endpoint at the same url
+	r := newReceiver(t, 0)
+	rewriteOutboxURL(t, outbox, r.server.URL)
+
+	d = newTestDispatcher(t, r.server.URL, outbox)
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if payload := r.next(t); payload["message"] != "expiring" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestDispatcher_HangingURLKeepsIntake(t *testing.T) {
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(release) })
+
+	outbox := t.TempDir()
+	d := newTestDispatcher(t, hanging.URL, outbox)
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// More events than the subscription buffers while every delivery hangs
+	const published = 2 * eventBuffer
+	for range published {
+		events.Publish(&common.Event{Type: common.EventType_CORE_CRASH_LOOP})
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for outboxSize(t, outbox) != published {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d webhooks in outbox, got %d", published, outboxSize(t, outbox))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func rewriteOutboxURL(t *testing.T, outbox, url string) {
+	t.Helper()
+
+	files, _ := filepath.Glob(filepath.Join(outbox, "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item := &delivery{}
+		if err = json.Unmarshal(data, item); err != nil {
+			t.Fatal(err)
+		}
+		item.URL = url
+		if data, err = json.Marshal(item); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNew_UnknownEventType(t *testing.T) {
+	_, err := New(&config.Config{
+		WebhookURLs:       []string{"http://127.0.0.1"},
+		WebhookEvents:     []string{"NOT_AN_EVENT"},
+		WebhookOutboxPath: t.TempDir(),
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown event type")
+	}
+}